@@ -1,11 +1,18 @@
 package mamba
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/base-go/mamba/pkg/spinner"
+	"github.com/base-go/mamba/pkg/style"
+	"github.com/charmbracelet/x/term"
 	"github.com/spf13/pflag"
 )
 
@@ -32,6 +39,12 @@ type Command struct {
 	// Aliases is an array of aliases that can be used instead of the first word in Use
 	Aliases []string
 
+	// SuggestFor lists words that are not aliases but should lead to this
+	// command. An unmatched word listed by exactly one sibling runs that
+	// command; otherwise the commands listing it are suggested first. Unlike
+	// aliases, these words are not shown in help.
+	SuggestFor []string
+
 	// Short is the short description shown in the 'help' output
 	Short string
 
@@ -48,30 +61,48 @@ type Command struct {
 	// RunE is the function to call when this command is executed, with error handling
 	RunE func(cmd *Command, args []string) error
 
+	// RunContextE is like RunE but receives the command's context.
+	// It takes precedence over RunE and Run.
+	RunContextE func(ctx context.Context, cmd *Command, args []string) error
+
 	// PreRun is called before Run
 	PreRun func(cmd *Command, args []string)
 
 	// PreRunE is called before RunE, with error handling
 	PreRunE func(cmd *Command, args []string) error
 
+	// PreRunContextE is like PreRunE but receives the command's context
+	PreRunContextE func(ctx context.Context, cmd *Command, args []string) error
+
 	// PostRun is called after Run
 	PostRun func(cmd *Command, args []string)
 
 	// PostRunE is called after RunE, with error handling
 	PostRunE func(cmd *Command, args []string) error
 
+	// PostRunContextE is like PostRunE but receives the command's context
+	PostRunContextE func(ctx context.Context, cmd *Command, args []string) error
+
 	// PersistentPreRun is called before PreRun and inherited by children
 	PersistentPreRun func(cmd *Command, args []string)
 
 	// PersistentPreRunE is called before PreRunE and inherited by children
 	PersistentPreRunE func(cmd *Command, args []string) error
 
+	// PersistentPreRunContextE is like PersistentPreRunE but receives the
+	// command's context
+	PersistentPreRunContextE func(ctx context.Context, cmd *Command, args []string) error
+
 	// PersistentPostRun is called after PostRun and inherited by children
 	PersistentPostRun func(cmd *Command, args []string)
 
 	// PersistentPostRunE is called after PostRunE and inherited by children
 	PersistentPostRunE func(cmd *Command, args []string) error
 
+	// PersistentPostRunContextE is like PersistentPostRunE but receives the
+	// command's context
+	PersistentPostRunContextE func(ctx context.Context, cmd *Command, args []string) error
+
 	// SilenceErrors prevents error messages from being displayed
 	SilenceErrors bool
 
@@ -81,12 +112,66 @@ type Command struct {
 	// DisableFlagParsing disables flag parsing
 	DisableFlagParsing bool
 
+	// SubcommandRequired makes invoking a command that has subcommands but
+	// no run function an error instead of showing its help
+	SubcommandRequired bool
+
+	// ReadArgsFromStdin reads whitespace-separated positional arguments from
+	// InOrStdin when none are given and input is piped rather than a
+	// terminal, as in "ls *.txt | myapp process"
+	ReadArgsFromStdin bool
+
+	// TraverseChildren parses the flags of each command on the way to the
+	// subcommand, so parent flags may come before the subcommand name, as in
+	// "myapp --verbose sub". Only read on the root command.
+	TraverseChildren bool
+
+	// ChainPreRun runs the PreRun hooks of every ancestor, root first, before
+	// the executed command's own, and their PostRun hooks in reverse after
+	// it. Persistent hooks still run outermost: persistent pre-run hooks
+	// before the chained PreRuns, persistent post-run hooks after the
+	// chained PostRuns. Only read on the root command.
+	ChainPreRun bool
+
 	// DisableAutoGenTag prevents auto-generation tag in help
 	DisableAutoGenTag bool
 
+	// DisableHelpFlag prevents the automatic -h/--help flag from being added
+	// to this command and its subcommands, so it can be managed manually
+	DisableHelpFlag bool
+
+	// DisableAutoHelpCommand prevents the automatic "help" subcommand from
+	// being added. Only read on the root command.
+	DisableAutoHelpCommand bool
+
+	// HiddenAutoHelpCommand keeps the automatic "help" subcommand out of help
+	// while leaving it runnable. Only read on the root command.
+	HiddenAutoHelpCommand bool
+
+	// CompletionOptions controls the automatic "completion" subcommand. Only
+	// read on the root command.
+	CompletionOptions CompletionOptions
+
 	// Hidden hides this command from help output
 	Hidden bool
 
+	// RecoverPanics recovers panics in this command and its subcommands,
+	// printing a styled error instead of a stack trace. The stack is shown
+	// when a --debug flag is set.
+	RecoverPanics bool
+
+	// SuggestionsMinimumDistance is the maximum edit distance for a
+	// subcommand to be suggested for an unknown command (default: 2)
+	SuggestionsMinimumDistance int
+
+	// DisableSuggestions disables "did you mean" suggestions for unknown
+	// subcommands of this command and its children
+	DisableSuggestions bool
+
+	// GroupID is the ID of the parent's group this command is listed under
+	// in help output (see AddGroup)
+	GroupID string
+
 	// Args defines expected arguments
 	Args PositionalArgs
 
@@ -96,12 +181,54 @@ type Command struct {
 	// ValidArgsFunction is an optional function for custom argument completion
 	ValidArgsFunction func(cmd *Command, args []string, toComplete string) ([]string, error)
 
+	// CompletionCacheTTL, when positive, reuses the results of
+	// ValidArgsFunction and flag completion functions for identical input
+	// within the same process for this long. Subcommands inherit it.
+	CompletionCacheTTL time.Duration
+
+	// Deprecated marks the command as deprecated. The message is shown as a
+	// warning whenever the command runs, e.g. `use "new" instead`.
+	Deprecated string
+
 	// Version is the version for this command
 	Version string
 
+	// OutputFormat selects how PrintData renders results (text, json, or
+	// yaml). Setting it on the root registers a persistent --output/-o flag.
+	OutputFormat OutputFormat
+
+	// EnableDryRun registers a persistent --dry-run flag when set on the
+	// root; commands read it with DryRun
+	EnableDryRun bool
+
+	// EnableQuiet registers a persistent --quiet/-q flag when set on the
+	// root; commands read it with Quiet
+	EnableQuiet bool
+
+	// EnableVerbosity registers a persistent --verbose/-v count flag when set
+	// on the root; commands read the level with Verbosity
+	EnableVerbosity bool
+
 	// commands is the list of subcommands
 	commands []*Command
 
+	// groups is the list of groups subcommands can be listed under
+	groups []*Group
+
+	// cleanup is set by SetCleanup. onInterrupt runs the cleanup of the
+	// executing command when it outlasts an interrupt.
+	cleanup     func()
+	onInterrupt func()
+	cleanupMu   sync.Mutex
+
+	// completionCache holds completion results while CompletionCacheTTL
+	// applies, keyed by the completion input
+	completionCache   map[string]cachedCompletion
+	completionCacheMu sync.Mutex
+
+	// flagCompletionFuncs provide completions for flag values, keyed by flag name
+	flagCompletionFuncs map[string]func(cmd *Command, args []string, toComplete string) ([]string, error)
+
 	// parent is a parent command for this command
 	parent *Command
 
@@ -124,10 +251,14 @@ type Command struct {
 	errOutput io.Writer
 
 	// ctx holds context for the command execution
-	ctx interface{}
+	ctx context.Context
+
+	// args overrides os.Args[1:] when set with SetArgs
+	args []string
 
 	// Modern terminal features
-	// EnableColors enables colored output (default: auto-detect)
+	// EnableColors enables colored output (default: inherited from the
+	// parent, then auto-detect)
 	EnableColors *bool
 
 	// EnableInteractive enables interactive prompts
@@ -135,6 +266,47 @@ type Command struct {
 
 	// ShowSpinner enables loading spinners
 	ShowSpinner bool
+
+	// versionTemplate is the template used to print the version
+	versionTemplate string
+
+	// theme overrides the style theme for this command and its subcommands
+	theme *style.Theme
+
+	// executionHook observes the run phase of this command and its subcommands
+	executionHook ExecutionHook
+
+	// flagErrorFunc rewrites flag parse errors for this command and its subcommands
+	flagErrorFunc func(*Command, error) error
+
+	// helpWidthOverride is the width help is wrapped to, set with SetHelpWidth
+	helpWidthOverride int
+
+	// config holds flag values loaded with BindConfig from configPath
+	config     map[string]interface{}
+	configPath string
+
+	// envBindings maps flag names to environment variables, set with BindEnv
+	envBindings map[string]string
+
+	// envPrefix binds every flag to PREFIX_FLAG_NAME, set with SetEnvPrefix
+	envPrefix string
+
+	// boundFlags holds the flags set from an environment variable or config
+	// file in the current run, which count as set like flags on the command
+	// line
+	boundFlags map[string]bool
+
+	// helpCommand replaces the automatic "help" subcommand, set with
+	// SetHelpCommand
+	helpCommand *Command
+
+	// styledErrors prints errors with PrintError instead of plainly, set on
+	// the root by ExecuteAndExit
+	styledErrors bool
+
+	// globNormFunc normalizes flag names of this command and its subcommands
+	globNormFunc func(f *pflag.FlagSet, name string) pflag.NormalizedName
 }
 
 // PositionalArgs defines a validation function for positional arguments.
@@ -155,7 +327,7 @@ var (
 	// NoArgs returns an error if any args are provided
 	NoArgs = func(cmd *Command, args []string) error {
 		if len(args) > 0 {
-			return fmt.Errorf("unknown command %q for %q", args[0], cmd.Use)
+			return &UnknownCommandError{Name: args[0], Command: cmd.Use}
 		}
 		return nil
 	}
@@ -169,7 +341,7 @@ var (
 	MinimumNArgs = func(n int) PositionalArgs {
 		return func(cmd *Command, args []string) error {
 			if len(args) < n {
-				return fmt.Errorf("requires at least %d arg(s), only received %d", n, len(args))
+				return &ArgCountError{Min: n, Max: -1, Got: len(args), kind: argCountMinimum}
 			}
 			return nil
 		}
@@ -179,7 +351,7 @@ var (
 	MaximumNArgs = func(n int) PositionalArgs {
 		return func(cmd *Command, args []string) error {
 			if len(args) > n {
-				return fmt.Errorf("accepts at most %d arg(s), received %d", n, len(args))
+				return &ArgCountError{Max: n, Got: len(args), kind: argCountMaximum}
 			}
 			return nil
 		}
@@ -189,7 +361,7 @@ var (
 	ExactArgs = func(n int) PositionalArgs {
 		return func(cmd *Command, args []string) error {
 			if len(args) != n {
-				return fmt.Errorf("accepts %d arg(s), received %d", n, len(args))
+				return &ArgCountError{Min: n, Max: n, Got: len(args), kind: argCountExact}
 			}
 			return nil
 		}
@@ -199,35 +371,132 @@ var (
 	RangeArgs = func(min, max int) PositionalArgs {
 		return func(cmd *Command, args []string) error {
 			if len(args) < min || len(args) > max {
-				return fmt.Errorf("accepts between %d and %d arg(s), received %d", min, max, len(args))
+				return &ArgCountError{Min: min, Max: max, Got: len(args), kind: argCountRange}
+			}
+			return nil
+		}
+	}
+
+	// OnlyValidArgs returns an error if any arg is not in the command's ValidArgs
+	OnlyValidArgs = func(cmd *Command, args []string) error {
+		for _, arg := range args {
+			if !slices.Contains(cmd.ValidArgs, arg) {
+				return &InvalidArgError{Arg: arg, Command: cmd.CommandPath()}
+			}
+		}
+		return nil
+	}
+
+	// ExactValidArgs returns an error if there are not exactly N args or any
+	// arg is not in the command's ValidArgs
+	ExactValidArgs = func(n int) PositionalArgs {
+		return MatchAll(ExactArgs(n), OnlyValidArgs)
+	}
+
+	// MatchAll returns a validator that runs each validator in turn and
+	// returns the first error, such as MatchAll(ExactArgs(1), OnlyValidArgs)
+	MatchAll = func(validators ...PositionalArgs) PositionalArgs {
+		return func(cmd *Command, args []string) error {
+			for _, validate := range validators {
+				if err := validate(cmd, args); err != nil {
+					return err
+				}
 			}
 			return nil
 		}
 	}
 )
 
+// ValidateArgs validates args with the command's Args validator. Without one,
+// args must be in ValidArgs when it is set.
+func (c *Command) ValidateArgs(args []string) error {
+	if c.Args != nil {
+		return c.Args(c, args)
+	}
+	if len(c.ValidArgs) > 0 {
+		return OnlyValidArgs(c, args)
+	}
+	return nil
+}
+
 // Execute runs the command
 func (c *Command) Execute() error {
-	return c.ExecuteContext(nil)
+	return c.ExecuteContext(context.Background())
+}
+
+// ExecuteC runs the command like Execute and also returns the subcommand
+// that was executed, which is set even when it fails
+func (c *Command) ExecuteC() (*Command, error) {
+	return c.ExecuteContextC(context.Background())
 }
 
-// ExecuteContext runs the command with context
-func (c *Command) ExecuteContext(ctx interface{}) error {
+// ExecuteContext runs the command with context.
+// The context is available to the executed command (and its hooks) via Context()
+// and is canceled when the process receives SIGINT or SIGTERM.
+func (c *Command) ExecuteContext(ctx context.Context) error {
+	_, err := c.ExecuteContextC(ctx)
+	return err
+}
+
+// ExecuteContextC runs the command with context like ExecuteContext and also
+// returns the subcommand that was executed
+func (c *Command) ExecuteContextC(ctx context.Context) (*Command, error) {
+	ctx, stop := notifyContext(ctx, c.expireInterrupted)
+	defer stop()
 	c.ctx = ctx
 
-	args := os.Args[1:]
-	return c.execute(args)
+	args := c.args
+	if args == nil {
+		args = os.Args[1:]
+	}
+	return c.executeC(args)
+}
+
+// SetArgs sets the arguments used by Execute instead of os.Args[1:], for
+// tests and programs that embed the CLI. Passing nil restores os.Args.
+func (c *Command) SetArgs(args []string) {
+	c.args = args
 }
 
 func (c *Command) execute(args []string) error {
+	_, err := c.executeC(args)
+	return err
+}
+
+// executeC runs the command found for args and returns it with any error
+func (c *Command) executeC(args []string) (cmd *Command, err error) {
+	if !c.HasParent() {
+		c.initDefaults()
+		// Completion requests from the generated shell scripts, which also
+		// offer the default flags and subcommands
+		if len(args) > 0 && args[0] == compRequestCmd {
+			return c, c.runCompletionRequest(args[1:])
+		}
+	}
+
 	// Find the command to execute first (before parsing flags)
-	cmd, cmdArgs, err := c.Find(args)
+	find := c.Find
+	if c.TraverseChildren {
+		find = c.Traverse
+	}
+	cmd, cmdArgs, err := find(args)
 	if err != nil {
-		return err
+		return cmd, cmd.reportError(err)
+	}
+
+	if cmd.recoverPanicsEnabled() {
+		defer cmd.recoverPanic(&err)
+	}
+
+	// Subcommands inherit the context of this run; a context kept from an
+	// earlier run is canceled once that run ends
+	if cmd != c {
+		cmd.ctx = c.ctx
 	}
 
 	// Initialize help flag for the found command
-	cmd.initDefaultHelpFlag()
+	cmd.InitDefaultHelpFlag()
+	cmd.InitDefaultVersionFlag()
 
 	// Parse flags on the found command
 	if !cmd.DisableFlagParsing {
@@ -235,106 +504,237 @@ func (c *Command) execute(args []string) error {
 			// Check if it's a help request from pflag
 			if err == pflag.ErrHelp {
 				cmd.Help()
-				return nil
+				return cmd, nil
 			}
-			return err
+			return cmd, cmd.reportError(err)
 		}
 		cmdArgs = cmd.Flags().Args()
+		cmd.warnDeprecatedFlags()
+	}
+
+	// Spinners and progress bars are package-level, so quiet mode is
+	// switched on for them for the duration of the command
+	if cmd.Quiet() {
+		spinner.SetQuiet(true)
+		defer spinner.SetQuiet(false)
 	}
 
 	// Check if help was requested after parsing
-	if cmd.helpFlagSet() {
+	if !cmd.helpFlagDisabled() && cmd.helpFlagSet() {
 		cmd.Help()
-		return nil
+		return cmd, nil
 	}
 
-	// Validate arguments
-	if cmd.Args != nil {
-		if err := cmd.Args(cmd, cmdArgs); err != nil {
-			return err
+	// Print the version if requested
+	if cmd.versionFlagSet() {
+		return cmd, cmd.printVersion()
+	}
+
+	// Commands without a run function, such as those that only group
+	// subcommands, show help when invoked, or fail with SubcommandRequired
+	if !cmd.Runnable() {
+		if cmd.SubcommandRequired && cmd.HasSubCommands() {
+			return cmd, cmd.reportError(fmt.Errorf("%q requires a subcommand", cmd.CommandPath()))
+		}
+		cmd.Help()
+		return cmd, nil
+	}
+
+	// OnInitialize callbacks see the parsed flags; OnFinalize callbacks and
+	// cleanups run after the command, even when it fails or is interrupted
+	runInitializers()
+	finish := cmd.finisher(cmdArgs)
+	defer finish(false)
+	c.setOnInterrupt(func() { finish(true) })
+	defer c.setOnInterrupt(nil)
+
+	// Fill flags not set on the command line from bound config files
+	if err := cmd.applyFlagBindings(); err != nil {
+		return cmd, cmd.reportError(err)
+	}
+
+	// Warn about deprecated commands, which still run normally
+	if cmd.Deprecated != "" {
+		fmt.Fprintln(cmd.ErrOrStderr(), cmd.render(cmd.ErrOrStderr(), cmd.Theme().Warning(fmt.Sprintf("Command %q is deprecated: %s", cmd.Name(), cmd.Deprecated))))
+	}
+
+	// Take positional arguments from piped input
+	if cmd.ReadArgsFromStdin && len(cmdArgs) == 0 && !isInputTerminal(cmd.InOrStdin()) {
+		stdinArgs, err := readArgs(cmd.InOrStdin())
+		if err != nil {
+			return cmd, cmd.reportError(err)
 		}
+		cmdArgs = append(cmdArgs, stdinArgs...)
+	}
+
+	// Validate arguments
+	if err := cmd.ValidateArgs(cmdArgs); err != nil {
+		return cmd, cmd.reportError(err)
 	}
 
 	// Execute persistent pre-run
 	if err := cmd.executePersistentPreRun(cmdArgs); err != nil {
-		return err
+		return cmd, cmd.reportError(err)
 	}
 
 	// Execute pre-run
 	if err := cmd.executePreRun(cmdArgs); err != nil {
-		return err
+		return cmd, cmd.reportError(err)
+	}
+
+	// Validate required flags
+	if err := cmd.validateRequiredFlags(); err != nil {
+		return cmd, cmd.reportError(err)
+	}
+
+	// Validate flag groups
+	if err := cmd.validateFlagGroups(); err != nil {
+		return cmd, cmd.reportError(err)
 	}
 
 	// Execute main run
-	if err := cmd.executeRun(cmdArgs); err != nil {
-		if !cmd.SilenceErrors {
-			fmt.Fprintln(cmd.ErrOrStderr(), err)
-		}
-		if !cmd.SilenceUsage {
-			cmd.Usage()
+	if err := cmd.executeRunWithHook(cmdArgs); err != nil {
+		// An interrupted command still gets to clean up in its post-run hooks
+		if cmd.interrupted() {
+			finish(true)
 		}
-		return err
+		return cmd, cmd.reportError(err)
 	}
 
 	// Execute post-run
 	if err := cmd.executePostRun(cmdArgs); err != nil {
-		return err
+		return cmd, cmd.reportError(err)
 	}
 
 	// Execute persistent post-run
 	if err := cmd.executePersistentPostRun(cmdArgs); err != nil {
-		return err
+		return cmd, cmd.reportError(err)
+	}
+
+	return cmd, nil
+}
+
+// initDefaults adds the automatic subcommands and flags to a root command
+func (c *Command) initDefaults() {
+	c.initDefaultHelpCmd()
+	c.initDefaultCompletionCmd()
+	c.initDefaultVersionCmd()
+	c.initDefaultOutputFlag()
+	c.initDefaultDryRunFlag()
+	c.initDefaultQuietFlag()
+	c.initDefaultVerboseFlag()
+}
+
+// reportError prints err and the usage message, unless silenced, and returns err
+func (c *Command) reportError(err error) error {
+	if !c.SilenceErrors {
+		if c.Root().styledErrors {
+			c.PrintError(err.Error())
+		} else {
+			fmt.Fprintln(c.ErrOrStderr(), err)
+		}
 	}
+	if !c.SilenceUsage {
+		c.Usage()
+	}
+	return err
+}
 
+// EnableTraverseRunHooks runs the persistent hooks of every ancestor instead
+// of only the closest one. Pre-run hooks run from the root down; post-run
+// hooks run from the executed command up.
+var EnableTraverseRunHooks = false
+
+// runHook calls the first non-nil variant of a hook, in the order
+// context-aware, error-returning, plain
+func (c *Command) runHook(ctxFn func(context.Context, *Command, []string) error, eFn func(*Command, []string) error, fn func(*Command, []string), args []string) error {
+	switch {
+	case ctxFn != nil:
+		return ctxFn(c.Context(), c, args)
+	case eFn != nil:
+		return eFn(c, args)
+	case fn != nil:
+		fn(c, args)
+	}
 	return nil
 }
 
+// executePersistentPreRun runs the closest persistent pre-run hook defined on
+// the command or its ancestors, or all of them with EnableTraverseRunHooks
 func (c *Command) executePersistentPreRun(args []string) error {
-	if c.PersistentPreRunE != nil {
-		return c.PersistentPreRunE(c, args)
+	var hooks []*Command
+	for p := c; p != nil; p = p.parent {
+		if p.PersistentPreRunContextE != nil || p.PersistentPreRunE != nil || p.PersistentPreRun != nil {
+			// Prepend so ancestors run first
+			hooks = append([]*Command{p}, hooks...)
+			if !EnableTraverseRunHooks {
+				break
+			}
+		}
 	}
-	if c.PersistentPreRun != nil {
-		c.PersistentPreRun(c, args)
+
+	for _, p := range hooks {
+		if err := c.runHook(p.PersistentPreRunContextE, p.PersistentPreRunE, p.PersistentPreRun, args); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// executePreRun runs the command's pre-run hook, preceded by those of its
+// ancestors, root first, when ChainPreRun is set on the root
 func (c *Command) executePreRun(args []string) error {
-	if c.PreRunE != nil {
-		return c.PreRunE(c, args)
-	}
-	if c.PreRun != nil {
-		c.PreRun(c, args)
+	for _, p := range c.hookChain() {
+		if err := c.runHook(p.PreRunContextE, p.PreRunE, p.PreRun, args); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
 func (c *Command) executeRun(args []string) error {
-	if c.RunE != nil {
-		return c.RunE(c, args)
-	}
-	if c.Run != nil {
-		c.Run(c, args)
+	return c.runHook(c.RunContextE, c.RunE, c.Run, args)
+}
+
+// executePostRun runs the command's post-run hook, followed by those of its
+// ancestors, root last, when ChainPreRun is set on the root
+func (c *Command) executePostRun(args []string) error {
+	chain := c.hookChain()
+	for i := len(chain) - 1; i >= 0; i-- {
+		p := chain[i]
+		if err := c.runHook(p.PostRunContextE, p.PostRunE, p.PostRun, args); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func (c *Command) executePostRun(args []string) error {
-	if c.PostRunE != nil {
-		return c.PostRunE(c, args)
+// hookChain returns the commands whose local hooks run for c, root first:
+// c alone, or c and its ancestors with ChainPreRun
+func (c *Command) hookChain() []*Command {
+	if !c.Root().ChainPreRun {
+		return []*Command{c}
 	}
-	if c.PostRun != nil {
-		c.PostRun(c, args)
+	var chain []*Command
+	for p := c; p != nil; p = p.parent {
+		chain = append([]*Command{p}, chain...)
 	}
-	return nil
+	return chain
 }
 
+// executePersistentPostRun runs the closest persistent post-run hook defined
+// on the command or its ancestors, or all of them with EnableTraverseRunHooks
 func (c *Command) executePersistentPostRun(args []string) error {
-	if c.PersistentPostRunE != nil {
-		return c.PersistentPostRunE(c, args)
-	}
-	if c.PersistentPostRun != nil {
-		c.PersistentPostRun(c, args)
+	for p := c; p != nil; p = p.parent {
+		if p.PersistentPostRunContextE == nil && p.PersistentPostRunE == nil && p.PersistentPostRun == nil {
+			continue
+		}
+		if err := c.runHook(p.PersistentPostRunContextE, p.PersistentPostRunE, p.PersistentPostRun, args); err != nil {
+			return err
+		}
+		if !EnableTraverseRunHooks {
+			break
+		}
 	}
 	return nil
 }
@@ -346,6 +746,9 @@ func (c *Command) AddCommand(cmds ...*Command) {
 			panic("command can't be a child of itself")
 		}
 		cmd.parent = c
+		if c.globNormFunc != nil {
+			cmd.SetGlobalNormalizationFunc(c.globNormFunc)
+		}
 		c.commands = append(c.commands, cmd)
 	}
 }
@@ -366,22 +769,96 @@ main:
 	c.commands = commands
 }
 
-// Find finds the command to execute
+// Find finds the command to execute. An unmatched word is an error when the
+// command has subcommands and no Args validator to accept it as an argument.
 func (c *Command) Find(args []string) (*Command, []string, error) {
 	if len(args) == 0 {
 		return c, args, nil
 	}
 
 	// Check for subcommand
-	for _, cmd := range c.commands {
-		if cmd.Name() == args[0] || cmd.HasAlias(args[0]) {
+	if cmd := c.findSubCommand(args[0]); cmd != nil {
+		return cmd.Find(args[1:])
+	}
+
+	if c.HasSubCommands() && c.Args == nil && !strings.HasPrefix(args[0], "-") {
+		if cmd := c.suggestedCommand(args[0]); cmd != nil {
 			return cmd.Find(args[1:])
 		}
+		return c, args, c.unknownCommandError(args[0])
 	}
 
 	return c, args, nil
 }
 
+// Traverse finds the command to execute like Find, but parses each
+// command's flags as it descends, so flags may appear before subcommands
+func (c *Command) Traverse(args []string) (*Command, []string, error) {
+	c.InitDefaultHelpFlag()
+	c.InitDefaultVersionFlag()
+	c.mergePersistentFlags()
+
+	var flags []string
+	inFlag := false
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return c, args, nil
+		case strings.HasPrefix(arg, "--") && !strings.Contains(arg, "="):
+			// The next word is this flag's value unless it takes none
+			inFlag = !hasNoOptDefVal(c.Flags().Lookup(arg[2:]))
+			flags = append(flags, arg)
+			continue
+		case len(arg) == 2 && arg[0] == '-' && arg != "-":
+			inFlag = !hasNoOptDefVal(c.Flags().ShorthandLookup(arg[1:]))
+			flags = append(flags, arg)
+			continue
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			flags = append(flags, arg)
+			continue
+		case inFlag:
+			inFlag = false
+			flags = append(flags, arg)
+			continue
+		}
+
+		next := c.findSubCommand(arg)
+		if next == nil && c.HasSubCommands() && c.Args == nil {
+			next = c.suggestedCommand(arg)
+			if next == nil {
+				return c, args, c.unknownCommandError(arg)
+			}
+		}
+		if next == nil {
+			return c, args, nil
+		}
+
+		// Leave flag errors, help and version to execute, which reports
+		// them against this command
+		if err := c.ParseFlags(flags); err != nil || c.helpFlagSet() || c.versionFlagSet() {
+			return c, args, nil
+		}
+		return next.Traverse(args[i+1:])
+	}
+
+	return c, args, nil
+}
+
+// findSubCommand returns the direct subcommand named or aliased name
+func (c *Command) findSubCommand(name string) *Command {
+	for _, cmd := range c.commands {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// hasNoOptDefVal reports whether f can be given without a value
+func hasNoOptDefVal(f *pflag.Flag) bool {
+	return f != nil && f.NoOptDefVal != ""
+}
+
 // Name returns the command's name
 func (c *Command) Name() string {
 	name := c.Use
@@ -443,33 +920,87 @@ func (c *Command) PersistentFlags() *pflag.FlagSet {
 func (c *Command) ParseFlags(args []string) error {
 	c.mergePersistentFlags()
 
-	return c.Flags().Parse(args)
+	err := c.Flags().Parse(args)
+	if err != nil && err != pflag.ErrHelp {
+		return c.FlagErrorFunc()(c, err)
+	}
+	return err
 }
 
 func (c *Command) mergePersistentFlags() {
-	// Merge parent persistent flags
-	if c.parent != nil {
-		c.parent.mergePersistentFlags()
-		c.parent.PersistentFlags().VisitAll(func(f *pflag.Flag) {
-			if c.Flags().Lookup(f.Name) == nil {
-				c.Flags().AddFlag(f)
-			}
-		})
+	if c.globNormFunc != nil {
+		c.Flags().SetNormalizeFunc(c.globNormFunc)
 	}
 
-	// Merge local persistent flags
-	c.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+	add := func(f *pflag.Flag) {
 		if c.Flags().Lookup(f.Name) == nil {
 			c.Flags().AddFlag(f)
 		}
-	})
+	}
 
-	// Merge local flags
-	c.LocalFlags().VisitAll(func(f *pflag.Flag) {
-		if c.Flags().Lookup(f.Name) == nil {
-			c.Flags().AddFlag(f)
+	// The command's own flags shadow inherited ones of the same name
+	c.PersistentFlags().VisitAll(add)
+	c.LocalFlags().VisitAll(add)
+	c.InheritedFlags().VisitAll(add)
+}
+
+// InheritedFlags returns the persistent flags of all ancestors. A flag
+// defined closer to the command shadows one of the same name further up.
+func (c *Command) InheritedFlags() *pflag.FlagSet {
+	inherited := c.newFlagSet()
+	for p := c.parent; p != nil; p = p.parent {
+		p.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+			if inherited.Lookup(f.Name) == nil {
+				inherited.AddFlag(f)
+			}
+		})
+	}
+	return inherited
+}
+
+// NonInheritedFlags returns the flags defined on the command itself, both
+// local and persistent
+func (c *Command) NonInheritedFlags() *pflag.FlagSet {
+	c.mergePersistentFlags()
+	inherited := c.InheritedFlags()
+
+	own := c.newFlagSet()
+	c.Flags().VisitAll(func(f *pflag.Flag) {
+		if inherited.Lookup(f.Name) != f {
+			own.AddFlag(f)
 		}
 	})
+	return own
+}
+
+// newFlagSet returns an empty flag set that normalizes names like the
+// command's own flags
+func (c *Command) newFlagSet() *pflag.FlagSet {
+	fs := pflag.NewFlagSet(c.Name(), pflag.ContinueOnError)
+	if c.globNormFunc != nil {
+		fs.SetNormalizeFunc(c.globNormFunc)
+	}
+	return fs
+}
+
+// SetGlobalNormalizationFunc sets a function that normalizes flag names for
+// the command and all of its subcommands, so that, for example, --my_flag
+// and --my-flag resolve to the same flag
+func (c *Command) SetGlobalNormalizationFunc(n func(f *pflag.FlagSet, name string) pflag.NormalizedName) {
+	c.Flags().SetNormalizeFunc(n)
+	c.LocalFlags().SetNormalizeFunc(n)
+	c.PersistentFlags().SetNormalizeFunc(n)
+	c.globNormFunc = n
+
+	for _, cmd := range c.commands {
+		cmd.SetGlobalNormalizationFunc(n)
+	}
+}
+
+// GlobalNormalizationFunc returns the flag name normalization function, or
+// nil if none is set
+func (c *Command) GlobalNormalizationFunc() func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+	return c.globNormFunc
 }
 
 // SetOutput sets the output writer
@@ -535,9 +1066,12 @@ func (c *Command) UsageString() string {
 	var sb strings.Builder
 
 	sb.WriteString("Usage:\n")
-	sb.WriteString("  ")
-	sb.WriteString(c.UseLine())
-	sb.WriteString("\n\n")
+	for _, line := range c.usageLines() {
+		sb.WriteString("  ")
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
 
 	if c.Long != "" {
 		sb.WriteString(c.Long)
@@ -547,19 +1081,30 @@ func (c *Command) UsageString() string {
 		sb.WriteString("\n\n")
 	}
 
-	if len(c.commands) > 0 {
-		sb.WriteString("Available Commands:\n")
-		for _, cmd := range c.commands {
-			if !cmd.Hidden {
-				sb.WriteString(fmt.Sprintf("  %-12s %s\n", cmd.Name(), cmd.Short))
+	for _, section := range c.commandSections() {
+		sb.WriteString(section.title + ":\n")
+		for _, cmd := range section.commands {
+			short := cmd.Short
+			if cmd.Deprecated != "" {
+				short = strings.TrimSpace(short + " (deprecated)")
 			}
+			sb.WriteString(fmt.Sprintf("  %-12s %s\n", cmd.Name(), short))
 		}
 		sb.WriteString("\n")
 	}
 
-	if c.Flags().HasFlags() {
+	local := c.NonInheritedFlags()
+	if local.HasAvailableFlags() {
 		sb.WriteString("Flags:\n")
-		sb.WriteString(c.Flags().FlagUsages())
+		sb.WriteString(local.FlagUsages())
+	}
+
+	if global := c.globalFlags(); global.HasAvailableFlags() {
+		if local.HasAvailableFlags() {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("Global Flags:\n")
+		sb.WriteString(global.FlagUsages())
 	}
 
 	return sb.String()
@@ -576,6 +1121,32 @@ func (c *Command) UseLine() string {
 	return useline
 }
 
+// Runnable reports whether the command has a run function to execute
+func (c *Command) Runnable() bool {
+	return c.Run != nil || c.RunE != nil || c.RunContextE != nil
+}
+
+// usageLines returns the usage forms of the command: its use line when it is
+// runnable, and the "[command]" form when it has subcommands
+func (c *Command) usageLines() []string {
+	var lines []string
+	if c.Runnable() || !c.HasSubCommands() {
+		lines = append(lines, c.UseLine())
+	}
+	if c.HasSubCommands() {
+		lines = append(lines, c.CommandPath()+" [command]")
+	}
+	return lines
+}
+
+// CommandPath returns the full path to this command, such as "app remote add"
+func (c *Command) CommandPath() string {
+	if c.parent != nil {
+		return c.parent.CommandPath() + " " + c.Name()
+	}
+	return c.Name()
+}
+
 // Help prints the help message
 func (c *Command) Help() error {
 	if c.shouldUseModernHelp() {
@@ -588,22 +1159,43 @@ func (c *Command) Help() error {
 
 // shouldUseModernHelp determines if modern help should be used
 func (c *Command) shouldUseModernHelp() bool {
-	// Use modern help by default unless explicitly disabled
-	if c.EnableColors != nil {
-		return *c.EnableColors
+	// An explicit setting on the command or its nearest ancestor wins
+	if enabled := c.colorSetting(); enabled != nil {
+		return *enabled
 	}
-	// Auto-detect: use modern help if output is a terminal
-	// For now, default to true
-	return true
+	// Auto-detect: use modern help if output is a terminal and styling has
+	// not been disabled
+	return style.Styling() && isTerminal(c.OutOrStdout())
 }
 
-// Context returns the command context
-func (c *Command) Context() interface{} {
+// colorSetting returns EnableColors from the command or its nearest ancestor
+// that sets it, or nil when colors are auto-detected
+func (c *Command) colorSetting() *bool {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.EnableColors != nil {
+			return cmd.EnableColors
+		}
+	}
+	return nil
+}
+
+// colorsDisabled reports whether colors were explicitly turned off
+func (c *Command) colorsDisabled() bool {
+	enabled := c.colorSetting()
+	return enabled != nil && !*enabled
+}
+
+// Context returns the command context.
+// If no context has been set, context.Background() is returned.
+func (c *Command) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
 	return c.ctx
 }
 
 // SetContext sets the command context
-func (c *Command) SetContext(ctx interface{}) {
+func (c *Command) SetContext(ctx context.Context) {
 	c.ctx = ctx
 }
 
@@ -617,6 +1209,23 @@ func (c *Command) HasParent() bool {
 	return c.parent != nil
 }
 
+// VisitParents calls fn for each ancestor of the command, starting with its
+// parent and ending with the root
+func (c *Command) VisitParents(fn func(*Command)) {
+	for cmd := c.parent; cmd != nil; cmd = cmd.parent {
+		fn(cmd)
+	}
+}
+
+// VisitCommands calls fn for the command and all of its descendants in
+// pre-order, including hidden commands
+func (c *Command) VisitCommands(fn func(*Command)) {
+	fn(c)
+	for _, cmd := range c.commands {
+		cmd.VisitCommands(fn)
+	}
+}
+
 // Root returns the root command
 func (c *Command) Root() *Command {
 	if c.parent != nil {
@@ -625,14 +1234,55 @@ func (c *Command) Root() *Command {
 	return c
 }
 
-// SetVersionTemplate sets the version template
-func (c *Command) SetVersionTemplate(s string) {
-	// TODO: implement version templating
+// SetTheme sets the style theme used by this command and its subcommands
+// for help and Print* output
+func (c *Command) SetTheme(t style.Theme) {
+	c.theme = &t
 }
 
-// SetHelpCommand sets the help command
+// Theme returns the style theme for this command, inherited from the nearest
+// ancestor with a theme set, or the active style theme
+func (c *Command) Theme() style.Theme {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.theme != nil {
+			return *cmd.theme
+		}
+	}
+	return style.CurrentTheme()
+}
+
+// SetHelpCommand replaces the automatic "help" subcommand of the root
 func (c *Command) SetHelpCommand(cmd *Command) {
-	// TODO: implement custom help command
+	c.helpCommand = cmd
+}
+
+// initDefaultHelpCmd adds a "help" subcommand to a root with subcommands,
+// unless one exists or DisableAutoHelpCommand is set. HiddenAutoHelpCommand
+// hides it from help.
+func (c *Command) initDefaultHelpCmd() {
+	if !c.HasSubCommands() || c.DisableAutoHelpCommand {
+		return
+	}
+	if c.helpCommand == nil {
+		c.helpCommand = &Command{
+			Use:    "help [command]",
+			Short:  "Help about any command",
+			Hidden: c.HiddenAutoHelpCommand,
+			Long: fmt.Sprintf(`Help provides help for any command in the application.
+Type "%s help [path to command]" for full details.`, c.Name()),
+			RunE: func(cmd *Command, args []string) error {
+				target, _, err := cmd.Root().Find(args)
+				if err != nil {
+					return err
+				}
+				return target.Help()
+			},
+		}
+	}
+	if c.findSubCommand(c.helpCommand.Name()) != nil {
+		return
+	}
+	c.AddCommand(c.helpCommand)
 }
 
 // SetHelpFunc sets the help function
@@ -650,11 +1300,36 @@ func (c *Command) SetUsageTemplate(s string) {
 	// TODO: implement usage templating
 }
 
-// initDefaultHelpFlag adds the default help flag if it doesn't exist
-func (c *Command) initDefaultHelpFlag() {
-	if c.Flags().Lookup("help") == nil {
-		c.Flags().BoolP("help", "h", false, "help for "+c.Name())
+// InitDefaultHelpFlag adds a --help flag to the command unless it already
+// has one or DisableHelpFlag is set. The -h shorthand is only used if no
+// other flag has claimed it. Execute calls it automatically; calling it
+// earlier lets tooling inspect the final flag set.
+func (c *Command) InitDefaultHelpFlag() {
+	if c.helpFlagDisabled() {
+		return
+	}
+	c.mergePersistentFlags()
+	if c.Flags().Lookup("help") != nil {
+		return
+	}
+
+	usage := "help for " + c.Name()
+	if c.Flags().ShorthandLookup("h") == nil {
+		c.Flags().BoolP("help", "h", false, usage)
+	} else {
+		c.Flags().Bool("help", false, usage)
+	}
+}
+
+// helpFlagDisabled reports whether the automatic help flag is disabled
+// on this command or any of its ancestors
+func (c *Command) helpFlagDisabled() bool {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.DisableHelpFlag {
+			return true
+		}
 	}
+	return false
 }
 
 // helpFlagSet checks if the help flag was set
@@ -665,3 +1340,27 @@ func (c *Command) helpFlagSet() bool {
 	}
 	return flag.Value.String() == "true"
 }
+
+// isTerminal reports whether w is connected to a terminal.
+// It is a variable so tests can simulate a TTY.
+var isTerminal = func(w io.Writer) bool {
+	f, ok := w.(interface{ Fd() uintptr })
+	if !ok {
+		return false
+	}
+	return term.IsTerminal(f.Fd())
+}
+
+// colorForced reports whether color was forced on regardless of the terminal.
+// It is a variable so tests can simulate a detected color profile.
+var colorForced = style.ColorForced
+
+// isInputTerminal reports whether r is connected to a terminal.
+// It is a variable so tests can simulate a TTY.
+var isInputTerminal = func(r io.Reader) bool {
+	f, ok := r.(interface{ Fd() uintptr })
+	if !ok {
+		return false
+	}
+	return term.IsTerminal(f.Fd())
+}
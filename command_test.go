@@ -2,9 +2,13 @@ package mamba
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/spf13/pflag"
 )
 
 func TestCommand_Execute(t *testing.T) {
@@ -226,6 +230,114 @@ func TestCommand_Args_NoArgs(t *testing.T) {
 	}
 }
 
+func TestCommand_Args_OnlyValidArgs(t *testing.T) {
+	cmd := &Command{
+		Use:       "deploy",
+		ValidArgs: []string{"staging", "production"},
+		Run:       func(cmd *Command, args []string) {},
+	}
+	cmd.SetOutput(new(bytes.Buffer))
+
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{}, ""},
+		{[]string{"staging"}, ""},
+		{[]string{"staging", "production"}, ""},
+		{[]string{"staging", "qa"}, `invalid argument "qa" for "deploy"`},
+	}
+
+	for _, tt := range tests {
+		// Applied automatically when ValidArgs is set and Args is nil
+		err := cmd.execute(tt.args)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("execute(%v) error = %v", tt.args, err)
+		}
+		if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+			t.Errorf("execute(%v) error = %v, want %q", tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCommand_Args_MatchAll(t *testing.T) {
+	cmd := &Command{
+		Use:       "deploy",
+		Args:      MatchAll(ExactArgs(1), OnlyValidArgs),
+		ValidArgs: []string{"staging", "production"},
+	}
+
+	tests := []struct {
+		args    []string
+		wantErr bool
+	}{
+		{[]string{"staging"}, false},
+		{[]string{}, true},
+		{[]string{"staging", "production"}, true},
+		{[]string{"qa"}, true},
+	}
+
+	for _, tt := range tests {
+		err := cmd.ValidateArgs(tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("MatchAll() with args %v, wantErr %v, got err %v", tt.args, tt.wantErr, err)
+		}
+	}
+}
+
+func TestCommand_Args_ExactValidArgs(t *testing.T) {
+	cmd := &Command{
+		Use:       "diff",
+		Args:      ExactValidArgs(2),
+		ValidArgs: []string{"staging", "production", "qa"},
+	}
+
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{"staging", "production"}, ""},
+		{[]string{"staging"}, "accepts 2 arg(s), received 1"},
+		{[]string{"staging", "dev"}, `invalid argument "dev" for "diff"`},
+	}
+
+	for _, tt := range tests {
+		err := cmd.ValidateArgs(tt.args)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("ExactValidArgs(2) with args %v error = %v", tt.args, err)
+		}
+		if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+			t.Errorf("ExactValidArgs(2) with args %v error = %v, want %q", tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCommand_Args_MatchAllShortCircuits(t *testing.T) {
+	calls := 0
+	counting := func(cmd *Command, args []string) error {
+		calls++
+		return nil
+	}
+	cmd := &Command{
+		Use:       "diff",
+		Args:      MatchAll(ExactArgs(2), OnlyValidArgs, counting),
+		ValidArgs: []string{"staging", "production"},
+	}
+
+	// Both arity and membership are wrong; only the first error is returned
+	err := cmd.ValidateArgs([]string{"dev"})
+	if err == nil || err.Error() != "accepts 2 arg(s), received 1" {
+		t.Errorf("MatchAll() error = %v, want the ExactArgs error", err)
+	}
+	if calls != 0 {
+		t.Errorf("validators after the first failure should not run, ran %d", calls)
+	}
+
+	if err := cmd.ValidateArgs([]string{"staging", "production"}); err != nil || calls != 1 {
+		t.Errorf("MatchAll() error = %v calls = %d, want nil and 1", err, calls)
+	}
+}
+
 func TestCommand_Find(t *testing.T) {
 	rootCmd := &Command{Use: "root"}
 	subCmd := &Command{Use: "sub"}
@@ -283,6 +395,162 @@ func TestCommand_HelpFlag(t *testing.T) {
 	}
 }
 
+func TestCommand_HelpFlagExecute(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantHelp string
+	}{
+		{"root long flag", []string{"--help"}, "root"},
+		{"subcommand long flag", []string{"sub", "--help"}, "sub"},
+		{"subcommand shorthand", []string{"sub", "-h"}, "sub"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			ran := false
+			rootCmd := &Command{
+				Use:   "root",
+				Short: "Root command",
+				Run:   func(cmd *Command, args []string) { ran = true },
+			}
+			subCmd := &Command{
+				Use:   "sub",
+				Short: "Sub command",
+				Run:   func(cmd *Command, args []string) { ran = true },
+			}
+			rootCmd.AddCommand(subCmd)
+			rootCmd.SetOutput(buf)
+
+			if err := rootCmd.execute(tt.args); err != nil {
+				t.Fatalf("execute() error = %v", err)
+			}
+			if ran {
+				t.Error("Expected Run not to be called when help is requested")
+			}
+			if !strings.Contains(buf.String(), tt.wantHelp) {
+				t.Errorf("Expected help for %q, got: %s", tt.wantHelp, buf.String())
+			}
+		})
+	}
+}
+
+func TestCommand_HelpForNonRunnableParent(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd := &Command{Use: "root", Short: "Root command"}
+	rootCmd.AddCommand(&Command{Use: "sub", Short: "Sub command"})
+	rootCmd.SetOutput(buf)
+
+	if err := rootCmd.execute([]string{}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "sub") {
+		t.Errorf("Expected help listing subcommands, got: %s", buf.String())
+	}
+}
+
+func TestCommand_HelpForBareSubcommand(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd := &Command{Use: "app"}
+	remoteCmd := &Command{Use: "remote", Short: "Manage remotes"}
+	remoteCmd.AddCommand(&Command{Use: "add", Short: "Add a remote", Run: func(cmd *Command, args []string) {}})
+	rootCmd.AddCommand(remoteCmd)
+	rootCmd.SetOutput(buf)
+
+	if err := rootCmd.execute([]string{"remote"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Add a remote") {
+		t.Errorf("Expected help listing subcommands, got: %s", buf.String())
+	}
+}
+
+func TestCommand_SubcommandRequired(t *testing.T) {
+	outBuf, errBuf := new(bytes.Buffer), new(bytes.Buffer)
+	rootCmd := &Command{Use: "app"}
+	remoteCmd := &Command{Use: "remote", SubcommandRequired: true}
+	remoteCmd.AddCommand(&Command{Use: "add", Short: "Add a remote", Run: func(cmd *Command, args []string) {}})
+	rootCmd.AddCommand(remoteCmd)
+	rootCmd.SetOutput(outBuf)
+	rootCmd.SetErr(errBuf)
+
+	err := rootCmd.execute([]string{"remote"})
+	if err == nil || err.Error() != `"app remote" requires a subcommand` {
+		t.Fatalf("execute() error = %v, want a subcommand required error", err)
+	}
+	if !strings.Contains(errBuf.String(), "requires a subcommand") {
+		t.Errorf("Expected the error to be printed, got: %s", errBuf.String())
+	}
+
+	if err := rootCmd.execute([]string{"remote", "add"}); err != nil {
+		t.Errorf("execute() with a subcommand error = %v", err)
+	}
+}
+
+func TestCommand_DisableHelpFlag(t *testing.T) {
+	var helpValue bool
+	rootCmd := &Command{
+		Use:             "root",
+		DisableHelpFlag: true,
+		Run:             func(cmd *Command, args []string) {},
+	}
+	subCmd := &Command{
+		Use: "sub",
+		Run: func(cmd *Command, args []string) {},
+	}
+	subCmd.Flags().BoolVar(&helpValue, "help", false, "custom help")
+	rootCmd.AddCommand(subCmd)
+
+	if err := rootCmd.execute([]string{"sub", "--help"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if !helpValue {
+		t.Error("Expected custom help flag to be parsed")
+	}
+	if subCmd.Flags().ShorthandLookup("h") != nil {
+		t.Error("Expected no automatic -h flag when DisableHelpFlag is set")
+	}
+}
+
+func TestCommand_InitDefaultHelpFlag(t *testing.T) {
+	cmd := &Command{Use: "root"}
+
+	cmd.InitDefaultHelpFlag()
+	cmd.InitDefaultHelpFlag()
+
+	count := 0
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Name == "help" {
+			count++
+		}
+	})
+	if count != 1 {
+		t.Errorf("help flags = %d, want 1", count)
+	}
+	if f := cmd.Flags().Lookup("help"); f == nil || f.Shorthand != "h" {
+		t.Errorf("help flag = %+v, want shorthand h", f)
+	}
+}
+
+func TestCommand_InitDefaultHelpFlagShorthandTaken(t *testing.T) {
+	var host string
+	cmd := &Command{Use: "root", Run: func(cmd *Command, args []string) {}}
+	cmd.Flags().StringVarP(&host, "host", "h", "", "Host to connect to")
+
+	cmd.InitDefaultHelpFlag()
+
+	if f := cmd.Flags().Lookup("help"); f == nil || f.Shorthand != "" {
+		t.Errorf("help flag = %+v, want no shorthand when -h is taken", f)
+	}
+	if err := cmd.execute([]string{"-h", "example.com"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if host != "example.com" {
+		t.Errorf("host = %q, want example.com", host)
+	}
+}
+
 func TestCommand_IO(t *testing.T) {
 	inBuf := bytes.NewBufferString("input")
 	outBuf := new(bytes.Buffer)
@@ -323,6 +591,51 @@ func TestCommand_Root(t *testing.T) {
 	}
 }
 
+func TestCommand_VisitParents(t *testing.T) {
+	rootCmd := &Command{Use: "root"}
+	subCmd := &Command{Use: "sub"}
+	subSubCmd := &Command{Use: "subsub"}
+	rootCmd.AddCommand(subCmd)
+	subCmd.AddCommand(subSubCmd)
+
+	var visited []string
+	subSubCmd.VisitParents(func(cmd *Command) {
+		visited = append(visited, cmd.Name())
+	})
+	if got := strings.Join(visited, ","); got != "sub,root" {
+		t.Errorf("VisitParents() order = %s, want sub,root", got)
+	}
+
+	rootCmd.VisitParents(func(cmd *Command) {
+		t.Errorf("VisitParents() on the root should not visit %s", cmd.Name())
+	})
+}
+
+func TestCommand_VisitCommands(t *testing.T) {
+	rootCmd := &Command{Use: "root"}
+	aCmd := &Command{Use: "a"}
+	bCmd := &Command{Use: "b", Hidden: true}
+	aCmd.AddCommand(&Command{Use: "a1"}, &Command{Use: "a2"})
+	bCmd.AddCommand(&Command{Use: "b1"})
+	rootCmd.AddCommand(aCmd, bCmd)
+
+	var visited []string
+	seen := map[*Command]int{}
+	rootCmd.VisitCommands(func(cmd *Command) {
+		visited = append(visited, cmd.Name())
+		seen[cmd]++
+	})
+
+	if got := strings.Join(visited, ","); got != "root,a,a1,a2,b,b1" {
+		t.Errorf("VisitCommands() order = %s, want root,a,a1,a2,b,b1", got)
+	}
+	for cmd, n := range seen {
+		if n != 1 {
+			t.Errorf("VisitCommands() visited %s %d times, want once", cmd.Name(), n)
+		}
+	}
+}
+
 func TestCommand_HasParent(t *testing.T) {
 	rootCmd := &Command{Use: "root"}
 	subCmd := &Command{Use: "sub"}
@@ -394,6 +707,84 @@ func TestCommand_ExecuteWithArgs(t *testing.T) {
 	}
 }
 
+func TestCommand_SetArgs(t *testing.T) {
+	var receivedArgs []string
+	var replicas int
+	var verbose bool
+	rootCmd := &Command{Use: "app"}
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Verbose output")
+	deployCmd := &Command{
+		Use: "deploy",
+		Run: func(cmd *Command, args []string) {
+			receivedArgs = args
+		},
+	}
+	deployCmd.Flags().IntVar(&replicas, "replicas", 1, "Replica count")
+	rootCmd.AddCommand(deployCmd)
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	rootCmd.SetArgs([]string{"deploy", "--replicas", "3", "--verbose", "prod"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(receivedArgs) != 1 || receivedArgs[0] != "prod" {
+		t.Errorf("Expected args [prod], got %v", receivedArgs)
+	}
+	if replicas != 3 || !verbose {
+		t.Errorf("Expected replicas=3 verbose=true, got replicas=%d verbose=%v", replicas, verbose)
+	}
+}
+
+func TestCommand_ExecuteC(t *testing.T) {
+	runErr := errors.New("deploy failed")
+	newTree := func() (*Command, *Command, *Command) {
+		rootCmd := &Command{Use: "app", SilenceErrors: true, SilenceUsage: true}
+		deployCmd := &Command{
+			Use:  "deploy",
+			Args: ExactArgs(1),
+			RunE: func(cmd *Command, args []string) error {
+				if args[0] == "fail" {
+					return runErr
+				}
+				return nil
+			},
+		}
+		statusCmd := &Command{Use: "status", Run: func(cmd *Command, args []string) {}}
+		rootCmd.AddCommand(deployCmd, statusCmd)
+		rootCmd.SetOutput(new(bytes.Buffer))
+		return rootCmd, deployCmd, statusCmd
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    func(root, deploy, status *Command) *Command
+		wantErr bool
+	}{
+		{"success", []string{"status"}, func(root, deploy, status *Command) *Command { return status }, false},
+		{"run error", []string{"deploy", "fail"}, func(root, deploy, status *Command) *Command { return deploy }, true},
+		{"args error", []string{"deploy"}, func(root, deploy, status *Command) *Command { return deploy }, true},
+		{"unknown command", []string{"dpeloy"}, func(root, deploy, status *Command) *Command { return root }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd, deployCmd, statusCmd := newTree()
+			rootCmd.SetArgs(tt.args)
+
+			cmd, err := rootCmd.ExecuteC()
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExecuteC() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if want := tt.want(rootCmd, deployCmd, statusCmd); cmd != want {
+				t.Errorf("ExecuteC() command = %q, want %q", cmd.Name(), want.Name())
+			}
+		})
+	}
+}
+
 func TestCommand_ErrorHandling(t *testing.T) {
 	errBuf := new(bytes.Buffer)
 	cmd := &Command{
@@ -430,6 +821,66 @@ func TestCommand_SilenceErrors(t *testing.T) {
 	}
 }
 
+func TestCommand_ErrorsPrintUsage(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"args validation", []string{"a", "b"}, "accepts 1 arg(s), received 2"},
+		{"unknown flag", []string{"a", "--bogus"}, "unknown flag: --bogus"},
+		{"required flag", []string{"a"}, `required flag(s) "name" not set`},
+		{"pre-run", []string{"a", "--name", "x", "--fail-pre"}, "pre-run failed"},
+	}
+
+	for _, tt := range tests {
+		for _, silenced := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/silenced=%v", tt.name, silenced), func(t *testing.T) {
+				out := new(bytes.Buffer)
+				errBuf := new(bytes.Buffer)
+				cmd := &Command{
+					Use:           "test <name>",
+					Args:          ExactArgs(1),
+					SilenceErrors: silenced,
+					SilenceUsage:  silenced,
+					PreRunE: func(cmd *Command, args []string) error {
+						if fail, _ := cmd.Flags().GetBool("fail-pre"); fail {
+							return errors.New("pre-run failed")
+						}
+						return nil
+					},
+					Run: func(cmd *Command, args []string) {},
+				}
+				cmd.Flags().String("name", "", "Name")
+				cmd.Flags().Bool("fail-pre", false, "Fail in pre-run")
+				_ = cmd.MarkFlagRequired("name")
+				cmd.SetOutput(out)
+				cmd.SetErr(errBuf)
+
+				err := cmd.execute(tt.args)
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("execute() error = %v, want %q", err, tt.wantErr)
+				}
+
+				usages := strings.Count(out.String(), "Usage:")
+				printed := strings.Count(errBuf.String(), tt.wantErr)
+				if silenced {
+					if usages != 0 || printed != 0 {
+						t.Errorf("silenced command printed usage %d and error %d times", usages, printed)
+					}
+					return
+				}
+				if usages != 1 {
+					t.Errorf("usage printed %d times, want once:\n%s", usages, out.String())
+				}
+				if printed != 1 {
+					t.Errorf("error printed %d times, want once:\n%s", printed, errBuf.String())
+				}
+			})
+		}
+	}
+}
+
 func TestCommand_PersistentPreRunE(t *testing.T) {
 	var executed []string
 	rootCmd := &Command{
@@ -482,6 +933,143 @@ func TestCommand_PersistentPostRunE(t *testing.T) {
 	}
 }
 
+func newPersistentHookTree(executed *[]string) (*Command, *Command) {
+	hook := func(name string) func(cmd *Command, args []string) {
+		return func(cmd *Command, args []string) {
+			*executed = append(*executed, name+":"+cmd.Name())
+		}
+	}
+
+	rootCmd := &Command{
+		Use:               "root",
+		PersistentPreRun:  hook("root-pre"),
+		PersistentPostRun: hook("root-post"),
+	}
+	midCmd := &Command{
+		Use:               "mid",
+		PersistentPreRun:  hook("mid-pre"),
+		PersistentPostRun: hook("mid-post"),
+	}
+	leafCmd := &Command{
+		Use: "leaf",
+		Run: hook("run"),
+	}
+	otherCmd := &Command{
+		Use: "other",
+		Run: hook("run"),
+	}
+
+	midCmd.AddCommand(leafCmd)
+	rootCmd.AddCommand(midCmd, otherCmd)
+	return rootCmd, leafCmd
+}
+
+func TestCommand_PersistentHooksInherited(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"mid", "leaf"}, []string{"mid-pre:leaf", "run:leaf", "mid-post:leaf"}},
+		{[]string{"other"}, []string{"root-pre:other", "run:other", "root-post:other"}},
+	}
+
+	for _, tt := range tests {
+		var executed []string
+		rootCmd, _ := newPersistentHookTree(&executed)
+
+		if err := rootCmd.execute(tt.args); err != nil {
+			t.Fatalf("execute(%v) error = %v", tt.args, err)
+		}
+		if strings.Join(executed, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("execute(%v) hooks = %v, want %v", tt.args, executed, tt.want)
+		}
+	}
+}
+
+func TestCommand_EnableTraverseRunHooks(t *testing.T) {
+	EnableTraverseRunHooks = true
+	defer func() { EnableTraverseRunHooks = false }()
+
+	var executed []string
+	rootCmd, _ := newPersistentHookTree(&executed)
+
+	if err := rootCmd.execute([]string{"mid", "leaf"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+
+	want := []string{"root-pre:leaf", "mid-pre:leaf", "run:leaf", "mid-post:leaf", "root-post:leaf"}
+	if strings.Join(executed, ",") != strings.Join(want, ",") {
+		t.Errorf("hooks = %v, want %v", executed, want)
+	}
+}
+
+func TestCommand_ChainPreRun(t *testing.T) {
+	tests := []struct {
+		chain bool
+		want  []string
+	}{
+		{false, []string{"root-pre:leaf", "leaf-pre:leaf", "run:leaf", "leaf-post:leaf", "root-post:leaf"}},
+		{true, []string{"root-pre:leaf", "root-local-pre:leaf", "leaf-pre:leaf", "run:leaf", "leaf-post:leaf", "root-local-post:leaf", "root-post:leaf"}},
+	}
+
+	for _, tt := range tests {
+		var executed []string
+		hook := func(name string) func(cmd *Command, args []string) {
+			return func(cmd *Command, args []string) {
+				executed = append(executed, name+":"+cmd.Name())
+			}
+		}
+
+		rootCmd := &Command{
+			Use:               "root",
+			ChainPreRun:       tt.chain,
+			PersistentPreRun:  hook("root-pre"),
+			PersistentPostRun: hook("root-post"),
+			PreRun:            hook("root-local-pre"),
+			PostRun:           hook("root-local-post"),
+		}
+		leafCmd := &Command{
+			Use:     "leaf",
+			PreRun:  hook("leaf-pre"),
+			Run:     hook("run"),
+			PostRun: hook("leaf-post"),
+		}
+		rootCmd.AddCommand(leafCmd)
+
+		if err := rootCmd.execute([]string{"leaf"}); err != nil {
+			t.Fatalf("execute() error = %v", err)
+		}
+		if strings.Join(executed, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("ChainPreRun=%v hooks = %v, want %v", tt.chain, executed, tt.want)
+		}
+	}
+}
+
+func TestCommand_ChainPreRunError(t *testing.T) {
+	var ran bool
+	rootCmd := &Command{
+		Use:          "root",
+		ChainPreRun:  true,
+		SilenceUsage: true,
+		PreRunE: func(cmd *Command, args []string) error {
+			return errors.New("setup failed")
+		},
+	}
+	leafCmd := &Command{
+		Use: "leaf",
+		Run: func(cmd *Command, args []string) { ran = true },
+	}
+	rootCmd.AddCommand(leafCmd)
+	rootCmd.SetErr(new(bytes.Buffer))
+
+	if err := rootCmd.execute([]string{"leaf"}); err == nil || err.Error() != "setup failed" {
+		t.Errorf("execute() error = %v, want setup failed", err)
+	}
+	if ran {
+		t.Error("leaf should not run when an ancestor PreRunE fails")
+	}
+}
+
 func TestCommand_IOFallbackToParent(t *testing.T) {
 	outBuf := new(bytes.Buffer)
 	errBuf := new(bytes.Buffer)
@@ -507,20 +1095,134 @@ func TestCommand_IOFallbackToParent(t *testing.T) {
 }
 
 func TestCommand_Context(t *testing.T) {
-	ctx := map[string]string{"key": "value"}
+	type ctxKey string
+	ctx := context.WithValue(context.Background(), ctxKey("key"), "value")
 	cmd := &Command{Use: "test"}
 
+	if cmd.Context() == nil {
+		t.Error("Expected Context() to default to a non-nil context")
+	}
+
 	cmd.SetContext(ctx)
 
 	result := cmd.Context()
-	if result == nil {
-		t.Error("Expected context to be set")
-	}
-	if resultMap, ok := result.(map[string]string); !ok || resultMap["key"] != "value" {
+	if result.Value(ctxKey("key")) != "value" {
 		t.Error("Expected context to contain correct data")
 	}
 }
 
+func TestCommand_ExecuteContextInheritedBySubcommand(t *testing.T) {
+	type ctxKey string
+	ctx := context.WithValue(context.Background(), ctxKey("key"), "value")
+
+	var got context.Context
+	rootCmd := &Command{Use: "root"}
+	subCmd := &Command{
+		Use: "sub",
+		Run: func(cmd *Command, args []string) {
+			got = cmd.Context()
+		},
+	}
+	rootCmd.AddCommand(subCmd)
+	rootCmd.SetContext(ctx)
+
+	if err := rootCmd.execute([]string{"sub"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if got == nil || got.Value(ctxKey("key")) != "value" {
+		t.Error("Expected subcommand to inherit the root context")
+	}
+}
+
+func TestCommand_ExecuteContextFreshOnEachRun(t *testing.T) {
+	var err error
+	rootCmd := &Command{Use: "root"}
+	subCmd := &Command{
+		Use: "sub",
+		Run: func(cmd *Command, args []string) {
+			err = cmd.Context().Err()
+		},
+	}
+	rootCmd.AddCommand(subCmd)
+	rootCmd.SetArgs([]string{"sub"})
+
+	for run := 1; run <= 2; run++ {
+		if execErr := rootCmd.Execute(); execErr != nil {
+			t.Fatalf("run %d: Execute() error = %v", run, execErr)
+		}
+		if err != nil {
+			t.Errorf("run %d: Context().Err() = %v, want nil", run, err)
+		}
+	}
+}
+
+func TestCommand_RunContextE(t *testing.T) {
+	type ctxKey string
+	ctx := context.WithValue(context.Background(), ctxKey("key"), "value")
+
+	var got context.Context
+	cmd := &Command{
+		Use: "root",
+		RunContextE: func(ctx context.Context, cmd *Command, args []string) error {
+			got = ctx
+			return nil
+		},
+		RunE: func(cmd *Command, args []string) error {
+			t.Error("RunE should not be called when RunContextE is set")
+			return nil
+		},
+	}
+
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+	if got == nil || got.Value(ctxKey("key")) != "value" {
+		t.Error("Expected RunContextE to receive the ExecuteContext context")
+	}
+}
+
+func TestCommand_HookContextVariants(t *testing.T) {
+	type ctxKey string
+	ctx := context.WithValue(context.Background(), ctxKey("key"), "value")
+
+	var executed []string
+	hook := func(name string) func(context.Context, *Command, []string) error {
+		return func(ctx context.Context, cmd *Command, args []string) error {
+			if ctx.Value(ctxKey("key")) != "value" {
+				t.Errorf("%s did not receive the command context", name)
+			}
+			executed = append(executed, name)
+			return nil
+		}
+	}
+
+	rootCmd := &Command{
+		Use:                       "root",
+		PersistentPreRunContextE:  hook("persistent-pre"),
+		PersistentPostRunContextE: hook("persistent-post"),
+	}
+	subCmd := &Command{
+		Use:             "sub",
+		PreRunContextE:  hook("pre"),
+		RunContextE:     hook("run"),
+		PostRunContextE: hook("post"),
+		PreRun: func(cmd *Command, args []string) {
+			t.Error("PreRun should not be called when PreRunContextE is set")
+		},
+	}
+	rootCmd.AddCommand(subCmd)
+	rootCmd.SetContext(ctx)
+
+	if err := rootCmd.execute([]string{"sub"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+
+	want := "persistent-pre,pre,run,post,persistent-post"
+	if strings.Join(executed, ",") != want {
+		t.Errorf("hooks = %v, want %s", executed, want)
+	}
+}
+
 func TestCommand_DisableFlagParsing(t *testing.T) {
 	var receivedArgs []string
 	cmd := &Command{
@@ -540,3 +1242,166 @@ func TestCommand_DisableFlagParsing(t *testing.T) {
 		t.Errorf("Expected 2 args with disabled flag parsing, got %d", len(receivedArgs))
 	}
 }
+
+func TestCommand_Deprecated(t *testing.T) {
+	ran := false
+	stderr := new(bytes.Buffer)
+	rootCmd := &Command{Use: "app"}
+	oldCmd := &Command{
+		Use:        "old",
+		Short:      "Old command",
+		Deprecated: `use "new" instead`,
+		Run:        func(cmd *Command, args []string) { ran = true },
+	}
+	rootCmd.AddCommand(oldCmd, &Command{Use: "new", Short: "New command"})
+	rootCmd.SetOutput(new(bytes.Buffer))
+	rootCmd.SetErr(stderr)
+
+	if err := rootCmd.execute([]string{"old"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if !ran {
+		t.Error("deprecated commands should still run")
+	}
+	if !strings.Contains(stderr.String(), `Command "old" is deprecated: use "new" instead`) {
+		t.Errorf("expected a deprecation warning on stderr, got: %q", stderr.String())
+	}
+
+	for _, line := range strings.Split(rootCmd.ModernHelp(), "\n") {
+		if strings.Contains(line, "Old command") && !strings.Contains(line, "(deprecated)") {
+			t.Errorf("help should tag deprecated commands, got: %q", line)
+		}
+		if strings.Contains(line, "New command") && strings.Contains(line, "(deprecated)") {
+			t.Errorf("help should not tag other commands, got: %q", line)
+		}
+	}
+}
+
+func TestCommand_TraverseChildren(t *testing.T) {
+	newTree := func() (*Command, *bool, *string, *int, *[]string) {
+		var verbose bool
+		var config string
+		var replicas int
+		var gotArgs []string
+		rootCmd := &Command{Use: "app", TraverseChildren: true}
+		rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+		rootCmd.Flags().StringVar(&config, "config", "", "Config file")
+		deployCmd := &Command{
+			Use: "deploy",
+			Run: func(cmd *Command, args []string) { gotArgs = args },
+		}
+		deployCmd.Flags().IntVar(&replicas, "replicas", 1, "Replica count")
+		rootCmd.AddCommand(deployCmd)
+		rootCmd.SetOutput(new(bytes.Buffer))
+		return rootCmd, &verbose, &config, &replicas, &gotArgs
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"flags before subcommand", []string{"--verbose", "--config", "app.yaml", "deploy", "--replicas", "3", "prod"}},
+		{"persistent flag after subcommand", []string{"--config=app.yaml", "deploy", "prod", "-v", "--replicas=3"}},
+		{"shorthand before subcommand", []string{"-v", "--config", "app.yaml", "deploy", "--replicas", "3", "prod"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rootCmd, verbose, config, replicas, gotArgs := newTree()
+
+			if err := rootCmd.execute(tt.args); err != nil {
+				t.Fatalf("execute() error = %v", err)
+			}
+			if !*verbose || *config != "app.yaml" || *replicas != 3 {
+				t.Errorf("flags = verbose:%v config:%q replicas:%d, want true, app.yaml, 3", *verbose, *config, *replicas)
+			}
+			if len(*gotArgs) != 1 || (*gotArgs)[0] != "prod" {
+				t.Errorf("args = %v, want [prod]", *gotArgs)
+			}
+		})
+	}
+}
+
+func TestCommand_TraverseChildrenDisabled(t *testing.T) {
+	ran := false
+	rootCmd := &Command{Use: "app"}
+	rootCmd.PersistentFlags().Bool("verbose", false, "Verbose output")
+	rootCmd.AddCommand(&Command{
+		Use: "deploy",
+		Run: func(cmd *Command, args []string) { ran = true },
+	})
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	rootCmd.execute([]string{"--verbose", "deploy"})
+
+	if ran {
+		t.Error("without TraverseChildren, flags before the subcommand should stop command lookup")
+	}
+}
+
+func TestCommand_TraverseChildrenUnknownCommand(t *testing.T) {
+	rootCmd := &Command{Use: "app", TraverseChildren: true, SilenceUsage: true}
+	rootCmd.PersistentFlags().Bool("verbose", false, "Verbose output")
+	rootCmd.AddCommand(&Command{Use: "deploy", Run: func(cmd *Command, args []string) {}})
+	rootCmd.SetOutput(new(bytes.Buffer))
+	rootCmd.SetErr(new(bytes.Buffer))
+
+	err := rootCmd.execute([]string{"--verbose", "deplyo"})
+	if err == nil || !strings.Contains(err.Error(), `unknown command "deplyo"`) {
+		t.Errorf("expected an unknown command error, got: %v", err)
+	}
+}
+
+func TestCommand_Runnable(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  *Command
+		want bool
+	}{
+		{"no run function", &Command{Use: "remote"}, false},
+		{"Run", &Command{Use: "add", Run: func(cmd *Command, args []string) {}}, true},
+		{"RunE", &Command{Use: "add", RunE: func(cmd *Command, args []string) error { return nil }}, true},
+		{"RunContextE", &Command{Use: "add", RunContextE: func(ctx context.Context, cmd *Command, args []string) error { return nil }}, true},
+		{"only hooks", &Command{Use: "add", PreRun: func(cmd *Command, args []string) {}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cmd.Runnable(); got != tt.want {
+				t.Errorf("Runnable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand_NonRunnableShowsHelp(t *testing.T) {
+	buf := new(bytes.Buffer)
+	preRan := false
+	rootCmd := &Command{Use: "app"}
+	remoteCmd := &Command{
+		Use:    "remote",
+		Short:  "Manage remotes",
+		PreRun: func(cmd *Command, args []string) { preRan = true },
+	}
+	remoteCmd.AddCommand(&Command{Use: "add", Short: "Add a remote", Run: func(cmd *Command, args []string) {}})
+	rootCmd.AddCommand(remoteCmd)
+	rootCmd.SetOutput(buf)
+
+	if err := rootCmd.execute([]string{"remote"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if preRan {
+		t.Error("hooks should not run for a non-runnable command")
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "app remote [command]") {
+		t.Errorf("help should show the [command] usage form, got: %s", output)
+	}
+	if strings.Contains(output, "app remote\n") {
+		t.Errorf("help should omit the bare usage line for non-runnable commands, got: %s", output)
+	}
+	if !strings.Contains(output, "Add a remote") {
+		t.Errorf("help should list subcommands, got: %s", output)
+	}
+}
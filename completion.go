@@ -0,0 +1,676 @@
+package mamba
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// compRequestCmd is the hidden argument the generated shell scripts pass to
+// the program to request dynamic completions
+const compRequestCmd = "__complete"
+
+// CompletionOptions controls the automatic "completion" subcommand
+type CompletionOptions struct {
+	// DisableDefaultCmd prevents the "completion" subcommand from being added
+	DisableDefaultCmd bool
+
+	// HiddenDefaultCmd keeps the "completion" subcommand out of help while
+	// leaving it runnable
+	HiddenDefaultCmd bool
+}
+
+// completionEntry describes the static completion data for one command path
+type completionEntry struct {
+	// path is the space-separated command path below the root ("" for the root)
+	path string
+
+	// commands are the names and aliases of the visible subcommands
+	commands []string
+
+	// flags are the long and shorthand forms of all visible flags
+	flags []string
+
+	// validArgs are the static positional argument candidates
+	validArgs []string
+
+	// dynamic reports whether completion must be delegated to __complete
+	dynamic bool
+}
+
+// completionEntries walks the command tree below c and returns the completion
+// data for every visible command
+func (c *Command) completionEntries() []completionEntry {
+	var entries []completionEntry
+
+	var walk func(cmd *Command, path string)
+	walk = func(cmd *Command, path string) {
+		entry := completionEntry{
+			path:      path,
+			validArgs: cmd.ValidArgs,
+			dynamic:   cmd.ValidArgsFunction != nil || cmd.hasFlagCompletionFuncs(),
+		}
+
+		for _, sub := range cmd.commands {
+			if sub.Hidden {
+				continue
+			}
+			entry.commands = append(entry.commands, sub.Name())
+			entry.commands = append(entry.commands, sub.Aliases...)
+		}
+
+		entry.flags = cmd.completionFlags("")
+		entries = append(entries, entry)
+
+		for _, sub := range cmd.commands {
+			if sub.Hidden {
+				continue
+			}
+			walk(sub, strings.TrimSpace(path+" "+sub.Name()))
+		}
+	}
+	walk(c, "")
+
+	return entries
+}
+
+// completionTransitions returns a mapping from "parentPath:word" to the
+// resulting command path, covering both names and aliases
+func (c *Command) completionTransitions() [][2]string {
+	var transitions [][2]string
+
+	var walk func(cmd *Command, path string)
+	walk = func(cmd *Command, path string) {
+		for _, sub := range cmd.commands {
+			if sub.Hidden {
+				continue
+			}
+			subPath := strings.TrimSpace(path + " " + sub.Name())
+			for _, word := range append([]string{sub.Name()}, sub.Aliases...) {
+				transitions = append(transitions, [2]string{path + ":" + word, subPath})
+			}
+			walk(sub, subPath)
+		}
+	}
+	walk(c, "")
+
+	return transitions
+}
+
+// completionFlags returns the visible flag names for c that start with prefix
+func (c *Command) completionFlags(prefix string) []string {
+	c.mergePersistentFlags()
+	c.InitDefaultHelpFlag()
+	c.InitDefaultVersionFlag()
+
+	var flags []string
+	c.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		for _, name := range []string{"--" + f.Name, "-" + f.Shorthand} {
+			if name == "-" {
+				continue
+			}
+			if strings.HasPrefix(name, prefix) {
+				flags = append(flags, name)
+			}
+		}
+	})
+	sort.Strings(flags)
+	return flags
+}
+
+// complete returns the completion candidates for a command line.
+// args holds the words after the program name; the last element is the
+// (possibly empty) word being completed.
+func (c *Command) complete(args []string) ([]string, error) {
+	toComplete := ""
+	if len(args) > 0 {
+		toComplete = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	cmd, cmdArgs, err := c.Find(args)
+	if err != nil {
+		return nil, err
+	}
+
+	// A flag value is being completed, either as "--flag <value>" or "--flag=<value>"
+	flag, valuePrefix, value := cmd.flagToComplete(cmdArgs, toComplete)
+	if flag != nil && valuePrefix == "" {
+		cmdArgs = cmdArgs[:len(cmdArgs)-1]
+	}
+
+	if flag == nil && strings.HasPrefix(toComplete, "-") {
+		return cmd.completionFlags(toComplete), nil
+	}
+
+	positionals := cmdArgs
+	if !cmd.DisableFlagParsing {
+		cmd.mergePersistentFlags()
+		// Parse errors are expected for partial command lines
+		_ = cmd.Flags().Parse(cmdArgs)
+		positionals = cmd.Flags().Args()
+	}
+
+	if flag != nil {
+		fn := cmd.flagCompletionFunc(flag.Name)
+		if fn == nil {
+			return nil, nil
+		}
+		values, err := cmd.cachedCompletions("--"+flag.Name, positionals, value, fn)
+		if err != nil {
+			return nil, err
+		}
+		completions := make([]string, len(values))
+		for i, v := range values {
+			completions[i] = valuePrefix + v
+		}
+		return completions, nil
+	}
+
+	var completions []string
+	if len(positionals) == 0 {
+		for _, sub := range cmd.commands {
+			if !sub.Hidden && strings.HasPrefix(sub.Name(), toComplete) {
+				completions = append(completions, sub.Name())
+			}
+		}
+	}
+
+	if len(cmd.ValidArgs) > 0 {
+		for _, arg := range cmd.ValidArgs {
+			if strings.HasPrefix(arg, toComplete) {
+				completions = append(completions, arg)
+			}
+		}
+	} else if cmd.ValidArgsFunction != nil {
+		dynamic, err := cmd.cachedCompletions("", positionals, toComplete, cmd.ValidArgsFunction)
+		if err != nil {
+			return nil, err
+		}
+		completions = append(completions, dynamic...)
+	}
+
+	return completions, nil
+}
+
+// now returns the current time; tests replace it to expire cached completions
+var now = time.Now
+
+// cachedCompletion is a completion result and when it stops being reused
+type cachedCompletion struct {
+	values  []string
+	expires time.Time
+}
+
+// cachedCompletions calls fn, or returns its earlier result for the same
+// flag (empty for arguments), args, and toComplete while CompletionCacheTTL
+// has not passed. Errors are not cached.
+func (c *Command) cachedCompletions(flag string, args []string, toComplete string, fn func(*Command, []string, string) ([]string, error)) ([]string, error) {
+	ttl := c.completionCacheTTL()
+	if ttl <= 0 {
+		return fn(c, args, toComplete)
+	}
+
+	key := fmt.Sprintf("%s\x00%q\x00%s", flag, args, toComplete)
+	c.completionCacheMu.Lock()
+	cached, ok := c.completionCache[key]
+	c.completionCacheMu.Unlock()
+	if ok && now().Before(cached.expires) {
+		return cached.values, nil
+	}
+
+	values, err := fn(c, args, toComplete)
+	if err != nil {
+		return nil, err
+	}
+
+	c.completionCacheMu.Lock()
+	defer c.completionCacheMu.Unlock()
+	if c.completionCache == nil {
+		c.completionCache = make(map[string]cachedCompletion)
+	}
+	c.completionCache[key] = cachedCompletion{values: values, expires: now().Add(ttl)}
+	return values, nil
+}
+
+// completionCacheTTL returns the nearest CompletionCacheTTL set on the
+// command or its ancestors
+func (c *Command) completionCacheTTL() time.Duration {
+	for p := c; p != nil; p = p.parent {
+		if p.CompletionCacheTTL > 0 {
+			return p.CompletionCacheTTL
+		}
+	}
+	return 0
+}
+
+// RegisterFlagCompletionFunc registers f to provide completions for the
+// values of the named flag, which must be defined on this command's local or
+// persistent flags
+func (c *Command) RegisterFlagCompletionFunc(flagName string, f func(cmd *Command, args []string, toComplete string) ([]string, error)) error {
+	if c.Flags().Lookup(flagName) == nil && c.PersistentFlags().Lookup(flagName) == nil {
+		return fmt.Errorf("RegisterFlagCompletionFunc: flag '%s' does not exist", flagName)
+	}
+	if _, exists := c.flagCompletionFuncs[flagName]; exists {
+		return fmt.Errorf("RegisterFlagCompletionFunc: flag '%s' already registered", flagName)
+	}
+	if c.flagCompletionFuncs == nil {
+		c.flagCompletionFuncs = make(map[string]func(*Command, []string, string) ([]string, error))
+	}
+	c.flagCompletionFuncs[flagName] = f
+	return nil
+}
+
+// flagCompletionFunc returns the completion function registered for the named
+// flag on the command or, for persistent flags, its ancestors
+func (c *Command) flagCompletionFunc(flagName string) func(*Command, []string, string) ([]string, error) {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if f, ok := cmd.flagCompletionFuncs[flagName]; ok {
+			return f
+		}
+	}
+	return nil
+}
+
+// hasFlagCompletionFuncs reports whether any flag completion functions apply
+// to the command
+func (c *Command) hasFlagCompletionFuncs() bool {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if len(cmd.flagCompletionFuncs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// flagToComplete returns the flag whose value is being completed, if any.
+// For "--flag=<value>" it also returns "--flag=" as the prefix to keep on
+// each candidate; for "--flag <value>" the prefix is empty.
+func (c *Command) flagToComplete(args []string, toComplete string) (*pflag.Flag, string, string) {
+	if c.DisableFlagParsing {
+		return nil, "", toComplete
+	}
+	c.mergePersistentFlags()
+
+	if i := strings.Index(toComplete, "="); i > 0 && strings.HasPrefix(toComplete, "-") {
+		if flag := c.lookupFlagArg(toComplete[:i]); flag != nil {
+			return flag, toComplete[:i+1], toComplete[i+1:]
+		}
+		return nil, "", toComplete
+	}
+
+	if len(args) > 0 {
+		last := args[len(args)-1]
+		if strings.HasPrefix(last, "-") && !strings.Contains(last, "=") {
+			// Flags with a default for the bare form, like bools, take no value
+			if flag := c.lookupFlagArg(last); flag != nil && flag.NoOptDefVal == "" {
+				return flag, "", toComplete
+			}
+		}
+	}
+
+	return nil, "", toComplete
+}
+
+// lookupFlagArg looks up a flag by its command line form, "--name" or "-n"
+func (c *Command) lookupFlagArg(arg string) *pflag.Flag {
+	if name := strings.TrimPrefix(arg, "--"); name != arg {
+		return c.Flags().Lookup(name)
+	}
+	if name := strings.TrimPrefix(arg, "-"); len(name) == 1 {
+		return c.Flags().ShorthandLookup(name)
+	}
+	return nil
+}
+
+// runCompletionRequest handles the hidden __complete request issued by the
+// generated shell scripts, writing one candidate per line
+func (c *Command) runCompletionRequest(args []string) error {
+	completions, err := c.complete(args)
+	if err != nil {
+		return err
+	}
+	for _, completion := range completions {
+		fmt.Fprintln(c.OutOrStdout(), completion)
+	}
+	return nil
+}
+
+// initDefaultCompletionCmd adds the completion command to a root command
+// that has subcommands, unless the user already defined one
+func (c *Command) initDefaultCompletionCmd() {
+	if !c.HasSubCommands() || c.CompletionOptions.DisableDefaultCmd {
+		return
+	}
+	for _, cmd := range c.commands {
+		if cmd.Name() == "completion" {
+			return
+		}
+	}
+
+	name := c.Name()
+	c.AddCommand(&Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate the autocompletion script for the specified shell",
+		Long: fmt.Sprintf(`Generate the autocompletion script for %[1]s for the specified shell.
+
+To load completions in your current shell session:
+
+  bash:        source <(%[1]s completion bash)
+  zsh:         source <(%[1]s completion zsh)
+  fish:        %[1]s completion fish | source
+  powershell:  %[1]s completion powershell | Out-String | Invoke-Expression`, name),
+		Hidden:    c.CompletionOptions.HiddenDefaultCmd,
+		Args:      ExactArgs(1),
+		ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+		RunE: func(cmd *Command, args []string) error {
+			root := cmd.Root()
+			out := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletion(out)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out)
+			case "powershell":
+				return root.GenPowerShellCompletion(out)
+			default:
+				return fmt.Errorf("unsupported shell %q, expected one of: bash, zsh, fish, powershell", args[0])
+			}
+		},
+	})
+}
+
+var nonIdentChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// completionFuncName returns a shell-safe function name for the command
+func (c *Command) completionFuncName() string {
+	return "_" + nonIdentChars.ReplaceAllString(c.Name(), "_")
+}
+
+// GenBashCompletion writes a bash completion script for the command tree to w
+func (c *Command) GenBashCompletion(w io.Writer) error {
+	name := c.Name()
+	fn := c.completionFuncName()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# bash completion for %s\n\n", name)
+	fmt.Fprintf(&sb, "%s_completions()\n{\n", fn)
+	sb.WriteString("    local cur word i cmdpath=\"\" dynamic=0\n")
+	sb.WriteString("    local -a commands=() flags=() validargs=() candidates=()\n")
+	sb.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n\n")
+	sb.WriteString("    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	sb.WriteString("        word=\"${COMP_WORDS[i]}\"\n")
+	sb.WriteString("        [[ \"$word\" == -* ]] && continue\n")
+	sb.WriteString("        case \"$cmdpath:$word\" in\n")
+	for _, t := range c.completionTransitions() {
+		fmt.Fprintf(&sb, "            %s) cmdpath=%s ;;\n", shellQuote(t[0]), shellQuote(t[1]))
+	}
+	sb.WriteString("        esac\n")
+	sb.WriteString("    done\n\n")
+	sb.WriteString("    case \"$cmdpath\" in\n")
+	for _, e := range c.completionEntries() {
+		fmt.Fprintf(&sb, "        %s)\n", shellQuote(e.path))
+		fmt.Fprintf(&sb, "            commands=(%s)\n", shellQuoteAll(e.commands))
+		fmt.Fprintf(&sb, "            flags=(%s)\n", shellQuoteAll(e.flags))
+		fmt.Fprintf(&sb, "            validargs=(%s)\n", shellQuoteAll(e.validArgs))
+		if e.dynamic {
+			sb.WriteString("            dynamic=1\n")
+		}
+		sb.WriteString("            ;;\n")
+	}
+	sb.WriteString("    esac\n\n")
+	sb.WriteString("    if [[ $dynamic -eq 1 ]]; then\n")
+	sb.WriteString("        while IFS= read -r word; do\n")
+	sb.WriteString("            candidates+=(\"$word\")\n")
+	fmt.Fprintf(&sb, "        done < <(\"${COMP_WORDS[0]}\" %s \"${COMP_WORDS[@]:1:COMP_CWORD}\" 2>/dev/null)\n", compRequestCmd)
+	sb.WriteString("    elif [[ \"$cur\" == -* ]]; then\n")
+	sb.WriteString("        candidates=(\"${flags[@]}\")\n")
+	sb.WriteString("    else\n")
+	sb.WriteString("        candidates=(\"${commands[@]}\" \"${validargs[@]}\")\n")
+	sb.WriteString("    fi\n\n")
+	sb.WriteString("    # Candidates are matched literally; compgen -W would expand them\n")
+	sb.WriteString("    COMPREPLY=()\n")
+	sb.WriteString("    for word in \"${candidates[@]}\"; do\n")
+	sb.WriteString("        [[ \"$word\" == \"$cur\"* ]] && COMPREPLY+=(\"$word\")\n")
+	sb.WriteString("    done\n")
+	sb.WriteString("}\n\n")
+	fmt.Fprintf(&sb, "complete -o default -F %s_completions %s\n", fn, name)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// GenZshCompletion writes a zsh completion script for the command tree to w
+func (c *Command) GenZshCompletion(w io.Writer) error {
+	name := c.Name()
+	fn := c.completionFuncName()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "#compdef %s\n\n", name)
+	fmt.Fprintf(&sb, "# zsh completion for %s\n\n", name)
+	fmt.Fprintf(&sb, "%s() {\n", fn)
+	sb.WriteString("    local word i cmdpath=\"\" dynamic=0\n")
+	sb.WriteString("    local -a commands flags validargs completions\n\n")
+	sb.WriteString("    for ((i = 2; i < CURRENT; i++)); do\n")
+	sb.WriteString("        word=\"${words[i]}\"\n")
+	sb.WriteString("        [[ \"$word\" == -* ]] && continue\n")
+	sb.WriteString("        case \"$cmdpath:$word\" in\n")
+	for _, t := range c.completionTransitions() {
+		fmt.Fprintf(&sb, "            %s) cmdpath=%s ;;\n", shellQuote(t[0]), shellQuote(t[1]))
+	}
+	sb.WriteString("        esac\n")
+	sb.WriteString("    done\n\n")
+	sb.WriteString("    case \"$cmdpath\" in\n")
+	for _, e := range c.completionEntries() {
+		fmt.Fprintf(&sb, "        %s)\n", shellQuote(e.path))
+		fmt.Fprintf(&sb, "            commands=(%s)\n", shellQuoteAll(e.commands))
+		fmt.Fprintf(&sb, "            flags=(%s)\n", shellQuoteAll(e.flags))
+		fmt.Fprintf(&sb, "            validargs=(%s)\n", shellQuoteAll(e.validArgs))
+		if e.dynamic {
+			sb.WriteString("            dynamic=1\n")
+		}
+		sb.WriteString("            ;;\n")
+	}
+	sb.WriteString("    esac\n\n")
+	sb.WriteString("    if (( dynamic )); then\n")
+	fmt.Fprintf(&sb, "        completions=(${(f)\"$(${words[1]} %s \"${(@)words[2,CURRENT]}\" 2>/dev/null)\"})\n", compRequestCmd)
+	sb.WriteString("        compadd -a completions\n")
+	sb.WriteString("    elif [[ \"${words[CURRENT]}\" == -* ]]; then\n")
+	sb.WriteString("        compadd -a flags\n")
+	sb.WriteString("    else\n")
+	sb.WriteString("        compadd -a commands validargs\n")
+	sb.WriteString("    fi\n")
+	sb.WriteString("}\n\n")
+	fmt.Fprintf(&sb, "if [ \"$funcstack[1]\" = \"%s\" ]; then\n", fn)
+	fmt.Fprintf(&sb, "    %s \"$@\"\n", fn)
+	sb.WriteString("else\n")
+	fmt.Fprintf(&sb, "    compdef %s %s\n", fn, name)
+	sb.WriteString("fi\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// GenFishCompletion writes a fish completion script for the command tree to w
+func (c *Command) GenFishCompletion(w io.Writer) error {
+	name := c.Name()
+	fn := "_" + c.completionFuncName()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# fish completion for %s\n\n", name)
+	fmt.Fprintf(&sb, "function %s_complete\n", fn)
+	sb.WriteString("    set -l tokens (commandline -opc)\n")
+	sb.WriteString("    set -l current (commandline -ct)\n")
+	sb.WriteString("    set -l program $tokens[1]\n")
+	sb.WriteString("    set -e tokens[1]\n")
+	sb.WriteString("    set -l cmdpath ''\n")
+	sb.WriteString("    set -l commands\n")
+	sb.WriteString("    set -l flags\n")
+	sb.WriteString("    set -l validargs\n")
+	sb.WriteString("    set -l dynamic 0\n\n")
+	sb.WriteString("    for word in $tokens\n")
+	sb.WriteString("        string match -q -- '-*' $word; and continue\n")
+	sb.WriteString("        switch \"$cmdpath:$word\"\n")
+	for _, t := range c.completionTransitions() {
+		fmt.Fprintf(&sb, "            case %s\n", fishQuote(t[0]))
+		fmt.Fprintf(&sb, "                set cmdpath %s\n", fishQuote(t[1]))
+	}
+	sb.WriteString("        end\n")
+	sb.WriteString("    end\n\n")
+	sb.WriteString("    switch \"$cmdpath\"\n")
+	for _, e := range c.completionEntries() {
+		fmt.Fprintf(&sb, "        case %s\n", fishQuote(e.path))
+		fmt.Fprintf(&sb, "            set commands %s\n", fishQuoteAll(e.commands))
+		fmt.Fprintf(&sb, "            set flags %s\n", fishQuoteAll(e.flags))
+		fmt.Fprintf(&sb, "            set validargs %s\n", fishQuoteAll(e.validArgs))
+		if e.dynamic {
+			sb.WriteString("            set dynamic 1\n")
+		}
+	}
+	sb.WriteString("    end\n\n")
+	sb.WriteString("    if test $dynamic -eq 1\n")
+	fmt.Fprintf(&sb, "        $program %s $tokens $current 2>/dev/null\n", compRequestCmd)
+	sb.WriteString("    else if string match -q -- '-*' $current\n")
+	sb.WriteString("        printf '%s\\n' $flags\n")
+	sb.WriteString("    else\n")
+	sb.WriteString("        printf '%s\\n' $commands $validargs\n")
+	sb.WriteString("    end\n")
+	sb.WriteString("end\n\n")
+	fmt.Fprintf(&sb, "complete -c %s -f -a '(%s_complete)'\n", name, fn)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// GenPowerShellCompletion writes a PowerShell completion script for the
+// command tree to w
+func (c *Command) GenPowerShellCompletion(w io.Writer) error {
+	name := c.Name()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# powershell completion for %s\n\n", name)
+	fmt.Fprintf(&sb, "Register-ArgumentCompleter -Native -CommandName %s -ScriptBlock {\n", psQuote(name))
+	sb.WriteString("    param($wordToComplete, $commandAst, $cursorPosition)\n\n")
+	sb.WriteString("    $words = @($commandAst.CommandElements | ForEach-Object { $_.ToString() })\n")
+	sb.WriteString("    if ($wordToComplete -ne '' -and $words.Count -gt 1) {\n")
+	sb.WriteString("        $words = $words[0..($words.Count - 2)]\n")
+	sb.WriteString("    }\n\n")
+	sb.WriteString("    $cmdpath = ''\n")
+	sb.WriteString("    foreach ($word in ($words | Select-Object -Skip 1)) {\n")
+	sb.WriteString("        if ($word.StartsWith('-')) { continue }\n")
+	sb.WriteString("        switch -exact -casesensitive (\"${cmdpath}:${word}\") {\n")
+	for _, t := range c.completionTransitions() {
+		fmt.Fprintf(&sb, "            %s { $cmdpath = %s }\n", psQuote(t[0]), psQuote(t[1]))
+	}
+	sb.WriteString("        }\n")
+	sb.WriteString("    }\n\n")
+	sb.WriteString("    $commands = @(); $flags = @(); $validArgs = @(); $dynamic = $false\n")
+	sb.WriteString("    switch -exact -casesensitive ($cmdpath) {\n")
+	for _, e := range c.completionEntries() {
+		fmt.Fprintf(&sb, "        %s {\n", psQuote(e.path))
+		fmt.Fprintf(&sb, "            $commands = @(%s)\n", psQuoteAll(e.commands))
+		fmt.Fprintf(&sb, "            $flags = @(%s)\n", psQuoteAll(e.flags))
+		fmt.Fprintf(&sb, "            $validArgs = @(%s)\n", psQuoteAll(e.validArgs))
+		if e.dynamic {
+			sb.WriteString("            $dynamic = $true\n")
+		}
+		sb.WriteString("        }\n")
+	}
+	sb.WriteString("    }\n\n")
+	sb.WriteString("    if ($dynamic) {\n")
+	fmt.Fprintf(&sb, "        $candidates = & $words[0] %s @($words | Select-Object -Skip 1) $wordToComplete 2>$null\n", compRequestCmd)
+	sb.WriteString("    } elseif ($wordToComplete.StartsWith('-')) {\n")
+	sb.WriteString("        $candidates = $flags\n")
+	sb.WriteString("    } else {\n")
+	sb.WriteString("        $candidates = $commands + $validArgs\n")
+	sb.WriteString("    }\n\n")
+	sb.WriteString("    $candidates | Where-Object { $_ -like \"$wordToComplete*\" } | ForEach-Object {\n")
+	sb.WriteString("        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)\n")
+	sb.WriteString("    }\n")
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// GenCompletionListing writes a machine-readable listing of the visible
+// command tree to w for tooling, one tab-separated line per command:
+//
+//	app remote add	Add a remote	branch,help,verbose
+//
+// The fields are the command path, the short description, and the
+// comma-separated names of the visible flags, sorted. Tabs and newlines in
+// the description are replaced by spaces.
+func (c *Command) GenCompletionListing(w io.Writer) error {
+	clean := strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+
+	var sb strings.Builder
+	var walk func(cmd *Command)
+	walk = func(cmd *Command) {
+		var flags []string
+		for _, name := range cmd.completionFlags("--") {
+			flags = append(flags, strings.TrimPrefix(name, "--"))
+		}
+		fmt.Fprintf(&sb, "%s\t%s\t%s\n", cmd.CommandPath(), clean.Replace(cmd.Short), strings.Join(flags, ","))
+
+		for _, sub := range cmd.commands {
+			if !sub.Hidden {
+				walk(sub)
+			}
+		}
+	}
+	walk(c)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// fishQuote quotes s as a single-quoted fish string
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func fishQuoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fishQuote(v)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote quotes s as a single-quoted bash or zsh word, so characters
+// such as $ and backticks are taken literally
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func shellQuoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = shellQuote(v)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// psQuote quotes s as a single-quoted PowerShell string
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func psQuoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = psQuote(v)
+	}
+	return strings.Join(quoted, ", ")
+}
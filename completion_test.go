@@ -0,0 +1,364 @@
+package mamba
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newCompletionTestTree() *Command {
+	rootCmd := &Command{Use: "app"}
+	rootCmd.PersistentFlags().Bool("verbose", false, "Verbose output")
+
+	remoteCmd := &Command{
+		Use:     "remote",
+		Aliases: []string{"rmt"},
+		Run:     func(cmd *Command, args []string) {},
+	}
+	addCmd := &Command{
+		Use:       "add",
+		ValidArgs: []string{"origin", "upstream"},
+		Run:       func(cmd *Command, args []string) {},
+	}
+	addCmd.Flags().StringP("branch", "b", "", "Branch to track")
+
+	getCmd := &Command{
+		Use: "get",
+		ValidArgsFunction: func(cmd *Command, args []string, toComplete string) ([]string, error) {
+			return []string{"alpha", "beta"}, nil
+		},
+		Run: func(cmd *Command, args []string) {},
+	}
+
+	remoteCmd.AddCommand(addCmd, getCmd)
+	rootCmd.AddCommand(remoteCmd)
+	return rootCmd
+}
+
+func TestCommand_GenCompletion(t *testing.T) {
+	generators := map[string]func(*Command, *bytes.Buffer) error{
+		"bash":       func(c *Command, b *bytes.Buffer) error { return c.GenBashCompletion(b) },
+		"zsh":        func(c *Command, b *bytes.Buffer) error { return c.GenZshCompletion(b) },
+		"fish":       func(c *Command, b *bytes.Buffer) error { return c.GenFishCompletion(b) },
+		"powershell": func(c *Command, b *bytes.Buffer) error { return c.GenPowerShellCompletion(b) },
+	}
+
+	for shell, gen := range generators {
+		t.Run(shell, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := gen(newCompletionTestTree(), buf); err != nil {
+				t.Fatalf("completion generation error = %v", err)
+			}
+
+			script := buf.String()
+			for _, want := range []string{"remote", "rmt", "add", "get", "--branch", "-b", "--verbose", "origin", compRequestCmd} {
+				if !strings.Contains(script, want) {
+					t.Errorf("%s completion should contain %q", shell, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCommand_GenCompletionListing(t *testing.T) {
+	rootCmd := newCompletionTestTree()
+	rootCmd.Short = "My application"
+	rootCmd.AddCommand(&Command{Use: "debug", Hidden: true})
+	rootCmd.commands[0].Short = "Manage\tremotes"
+
+	buf := new(bytes.Buffer)
+	if err := rootCmd.GenCompletionListing(buf); err != nil {
+		t.Fatalf("GenCompletionListing() error = %v", err)
+	}
+
+	want := strings.Join([]string{
+		"app\tMy application\thelp,verbose",
+		"app remote\tManage remotes\thelp,verbose",
+		"app remote add\t\tbranch,help,verbose",
+		"app remote get\t\thelp,verbose",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("GenCompletionListing() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCommand_CompletionCommand(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd := newCompletionTestTree()
+	rootCmd.SetOutput(buf)
+
+	if err := rootCmd.execute([]string{"completion", "bash"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "complete -o default -F _app_completions app") {
+		t.Errorf("Expected bash completion script, got: %s", buf.String())
+	}
+
+	var completionCmd *Command
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == "completion" {
+			completionCmd = cmd
+		}
+	}
+	if completionCmd == nil || completionCmd.Hidden {
+		t.Error("Expected a visible completion command to be added")
+	}
+}
+
+func TestCommand_CompletionOptionsHiddenDefaultCmd(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd := newCompletionTestTree()
+	rootCmd.CompletionOptions.HiddenDefaultCmd = true
+	rootCmd.SetOutput(buf)
+
+	if err := rootCmd.execute([]string{"--help"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "completion") {
+		t.Errorf("help should not list the hidden completion command, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := rootCmd.execute([]string{"completion", "bash"}); err != nil {
+		t.Fatalf("hidden completion command should still run, got error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "complete -o default") {
+		t.Errorf("Expected bash completion script, got: %s", buf.String())
+	}
+}
+
+func TestCommand_CompletionOptionsDisableDefaultCmd(t *testing.T) {
+	rootCmd := newCompletionTestTree()
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.SetOutput(new(bytes.Buffer))
+	rootCmd.SetErr(new(bytes.Buffer))
+
+	if err := rootCmd.execute([]string{"completion", "bash"}); err == nil {
+		t.Error("completion should be an unknown command when the default command is disabled")
+	}
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == "completion" {
+			t.Error("Expected no completion command to be added")
+		}
+	}
+}
+
+func TestCommand_CompletionRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"subcommands", []string{"remote", ""}, []string{"add", "get"}},
+		{"subcommand prefix", []string{"remote", "a"}, []string{"add"}},
+		{"valid args", []string{"remote", "add", "o"}, []string{"origin"}},
+		{"dynamic args", []string{"remote", "get", ""}, []string{"alpha", "beta"}},
+		{"flags", []string{"remote", "add", "--b"}, []string{"--branch"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			rootCmd := newCompletionTestTree()
+			rootCmd.SetOutput(buf)
+
+			if err := rootCmd.execute(append([]string{compRequestCmd}, tt.args...)); err != nil {
+				t.Fatalf("execute() error = %v", err)
+			}
+
+			got := strings.Fields(buf.String())
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("completions for %v = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand_CompletionRequestDefaultFlags(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd := &Command{Use: "app", Version: "1.0.0", EnableQuiet: true}
+	rootCmd.AddCommand(&Command{Use: "deploy", Run: func(cmd *Command, args []string) {}})
+	rootCmd.SetOutput(buf)
+
+	if err := rootCmd.execute([]string{compRequestCmd, "--"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	for _, want := range []string{"--help", "--quiet", "--version"} {
+		if !strings.Contains(buf.String(), want+"\n") {
+			t.Errorf("root flag completions should offer %s, got: %q", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := rootCmd.execute([]string{compRequestCmd, "deploy", "--q"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if got := buf.String(); got != "--quiet\n" {
+		t.Errorf("subcommand flag completions = %q, want the inherited --quiet", got)
+	}
+}
+
+func TestCommand_GenCompletionShellQuoting(t *testing.T) {
+	rootCmd := &Command{Use: "app"}
+	rootCmd.AddCommand(&Command{
+		Use:       "run",
+		ValidArgs: []string{"$HOME", "`id`", "it's"},
+		Run:       func(cmd *Command, args []string) {},
+	})
+
+	tests := map[string]struct {
+		gen  func(*Command, *bytes.Buffer) error
+		want string
+	}{
+		"bash": {func(c *Command, b *bytes.Buffer) error { return c.GenBashCompletion(b) }, `validargs=('$HOME' '` + "`id`" + `' 'it'\''s')`},
+		"zsh":  {func(c *Command, b *bytes.Buffer) error { return c.GenZshCompletion(b) }, `validargs=('$HOME' '` + "`id`" + `' 'it'\''s')`},
+	}
+	for shell, tt := range tests {
+		t.Run(shell, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := tt.gen(rootCmd, buf); err != nil {
+				t.Fatalf("completion generation error = %v", err)
+			}
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("%s script should single-quote values as %s, got:\n%s", shell, tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestCommand_RegisterFlagCompletionFunc(t *testing.T) {
+	newTree := func() *Command {
+		rootCmd := newCompletionTestTree()
+		remoteCmd := rootCmd.Commands()[0]
+		remoteCmd.PersistentFlags().String("region", "", "Cloud region")
+		err := remoteCmd.RegisterFlagCompletionFunc("region", func(cmd *Command, args []string, toComplete string) ([]string, error) {
+			var regions []string
+			for _, r := range []string{"us-east-1", "us-west-2", "eu-west-1"} {
+				if strings.HasPrefix(r, toComplete) {
+					regions = append(regions, r)
+				}
+			}
+			return regions, nil
+		})
+		if err != nil {
+			t.Fatalf("RegisterFlagCompletionFunc() error = %v", err)
+		}
+		return rootCmd
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"separate value", []string{"remote", "add", "--region", "us"}, []string{"us-east-1", "us-west-2"}},
+		{"inline value", []string{"remote", "add", "--region=eu"}, []string{"--region=eu-west-1"}},
+		{"on parent", []string{"remote", "--region", ""}, []string{"us-east-1", "us-west-2", "eu-west-1"}},
+		{"flag without func", []string{"remote", "add", "--branch", ""}, nil},
+		{"after bool flag", []string{"remote", "add", "--verbose", "o"}, []string{"origin"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			rootCmd := newTree()
+			rootCmd.SetOutput(buf)
+
+			if err := rootCmd.execute(append([]string{compRequestCmd}, tt.args...)); err != nil {
+				t.Fatalf("execute() error = %v", err)
+			}
+
+			got := strings.Fields(buf.String())
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("completions for %v = %v, want %v", tt.args, got, tt.want)
+			}
+		})
+	}
+
+	// Scripts delegate to the program for commands with flag completion funcs
+	buf := new(bytes.Buffer)
+	if err := newTree().GenBashCompletion(buf); err != nil {
+		t.Fatalf("GenBashCompletion() error = %v", err)
+	}
+	script := buf.String()
+	entry := script[strings.Index(script, `        'remote add')`):]
+	entry = entry[:strings.Index(entry, ";;")]
+	if !strings.Contains(entry, "dynamic=1") {
+		t.Errorf("bash script should delegate flag value completion for remote add, got: %s", entry)
+	}
+}
+
+func TestCommand_RegisterFlagCompletionFuncErrors(t *testing.T) {
+	cmd := &Command{Use: "app"}
+	cmd.Flags().String("region", "", "Cloud region")
+	fn := func(cmd *Command, args []string, toComplete string) ([]string, error) { return nil, nil }
+
+	if err := cmd.RegisterFlagCompletionFunc("missing", fn); err == nil {
+		t.Error("registering a completion func for an unknown flag should error")
+	}
+	if err := cmd.RegisterFlagCompletionFunc("region", fn); err != nil {
+		t.Fatalf("RegisterFlagCompletionFunc() error = %v", err)
+	}
+	if err := cmd.RegisterFlagCompletionFunc("region", fn); err == nil {
+		t.Error("registering a second completion func for the same flag should error")
+	}
+}
+
+func TestCommand_CompletionCacheTTL(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	originalNow := now
+	now = func() time.Time { return clock }
+	t.Cleanup(func() { now = originalNow })
+
+	argCalls, flagCalls := 0, 0
+	rootCmd := &Command{Use: "app", CompletionCacheTTL: time.Minute}
+	deployCmd := &Command{
+		Use: "deploy",
+		ValidArgsFunction: func(cmd *Command, args []string, toComplete string) ([]string, error) {
+			argCalls++
+			return []string{"api", "web"}, nil
+		},
+		Run: func(cmd *Command, args []string) {},
+	}
+	deployCmd.Flags().String("region", "", "Cloud region")
+	deployCmd.RegisterFlagCompletionFunc("region", func(cmd *Command, args []string, toComplete string) ([]string, error) {
+		flagCalls++
+		return []string{"eu-west-1"}, nil
+	})
+	rootCmd.AddCommand(deployCmd)
+
+	complete := func(args ...string) string {
+		t.Helper()
+		buf := new(bytes.Buffer)
+		rootCmd.SetOutput(buf)
+		if err := rootCmd.execute(append([]string{compRequestCmd}, args...)); err != nil {
+			t.Fatalf("execute() error = %v", err)
+		}
+		return buf.String()
+	}
+
+	complete("deploy", "")
+	if got := complete("deploy", ""); !strings.Contains(got, "api") {
+		t.Errorf("cached completions = %q, want api", got)
+	}
+	complete("deploy", "--region", "")
+	complete("deploy", "--region", "")
+	if argCalls != 1 || flagCalls != 1 {
+		t.Errorf("calls within the TTL = %d args, %d flags, want 1 each", argCalls, flagCalls)
+	}
+
+	// Different input is not served from the cache
+	complete("deploy", "a")
+	if argCalls != 2 {
+		t.Errorf("calls for new input = %d, want 2", argCalls)
+	}
+
+	clock = clock.Add(time.Minute)
+	complete("deploy", "")
+	complete("deploy", "--region", "")
+	if argCalls != 3 || flagCalls != 2 {
+		t.Errorf("calls after the TTL = %d args, %d flags, want 3 and 2", argCalls, flagCalls)
+	}
+}
@@ -0,0 +1,134 @@
+package mamba
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// BindConfig loads flag values from the JSON or YAML file at path, keyed by
+// flag name. When the command or one of its subcommands runs, each value is
+// applied to the matching flag unless it was set on the command line. Call it
+// before Execute, or from an OnInitialize callback to take the path from a
+// flag.
+func (c *Command) BindConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]interface{}{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		err = dec.Decode(&values)
+	} else {
+		err = yaml.Unmarshal(data, &values)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	c.configPath = path
+	c.config = values
+	return nil
+}
+
+// BindEnv binds the named flag of the command and its subcommands to the
+// environment variable envVar, which is used when the flag is not set on the
+// command line
+func (c *Command) BindEnv(flagName, envVar string) {
+	if c.envBindings == nil {
+		c.envBindings = map[string]string{}
+	}
+	c.envBindings[flagName] = envVar
+}
+
+// SetEnvPrefix binds every flag of the command and its subcommands to an
+// environment variable named by the prefix and the flag name, uppercased
+// with dashes replaced by underscores. For example, with the prefix "myapp"
+// the flag --dry-run reads MYAPP_DRY_RUN.
+func (c *Command) SetEnvPrefix(prefix string) {
+	c.envPrefix = prefix
+}
+
+// flagEnvVar returns the environment variable bound to the named flag by the
+// command or its nearest ancestor with a binding or prefix
+func (c *Command) flagEnvVar(name string) string {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if envVar, ok := cmd.envBindings[name]; ok {
+			return envVar
+		}
+		if cmd.envPrefix != "" {
+			return strings.ToUpper(strings.TrimSuffix(cmd.envPrefix, "_") + "_" + strings.ReplaceAll(name, "-", "_"))
+		}
+	}
+	return ""
+}
+
+// applyFlagBindings sets the flags of the command that were not set on the
+// command line from their bound environment variables or, failing that, the
+// config files bound to the command or its ancestors, where the closest
+// command wins
+func (c *Command) applyFlagBindings() error {
+	if c.DisableFlagParsing {
+		return nil
+	}
+
+	var err error
+	c.boundFlags = make(map[string]bool)
+	c.Flags().VisitAll(func(f *pflag.Flag) {
+		if err != nil || f.Changed {
+			return
+		}
+		if envVar := c.flagEnvVar(f.Name); envVar != "" {
+			if v, ok := os.LookupEnv(envVar); ok {
+				if setErr := f.Value.Set(v); setErr != nil {
+					err = fmt.Errorf("invalid value for flag --%s in $%s: %w", f.Name, envVar, setErr)
+				}
+				c.boundFlags[f.Name] = true
+				return
+			}
+		}
+		for cmd := c; cmd != nil; cmd = cmd.parent {
+			if v, ok := cmd.config[f.Name]; ok {
+				if setErr := setFlagValue(f, v); setErr != nil {
+					err = fmt.Errorf("invalid value for flag --%s in %s: %w", f.Name, cmd.configPath, setErr)
+				}
+				c.boundFlags[f.Name] = true
+				return
+			}
+		}
+	})
+	return err
+}
+
+// flagProvided reports whether f was set on the command line or from a bound
+// environment variable or config file
+func (c *Command) flagProvided(f *pflag.Flag) bool {
+	return f.Changed || c.boundFlags[f.Name]
+}
+
+// setFlagValue sets f from a decoded config value. Lists replace the values
+// of slice flags.
+func setFlagValue(f *pflag.Flag, v interface{}) error {
+	list, isList := v.([]interface{})
+	if !isList {
+		return f.Value.Set(fmt.Sprint(v))
+	}
+
+	items := make([]string, len(list))
+	for i, item := range list {
+		items[i] = fmt.Sprint(item)
+	}
+	if sv, ok := f.Value.(pflag.SliceValue); ok {
+		return sv.Replace(items)
+	}
+	return f.Value.Set(strings.Join(items, ","))
+}
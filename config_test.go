@@ -0,0 +1,302 @@
+package mamba
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes content to a file named name in a temporary directory
+// and returns its path
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+type configTestValues struct {
+	region   string
+	replicas int
+	tags     []string
+	force    bool
+}
+
+// newConfigTestCommand returns a root with a persistent region flag and a
+// deploy subcommand with local flags, recording their values when run
+func newConfigTestCommand(got *configTestValues) (*Command, *Command) {
+	rootCmd := &Command{Use: "app", SilenceUsage: true}
+	rootCmd.PersistentFlags().String("region", "us-east-1", "Region")
+
+	deployCmd := &Command{
+		Use: "deploy",
+		Run: func(cmd *Command, args []string) {
+			got.region, _ = cmd.Flags().GetString("region")
+			got.replicas, _ = cmd.Flags().GetInt("replicas")
+			got.tags, _ = cmd.Flags().GetStringSlice("tags")
+			got.force, _ = cmd.Flags().GetBool("force")
+		},
+	}
+	deployCmd.Flags().Int("replicas", 1, "Replicas")
+	deployCmd.Flags().StringSlice("tags", nil, "Tags")
+	deployCmd.Flags().Bool("force", false, "Force")
+	rootCmd.AddCommand(deployCmd)
+	rootCmd.SetErr(new(bytes.Buffer))
+	return rootCmd, deployCmd
+}
+
+func TestCommand_BindConfig(t *testing.T) {
+	for _, tt := range []struct {
+		name, file, content string
+	}{
+		{"json", "config.json", `{"region": "eu-west-1", "replicas": 3, "tags": ["web", "prod"], "force": true, "unknown": 1}`},
+		{"yaml", "config.yaml", "region: eu-west-1\nreplicas: 3\ntags: [web, prod]\nforce: true\nunknown: 1\n"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var got configTestValues
+			rootCmd, _ := newConfigTestCommand(&got)
+			if err := rootCmd.BindConfig(writeConfig(t, tt.file, tt.content)); err != nil {
+				t.Fatalf("BindConfig() error = %v", err)
+			}
+
+			if err := rootCmd.execute([]string{"deploy"}); err != nil {
+				t.Fatalf("execute() error = %v", err)
+			}
+
+			want := configTestValues{region: "eu-west-1", replicas: 3, tags: []string{"web", "prod"}, force: true}
+			if got.region != want.region || got.replicas != want.replicas || strings.Join(got.tags, ",") != "web,prod" || got.force != want.force {
+				t.Errorf("flag values = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestCommand_BindConfigCommandLineWins(t *testing.T) {
+	var got configTestValues
+	rootCmd, _ := newConfigTestCommand(&got)
+	if err := rootCmd.BindConfig(writeConfig(t, "config.yaml", "region: eu-west-1\nreplicas: 3\ntags: [web]\n")); err != nil {
+		t.Fatalf("BindConfig() error = %v", err)
+	}
+
+	if err := rootCmd.execute([]string{"deploy", "--region", "ap-south-1", "--tags", "cli"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+
+	if got.region != "ap-south-1" {
+		t.Errorf("region = %q, want the command-line value", got.region)
+	}
+	if strings.Join(got.tags, ",") != "cli" {
+		t.Errorf("tags = %v, want only the command-line value", got.tags)
+	}
+	if got.replicas != 3 {
+		t.Errorf("replicas = %d, want 3 from the config", got.replicas)
+	}
+}
+
+func TestCommand_BindConfigClosestWins(t *testing.T) {
+	var got configTestValues
+	rootCmd, deployCmd := newConfigTestCommand(&got)
+	if err := rootCmd.BindConfig(writeConfig(t, "root.yaml", "region: eu-west-1\nreplicas: 3\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := deployCmd.BindConfig(writeConfig(t, "deploy.yaml", "replicas: 5\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootCmd.execute([]string{"deploy"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+
+	if got.region != "eu-west-1" || got.replicas != 5 {
+		t.Errorf("region = %q, replicas = %d, want eu-west-1 and 5", got.region, got.replicas)
+	}
+}
+
+func TestCommand_BindConfigFromInitializer(t *testing.T) {
+	withInitializers(t)
+
+	var got configTestValues
+	rootCmd, _ := newConfigTestCommand(&got)
+	rootCmd.PersistentFlags().String("config", "", "Config file")
+	path := writeConfig(t, "config.yaml", "replicas: 4\n")
+
+	OnInitialize(func() {
+		if file, _ := rootCmd.PersistentFlags().GetString("config"); file != "" {
+			if err := rootCmd.BindConfig(file); err != nil {
+				t.Errorf("BindConfig() error = %v", err)
+			}
+		}
+	})
+
+	if err := rootCmd.execute([]string{"deploy", "--config", path}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if got.replicas != 4 {
+		t.Errorf("replicas = %d, want 4 from the config", got.replicas)
+	}
+}
+
+func TestCommand_BindConfigInvalidValue(t *testing.T) {
+	var got configTestValues
+	rootCmd, _ := newConfigTestCommand(&got)
+	path := writeConfig(t, "config.json", `{"replicas": "many"}`)
+	if err := rootCmd.BindConfig(path); err != nil {
+		t.Fatalf("BindConfig() error = %v", err)
+	}
+
+	err := rootCmd.execute([]string{"deploy"})
+	if err == nil || !strings.Contains(err.Error(), "invalid value for flag --replicas in "+path) {
+		t.Errorf("execute() error = %v, want an invalid value error", err)
+	}
+}
+
+func TestCommand_BindConfigErrors(t *testing.T) {
+	cmd := &Command{Use: "app"}
+
+	if err := cmd.BindConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("BindConfig() should fail for a missing file")
+	}
+	if err := cmd.BindConfig(writeConfig(t, "bad.json", `{"region":`)); err == nil || !strings.Contains(err.Error(), "invalid config file") {
+		t.Errorf("BindConfig() error = %v, want an invalid config file error", err)
+	}
+}
+
+func TestCommand_BindEnv(t *testing.T) {
+	t.Setenv("DEPLOY_REGION", "eu-west-1")
+	t.Setenv("DEPLOY_TAGS", "web,prod")
+
+	var got configTestValues
+	rootCmd, deployCmd := newConfigTestCommand(&got)
+	rootCmd.BindEnv("region", "DEPLOY_REGION")
+	deployCmd.BindEnv("tags", "DEPLOY_TAGS")
+	deployCmd.BindEnv("replicas", "DEPLOY_REPLICAS_UNSET")
+
+	if err := rootCmd.execute([]string{"deploy"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+
+	if got.region != "eu-west-1" {
+		t.Errorf("region = %q, want eu-west-1 from the environment", got.region)
+	}
+	if strings.Join(got.tags, ",") != "web,prod" {
+		t.Errorf("tags = %v, want [web prod] from the environment", got.tags)
+	}
+	if got.replicas != 1 {
+		t.Errorf("replicas = %d, want the default when the variable is unset", got.replicas)
+	}
+}
+
+func TestCommand_SetEnvPrefix(t *testing.T) {
+	t.Setenv("MYAPP_REGION", "eu-west-1")
+	t.Setenv("MYAPP_REPLICAS", "7")
+	t.Setenv("MYAPP_FORCE", "true")
+
+	var got configTestValues
+	rootCmd, _ := newConfigTestCommand(&got)
+	rootCmd.SetEnvPrefix("myapp")
+
+	if err := rootCmd.execute([]string{"deploy", "--region", "ap-south-1"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+
+	if got.region != "ap-south-1" {
+		t.Errorf("region = %q, want the command-line value over the environment", got.region)
+	}
+	if got.replicas != 7 || !got.force {
+		t.Errorf("replicas = %d, force = %v, want 7 and true from the environment", got.replicas, got.force)
+	}
+}
+
+func TestCommand_FlagEnvVar(t *testing.T) {
+	rootCmd := &Command{Use: "app"}
+	subCmd := &Command{Use: "sub"}
+	rootCmd.AddCommand(subCmd)
+
+	if got := subCmd.flagEnvVar("dry-run"); got != "" {
+		t.Errorf("flagEnvVar() without bindings = %q, want empty", got)
+	}
+
+	rootCmd.SetEnvPrefix("myapp_")
+	rootCmd.BindEnv("token", "API_TOKEN")
+	for name, want := range map[string]string{
+		"dry-run": "MYAPP_DRY_RUN",
+		"token":   "API_TOKEN",
+	} {
+		if got := subCmd.flagEnvVar(name); got != want {
+			t.Errorf("flagEnvVar(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestCommand_EnvOverridesConfig(t *testing.T) {
+	t.Setenv("MYAPP_REPLICAS", "9")
+
+	var got configTestValues
+	rootCmd, _ := newConfigTestCommand(&got)
+	rootCmd.SetEnvPrefix("MYAPP")
+	if err := rootCmd.BindConfig(writeConfig(t, "config.yaml", "region: eu-west-1\nreplicas: 3\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rootCmd.execute([]string{"deploy"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+
+	if got.replicas != 9 {
+		t.Errorf("replicas = %d, want 9 from the environment over the config", got.replicas)
+	}
+	if got.region != "eu-west-1" {
+		t.Errorf("region = %q, want eu-west-1 from the config", got.region)
+	}
+}
+
+func TestCommand_BindEnvInvalidValue(t *testing.T) {
+	t.Setenv("MYAPP_REPLICAS", "many")
+
+	var got configTestValues
+	rootCmd, _ := newConfigTestCommand(&got)
+	rootCmd.SetEnvPrefix("MYAPP")
+
+	err := rootCmd.execute([]string{"deploy"})
+	if err == nil || !strings.Contains(err.Error(), "invalid value for flag --replicas in $MYAPP_REPLICAS") {
+		t.Errorf("execute() error = %v, want an invalid value error", err)
+	}
+}
+
+func TestCommand_BoundFlagsCountAsSet(t *testing.T) {
+	t.Setenv("APP_NAME", "bob")
+
+	var name string
+	rootCmd := &Command{
+		Use: "app",
+		Run: func(cmd *Command, args []string) {
+			name, _ = cmd.Flags().GetString("name")
+		},
+	}
+	rootCmd.Flags().String("name", "", "Name")
+	rootCmd.Flags().String("user", "", "User")
+	rootCmd.Flags().String("password", "", "Password")
+	rootCmd.MarkFlagRequired("name")
+	rootCmd.MarkFlagsRequiredTogether("user", "password")
+	rootCmd.SetEnvPrefix("app")
+	rootCmd.SetOutput(new(bytes.Buffer))
+	if err := rootCmd.BindConfig(writeConfig(t, "config.yaml", "user: admin\n")); err != nil {
+		t.Fatalf("BindConfig() error = %v", err)
+	}
+
+	err := rootCmd.execute([]string{})
+	if err == nil || !strings.Contains(err.Error(), "missing [password]") {
+		t.Fatalf("execute() error = %v, want the config value to count toward the group", err)
+	}
+
+	if err := rootCmd.execute([]string{"--password", "secret"}); err != nil {
+		t.Fatalf("execute() error = %v, want the environment to satisfy the required flag", err)
+	}
+	if name != "bob" {
+		t.Errorf("name = %q, want %q from the environment", name, "bob")
+	}
+}
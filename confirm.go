@@ -0,0 +1,92 @@
+package mamba
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/base-go/mamba/pkg/interactive"
+	"github.com/base-go/mamba/pkg/spinner"
+)
+
+// askConfirm prompts for a yes/no answer. It is a variable so tests can
+// answer the prompt.
+var askConfirm = interactive.AskConfirm
+
+// AddConfirmFlags adds the persistent --yes/-y and --no-input flags used by
+// ConfirmDestructive. The -y shorthand is only used if it is free.
+func (c *Command) AddConfirmFlags() {
+	if c.PersistentFlags().Lookup("yes") == nil {
+		usage := "answer yes to all confirmation prompts"
+		if c.PersistentFlags().ShorthandLookup("y") == nil && c.LocalFlags().ShorthandLookup("y") == nil {
+			c.PersistentFlags().BoolP("yes", "y", false, usage)
+		} else {
+			c.PersistentFlags().Bool("yes", false, usage)
+		}
+	}
+	if c.PersistentFlags().Lookup("no-input") == nil {
+		c.PersistentFlags().Bool("no-input", false, "never prompt; fail if a confirmation is required")
+	}
+}
+
+// ConfirmDestructive warns that action is about to be performed and asks the
+// user to confirm it. With --yes it returns true without prompting. With
+// --no-input, or when input is not a terminal, it returns false and an error
+// explaining that confirmation was required.
+//
+// Example:
+//
+//	ok, err := cmd.ConfirmDestructive("delete 3 databases")
+//	if err != nil || !ok {
+//		return err
+//	}
+func (c *Command) ConfirmDestructive(action string) (bool, error) {
+	if c.inheritedFlagSet("yes") {
+		return true, nil
+	}
+	if c.inheritedFlagSet("no-input") || !isInputTerminal(c.InOrStdin()) {
+		return false, fmt.Errorf("confirmation required to %s; pass --yes to proceed", action)
+	}
+
+	c.PrintWarning("This will " + action)
+	return askConfirm("Are you sure?", false)
+}
+
+// RunGuarded asks the user to confirm confirmMsg like ConfirmDestructive,
+// then runs fn behind a spinner showing spinnerMsg. The spinner ends with
+// successMsg, or with the error fn returned, which RunGuarded also returns.
+// When the user declines, fn is not run and the result is nil.
+//
+// Example:
+//
+//	return cmd.RunGuarded("drop the staging database", "Dropping...", "Dropped staging", func() error {
+//		return db.Drop("staging")
+//	})
+func (c *Command) RunGuarded(confirmMsg, spinnerMsg, successMsg string, fn func() error) error {
+	ok, err := c.ConfirmDestructive(confirmMsg)
+	if err != nil || !ok {
+		return err
+	}
+
+	s := spinner.New(spinnerMsg)
+	if c.machineOutput() {
+		s.SetOutput(io.Discard)
+	} else {
+		s.SetOutput(c.OutOrStdout())
+	}
+	s.Start()
+	if err := fn(); err != nil {
+		s.Fail(err)
+		s.Wait()
+		return err
+	}
+	s.Succeed(successMsg)
+	s.Wait()
+	return nil
+}
+
+// inheritedFlagSet reports whether the named flag, defined on the command or
+// as a persistent flag on an ancestor, was set to true
+func (c *Command) inheritedFlagSet(name string) bool {
+	flag := c.Flag(name)
+	return flag != nil && flag.Changed && flag.Value.String() == "true"
+}
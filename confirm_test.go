@@ -0,0 +1,159 @@
+package mamba
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func newConfirmTestTree(confirmed *bool, confirmErr *error) *Command {
+	rootCmd := &Command{Use: "app"}
+	rootCmd.AddConfirmFlags()
+	rootCmd.AddCommand(&Command{
+		Use: "rm",
+		Run: func(cmd *Command, args []string) {
+			*confirmed, *confirmErr = cmd.ConfirmDestructive("delete everything")
+		},
+	})
+	rootCmd.SetOutput(new(bytes.Buffer))
+	return rootCmd
+}
+
+func TestCommand_ConfirmDestructiveFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    bool
+		wantErr bool
+	}{
+		{"yes", []string{"rm", "--yes"}, true, false},
+		{"yes shorthand", []string{"rm", "-y"}, true, false},
+		{"yes wins over no-input", []string{"rm", "--yes", "--no-input"}, true, false},
+		{"no-input", []string{"rm", "--no-input"}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var confirmed bool
+			var confirmErr error
+			rootCmd := newConfirmTestTree(&confirmed, &confirmErr)
+
+			if err := rootCmd.execute(tt.args); err != nil {
+				t.Fatalf("execute() error = %v", err)
+			}
+			if confirmed != tt.want {
+				t.Errorf("ConfirmDestructive() = %v, want %v", confirmed, tt.want)
+			}
+			if (confirmErr != nil) != tt.wantErr {
+				t.Errorf("ConfirmDestructive() error = %v, wantErr %v", confirmErr, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(confirmErr.Error(), "delete everything") {
+				t.Errorf("error should mention the action, got: %v", confirmErr)
+			}
+		})
+	}
+}
+
+func TestCommand_ConfirmDestructiveNonInteractive(t *testing.T) {
+	var confirmed bool
+	var confirmErr error
+	rootCmd := newConfirmTestTree(&confirmed, &confirmErr)
+	rootCmd.SetIn(new(bytes.Buffer))
+
+	if err := rootCmd.execute([]string{"rm"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if confirmed || confirmErr == nil {
+		t.Errorf("ConfirmDestructive() without a terminal = %v, %v; want false and an error", confirmed, confirmErr)
+	}
+}
+
+func TestCommand_ConfirmDestructivePrompt(t *testing.T) {
+	originalTerminal, originalAsk := isInputTerminal, askConfirm
+	isInputTerminal = func(r io.Reader) bool { return true }
+	askConfirm = func(title string, defaultValue bool) (bool, error) {
+		if defaultValue {
+			t.Error("destructive confirmations should default to no")
+		}
+		return true, nil
+	}
+	defer func() { isInputTerminal, askConfirm = originalTerminal, originalAsk }()
+
+	var confirmed bool
+	var confirmErr error
+	rootCmd := newConfirmTestTree(&confirmed, &confirmErr)
+	out := new(bytes.Buffer)
+	rootCmd.SetOutput(out)
+
+	if err := rootCmd.execute([]string{"rm"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if !confirmed || confirmErr != nil {
+		t.Errorf("ConfirmDestructive() = %v, %v; want the prompt answer", confirmed, confirmErr)
+	}
+	if !strings.Contains(out.String(), "This will delete everything") {
+		t.Errorf("ConfirmDestructive should print a warning, got: %q", out.String())
+	}
+}
+
+func newRunGuardedTree(buf *bytes.Buffer, fn func() error, ran *bool) *Command {
+	rootCmd := &Command{Use: "app", SilenceErrors: true, SilenceUsage: true}
+	rootCmd.AddConfirmFlags()
+	rootCmd.AddCommand(&Command{
+		Use: "drop",
+		RunE: func(cmd *Command, args []string) error {
+			return cmd.RunGuarded("drop the staging database", "Dropping...", "Dropped staging", func() error {
+				*ran = true
+				return fn()
+			})
+		},
+	})
+	rootCmd.SetOutput(buf)
+	return rootCmd
+}
+
+func TestCommand_RunGuardedYes(t *testing.T) {
+	buf := new(bytes.Buffer)
+	var ran bool
+	rootCmd := newRunGuardedTree(buf, func() error { return nil }, &ran)
+
+	if err := rootCmd.execute([]string{"drop", "--yes"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if !ran {
+		t.Error("RunGuarded should run fn after --yes")
+	}
+	if got, want := buf.String(), "Dropping...\n✓ Dropped staging\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestCommand_RunGuardedError(t *testing.T) {
+	buf := new(bytes.Buffer)
+	var ran bool
+	rootCmd := newRunGuardedTree(buf, func() error { return errors.New("database is locked") }, &ran)
+
+	err := rootCmd.execute([]string{"drop", "-y"})
+	if err == nil || err.Error() != "database is locked" {
+		t.Fatalf("execute() error = %v, want the error from fn", err)
+	}
+	if !strings.Contains(buf.String(), "✗ Dropping...: database is locked") {
+		t.Errorf("spinner should end with the error, got: %q", buf.String())
+	}
+}
+
+func TestCommand_RunGuardedNotConfirmed(t *testing.T) {
+	buf := new(bytes.Buffer)
+	var ran bool
+	rootCmd := newRunGuardedTree(buf, func() error { return nil }, &ran)
+
+	err := rootCmd.execute([]string{"drop", "--no-input"})
+	if err == nil || !strings.Contains(err.Error(), "confirmation required") {
+		t.Errorf("execute() error = %v, want a confirmation error", err)
+	}
+	if ran {
+		t.Error("RunGuarded should not run fn without confirmation")
+	}
+}
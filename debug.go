@@ -0,0 +1,37 @@
+package mamba
+
+import "github.com/spf13/pflag"
+
+// DebugParse resolves args the way Execute does, without running any hooks
+// or Run functions, and returns the command that would run, the flags set on
+// the command line with their values, and the remaining positional
+// arguments. It is meant for tests and for diagnosing where arguments end
+// up. Like Execute, it parses into the resolved command's flags.
+func (c *Command) DebugParse(args []string) (resolved *Command, flags map[string]string, positionals []string, err error) {
+	root := c.Root()
+	root.initDefaults()
+
+	find := root.Find
+	if root.TraverseChildren {
+		find = root.Traverse
+	}
+	cmd, cmdArgs, err := find(args)
+	if err != nil {
+		return cmd, nil, nil, err
+	}
+
+	cmd.InitDefaultHelpFlag()
+	cmd.InitDefaultVersionFlag()
+
+	flags = make(map[string]string)
+	if cmd.DisableFlagParsing {
+		return cmd, flags, cmdArgs, nil
+	}
+	if err := cmd.ParseFlags(cmdArgs); err != nil {
+		return cmd, flags, nil, err
+	}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		flags[f.Name] = f.Value.String()
+	})
+	return cmd, flags, cmd.Flags().Args(), nil
+}
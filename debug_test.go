@@ -0,0 +1,50 @@
+package mamba
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommand_DebugParse(t *testing.T) {
+	var ran bool
+	rootCmd := &Command{Use: "app"}
+	rootCmd.PersistentFlags().Bool("verbose", false, "Verbose output")
+	deployCmd := &Command{
+		Use: "deploy [target...]",
+		Run: func(cmd *Command, args []string) { ran = true },
+	}
+	deployCmd.Flags().String("env", "dev", "Environment")
+	deployCmd.Flags().IntP("replicas", "r", 1, "Replicas")
+	rootCmd.AddCommand(deployCmd)
+
+	resolved, flags, positionals, err := rootCmd.DebugParse([]string{"deploy", "web", "--env=staging", "-r", "3", "worker", "--verbose"})
+	if err != nil {
+		t.Fatalf("DebugParse() error = %v", err)
+	}
+
+	if resolved != deployCmd {
+		t.Errorf("resolved = %q, want deploy", resolved.Name())
+	}
+	wantFlags := map[string]string{"env": "staging", "replicas": "3", "verbose": "true"}
+	if !reflect.DeepEqual(flags, wantFlags) {
+		t.Errorf("flags = %v, want %v", flags, wantFlags)
+	}
+	if want := []string{"web", "worker"}; !reflect.DeepEqual(positionals, want) {
+		t.Errorf("positionals = %q, want %q", positionals, want)
+	}
+	if ran {
+		t.Error("DebugParse should not run the command")
+	}
+}
+
+func TestCommand_DebugParseErrors(t *testing.T) {
+	rootCmd := &Command{Use: "app"}
+	rootCmd.AddCommand(&Command{Use: "deploy", Run: func(cmd *Command, args []string) {}})
+
+	if _, _, _, err := rootCmd.DebugParse([]string{"deploy", "--nope"}); err == nil {
+		t.Error("DebugParse should fail for unknown flags")
+	}
+	if _, _, _, err := rootCmd.DebugParse([]string{"deploi"}); err == nil {
+		t.Error("DebugParse should fail for unknown commands")
+	}
+}
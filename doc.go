@@ -107,6 +107,9 @@ If needed, modern styling can be disabled:
 		EnableColors: &disableColors,
 	}
 
+Color output also honors the NO_COLOR and FORCE_COLOR environment variables,
+and can be overridden programmatically with style.SetColorProfile.
+
 # Architecture
 
 Mamba is built on proven libraries:
@@ -0,0 +1,24 @@
+package mamba
+
+// initDefaultDryRunFlag adds a persistent --dry-run flag to a root command
+// with EnableDryRun set
+func (c *Command) initDefaultDryRunFlag() {
+	if !c.EnableDryRun || c.PersistentFlags().Lookup("dry-run") != nil {
+		return
+	}
+	c.PersistentFlags().Bool("dry-run", false, "show what would happen without making changes")
+}
+
+// DryRun reports whether --dry-run was passed to the command or an ancestor
+func (c *Command) DryRun() bool {
+	return c.inheritedFlagSet("dry-run")
+}
+
+// dryRunPrefix returns the tag printed before success and info messages in
+// dry-run mode
+func (c *Command) dryRunPrefix() string {
+	if !c.DryRun() {
+		return ""
+	}
+	return c.Theme().Dim("[dry-run]") + " "
+}
@@ -0,0 +1,70 @@
+package mamba
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/base-go/mamba/pkg/style"
+)
+
+func TestCommand_DryRun(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd := &Command{Use: "app", EnableDryRun: true}
+	remoteCmd := &Command{Use: "remote"}
+	removeCmd := &Command{
+		Use: "remove",
+		Run: func(cmd *Command, args []string) {
+			cmd.PrintSuccess("Removed origin")
+		},
+	}
+	remoteCmd.AddCommand(removeCmd)
+	rootCmd.AddCommand(remoteCmd)
+	rootCmd.SetOutput(buf)
+
+	if err := rootCmd.execute([]string{"remote", "remove", "--dry-run"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+
+	if !removeCmd.DryRun() {
+		t.Error("DryRun() should be true for a nested subcommand when --dry-run is set")
+	}
+	if !strings.Contains(buf.String(), "[dry-run] "+style.SuccessIcon+" Removed origin") {
+		t.Errorf("PrintSuccess should be tagged in dry-run mode, got: %q", buf.String())
+	}
+}
+
+func TestCommand_DryRunNotSet(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd := &Command{Use: "app", EnableDryRun: true}
+	remoteCmd := &Command{Use: "remote"}
+	removeCmd := &Command{
+		Use: "remove",
+		Run: func(cmd *Command, args []string) {
+			cmd.PrintSuccess("Removed origin")
+		},
+	}
+	remoteCmd.AddCommand(removeCmd)
+	rootCmd.AddCommand(remoteCmd)
+	rootCmd.SetOutput(buf)
+
+	if err := rootCmd.execute([]string{"remote", "remove"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+
+	if removeCmd.DryRun() {
+		t.Error("DryRun() should be false without --dry-run")
+	}
+	if strings.Contains(buf.String(), "[dry-run]") {
+		t.Errorf("output should not be tagged without --dry-run, got: %q", buf.String())
+	}
+}
+
+func TestCommand_DryRunOptIn(t *testing.T) {
+	rootCmd := &Command{Use: "app", Run: func(cmd *Command, args []string) {}}
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	if err := rootCmd.execute([]string{"--dry-run"}); err == nil {
+		t.Error("--dry-run should be an unknown flag without EnableDryRun")
+	}
+}
@@ -0,0 +1,99 @@
+package mamba
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ArgCountError is returned when a command receives the wrong number of
+// positional arguments
+type ArgCountError struct {
+	// Min and Max bound the accepted count; Max is -1 when there is no
+	// upper bound
+	Min, Max int
+	// Got is the number of arguments received
+	Got int
+
+	// kind records the validator that returned the error, which picks the
+	// message
+	kind argCountKind
+}
+
+// argCountKind identifies the PositionalArgs validator behind an
+// ArgCountError
+type argCountKind int
+
+const (
+	argCountBounds argCountKind = iota
+	argCountMinimum
+	argCountMaximum
+	argCountExact
+	argCountRange
+)
+
+func (e *ArgCountError) Error() string {
+	switch e.kind {
+	case argCountMinimum:
+		return fmt.Sprintf("requires at least %d arg(s), only received %d", e.Min, e.Got)
+	case argCountMaximum:
+		return fmt.Sprintf("accepts at most %d arg(s), received %d", e.Max, e.Got)
+	case argCountExact:
+		return fmt.Sprintf("accepts %d arg(s), received %d", e.Max, e.Got)
+	case argCountRange:
+		return fmt.Sprintf("accepts between %d and %d arg(s), received %d", e.Min, e.Max, e.Got)
+	}
+
+	// Errors built outside the validators describe their bounds
+	switch {
+	case e.Max < 0:
+		return fmt.Sprintf("requires at least %d arg(s), only received %d", e.Min, e.Got)
+	case e.Min == e.Max:
+		return fmt.Sprintf("accepts %d arg(s), received %d", e.Max, e.Got)
+	case e.Min == 0:
+		return fmt.Sprintf("accepts at most %d arg(s), received %d", e.Max, e.Got)
+	default:
+		return fmt.Sprintf("accepts between %d and %d arg(s), received %d", e.Min, e.Max, e.Got)
+	}
+}
+
+// InvalidArgError is returned when a positional argument is not one of the
+// command's ValidArgs
+type InvalidArgError struct {
+	Arg     string
+	Command string
+}
+
+func (e *InvalidArgError) Error() string {
+	return fmt.Sprintf("invalid argument %q for %q", e.Arg, e.Command)
+}
+
+// RequiredFlagError is returned when required flags were not set
+type RequiredFlagError struct {
+	// Flags lists the missing flag names in sorted order
+	Flags []string
+}
+
+func (e *RequiredFlagError) Error() string {
+	return fmt.Sprintf(`required flag(s) "%s" not set`, strings.Join(e.Flags, `", "`))
+}
+
+// UnknownCommandError is returned when an argument does not name a
+// subcommand
+type UnknownCommandError struct {
+	Name    string
+	Command string
+	// Suggestions lists similarly named subcommands, if any
+	Suggestions []string
+}
+
+func (e *UnknownCommandError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "unknown command %q for %q", e.Name, e.Command)
+	if len(e.Suggestions) > 0 {
+		sb.WriteString("\n\nDid you mean this?")
+		for _, s := range e.Suggestions {
+			fmt.Fprintf(&sb, "\n\t%s", s)
+		}
+	}
+	return sb.String()
+}
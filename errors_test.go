@@ -0,0 +1,114 @@
+package mamba
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCommand_ArgCountError(t *testing.T) {
+	rootCmd := &Command{Use: "app", SilenceErrors: true, SilenceUsage: true}
+	rootCmd.AddCommand(&Command{Use: "copy", Args: ExactArgs(2), Run: func(cmd *Command, args []string) {}})
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	err := rootCmd.execute([]string{"copy", "a.txt"})
+
+	var countErr *ArgCountError
+	if !errors.As(err, &countErr) {
+		t.Fatalf("execute() error = %T %v, want *ArgCountError", err, err)
+	}
+	if countErr.Min != 2 || countErr.Max != 2 || countErr.Got != 1 {
+		t.Errorf("ArgCountError = %+v, want Min 2, Max 2, Got 1", countErr)
+	}
+	if got, want := err.Error(), "accepts 2 arg(s), received 1"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestArgCountErrorMessages(t *testing.T) {
+	tests := []struct {
+		validator PositionalArgs
+		args      []string
+		want      string
+	}{
+		{MinimumNArgs(2), []string{"a"}, "requires at least 2 arg(s), only received 1"},
+		{MaximumNArgs(1), []string{"a", "b", "c"}, "accepts at most 1 arg(s), received 3"},
+		{ExactArgs(2), []string{"a"}, "accepts 2 arg(s), received 1"},
+		{RangeArgs(1, 3), nil, "accepts between 1 and 3 arg(s), received 0"},
+		{RangeArgs(1, 1), nil, "accepts between 1 and 1 arg(s), received 0"},
+		{RangeArgs(0, 2), []string{"a", "b", "c"}, "accepts between 0 and 2 arg(s), received 3"},
+	}
+	for _, tt := range tests {
+		err := tt.validator(&Command{Use: "test"}, tt.args)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("error = %v, want %q", err, tt.want)
+		}
+	}
+
+	// Errors built directly describe their bounds
+	if got, want := (&ArgCountError{Min: 2, Max: -1, Got: 1}).Error(), "requires at least 2 arg(s), only received 1"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestCommand_InvalidArgError(t *testing.T) {
+	rootCmd := &Command{Use: "app", SilenceErrors: true, SilenceUsage: true}
+	rootCmd.AddCommand(&Command{
+		Use:       "deploy",
+		ValidArgs: []string{"staging", "production"},
+		Args:      OnlyValidArgs,
+		Run:       func(cmd *Command, args []string) {},
+	})
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	err := rootCmd.execute([]string{"deploy", "qa"})
+
+	var argErr *InvalidArgError
+	if !errors.As(err, &argErr) {
+		t.Fatalf("execute() error = %T %v, want *InvalidArgError", err, err)
+	}
+	if argErr.Arg != "qa" || argErr.Command != "app deploy" {
+		t.Errorf("InvalidArgError = %+v, want Arg qa for app deploy", argErr)
+	}
+}
+
+func TestCommand_RequiredFlagError(t *testing.T) {
+	rootCmd := &Command{Use: "app", SilenceErrors: true, SilenceUsage: true}
+	deployCmd := &Command{Use: "deploy", Run: func(cmd *Command, args []string) {}}
+	deployCmd.Flags().String("region", "", "region")
+	deployCmd.Flags().String("tag", "", "tag")
+	deployCmd.MarkFlagRequired("region")
+	deployCmd.MarkFlagRequired("tag")
+	rootCmd.AddCommand(deployCmd)
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	err := rootCmd.execute([]string{"deploy"})
+
+	var flagErr *RequiredFlagError
+	if !errors.As(err, &flagErr) {
+		t.Fatalf("execute() error = %T %v, want *RequiredFlagError", err, err)
+	}
+	if !slices.Equal(flagErr.Flags, []string{"region", "tag"}) {
+		t.Errorf("RequiredFlagError.Flags = %v, want [region tag]", flagErr.Flags)
+	}
+}
+
+func TestCommand_UnknownCommandError(t *testing.T) {
+	rootCmd := &Command{Use: "app", SilenceErrors: true, SilenceUsage: true}
+	rootCmd.AddCommand(&Command{Use: "deploy", Run: func(cmd *Command, args []string) {}})
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	err := rootCmd.execute([]string{"deplyo"})
+
+	var cmdErr *UnknownCommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("execute() error = %T %v, want *UnknownCommandError", err, err)
+	}
+	if cmdErr.Name != "deplyo" || cmdErr.Command != "app" {
+		t.Errorf("UnknownCommandError = %+v, want Name deplyo for app", cmdErr)
+	}
+	if !slices.Equal(cmdErr.Suggestions, []string{"deploy"}) {
+		t.Errorf("UnknownCommandError.Suggestions = %v, want [deploy]", cmdErr.Suggestions)
+	}
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/base-go/mamba"
@@ -289,28 +288,16 @@ Mamba is a drop-in replacement for Cobra with enhanced UX features.`,
 			cmd.PrintHeader("Available Features")
 			fmt.Println()
 
-			features := []struct {
-				name        string
-				description string
-				status      string
-			}{
-				{"Colored Output", "Beautiful terminal colors using lipgloss", "ready"},
-				{"Interactive Prompts", "User-friendly prompts with huh", "ready"},
-				{"Spinners", "Loading indicators for operations", "ready"},
-				{"Progress Bars", "Visual progress tracking", "ready"},
-				{"Styled Help", "Modern help messages", "ready"},
-			}
-
-			for _, f := range features {
-				fmt.Print(style.Command(f.name))
-				fmt.Print(" - ")
-				fmt.Print(style.Muted(f.description))
-				fmt.Print(" ")
-				if f.status == "ready" {
-					fmt.Print(style.Success("✓"))
-				}
-				fmt.Println()
-			}
+			cmd.PrintTable(
+				[]string{"Feature", "Description", "Status"},
+				[][]string{
+					{"Colored Output", "Beautiful terminal colors using lipgloss", "ready"},
+					{"Interactive Prompts", "User-friendly prompts with huh", "ready"},
+					{"Spinners", "Loading indicators for operations", "ready"},
+					{"Progress Bars", "Visual progress tracking", "ready"},
+					{"Styled Help", "Modern help messages", "ready"},
+				},
+			)
 		},
 	}
 
@@ -335,8 +322,6 @@ Mamba is a drop-in replacement for Cobra with enhanced UX features.`,
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(errorCmd)
 
-	// Execute
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
-	}
+	// Execute, exiting with status 1 on error
+	rootCmd.ExecuteAndExit()
 }
@@ -0,0 +1,36 @@
+package mamba
+
+import (
+	"errors"
+	"os"
+)
+
+// ExitCoder is implemented by errors that choose the process exit code used
+// by ExecuteAndExit
+type ExitCoder interface {
+	ExitCode() int
+}
+
+// osExit exits the process; tests replace it to observe the exit code
+var osExit = os.Exit
+
+// ExecuteAndExit runs the command like Execute, prints any error with
+// PrintError unless SilenceErrors is set, and exits the process. The exit
+// code is 0 on success, the code of the first ExitCoder in the error chain,
+// or 1 for any other error.
+func (c *Command) ExecuteAndExit() {
+	c.Root().styledErrors = true
+	osExit(exitCode(c.Execute()))
+}
+
+// exitCode returns the process exit code for err
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var coder ExitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	return 1
+}
@@ -0,0 +1,98 @@
+package mamba
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testExitError struct {
+	code int
+}
+
+func (e testExitError) Error() string { return fmt.Sprintf("exit status %d", e.code) }
+func (e testExitError) ExitCode() int { return e.code }
+
+// withExit records the code passed to osExit for the duration of a test
+func withExit(t *testing.T) *int {
+	t.Helper()
+	code := -1
+	original := osExit
+	osExit = func(c int) { code = c }
+	t.Cleanup(func() { osExit = original })
+	return &code
+}
+
+func TestCommand_ExecuteAndExit(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, 0},
+		{"plain error", errors.New("failed"), 1},
+		{"exit coder", testExitError{code: 3}, 3},
+		{"wrapped exit coder", fmt.Errorf("deploy: %w", testExitError{code: 4}), 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := withExit(t)
+			cmd := &Command{
+				Use:          "app",
+				SilenceUsage: true,
+				RunE:         func(cmd *Command, args []string) error { return tt.err },
+			}
+			cmd.SetArgs([]string{})
+			cmd.SetErr(new(bytes.Buffer))
+
+			cmd.ExecuteAndExit()
+
+			if *code != tt.want {
+				t.Errorf("exit code = %d, want %d", *code, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommand_ExecuteAndExitPrintsStyledError(t *testing.T) {
+	withExit(t)
+	errBuf := new(bytes.Buffer)
+	rootCmd := &Command{Use: "app", SilenceUsage: true}
+	subCmd := &Command{
+		Use:          "deploy",
+		SilenceUsage: true,
+		RunE:         func(cmd *Command, args []string) error { return errors.New("connection refused") },
+	}
+	rootCmd.AddCommand(subCmd)
+	rootCmd.SetArgs([]string{"deploy"})
+	rootCmd.SetErr(errBuf)
+
+	rootCmd.ExecuteAndExit()
+
+	want := rootCmd.render(errBuf, rootCmd.Theme().Error("connection refused"))
+	if got := errBuf.String(); strings.Count(got, "connection refused") != 1 || !strings.Contains(got, want) {
+		t.Errorf("error output = %q, want the styled error %q once", got, want)
+	}
+}
+
+func TestCommand_ExecuteAndExitSilenced(t *testing.T) {
+	withExit(t)
+	errBuf := new(bytes.Buffer)
+	cmd := &Command{
+		Use:           "app",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE:          func(cmd *Command, args []string) error { return errors.New("failed") },
+	}
+	cmd.SetArgs([]string{})
+	cmd.SetErr(errBuf)
+
+	cmd.ExecuteAndExit()
+
+	if errBuf.Len() != 0 {
+		t.Errorf("SilenceErrors should suppress the error, got: %q", errBuf.String())
+	}
+}
@@ -0,0 +1,291 @@
+package mamba
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// requiredFlagAnnotation marks a flag as required. It uses the same key as
+// Cobra so annotations set by Cobra-aware tooling keep working.
+const requiredFlagAnnotation = "cobra_annotation_bash_completion_one_required_flag"
+
+// Flag group annotations, also shared with Cobra. Each value is a
+// space-separated list of the flag names in one group.
+const (
+	requiredTogetherAnnotation  = "cobra_annotation_required_if_others_set"
+	mutuallyExclusiveAnnotation = "cobra_annotation_mutually_exclusive"
+)
+
+// deprecatedFlagAnnotation holds the migration message for a deprecated flag
+const deprecatedFlagAnnotation = "mamba_annotation_deprecated"
+
+// typeHintAnnotation holds the value hint shown for a flag in help
+const typeHintAnnotation = "mamba_annotation_type_hint"
+
+// flagGroupAnnotation holds the heading a flag is listed under in help
+const flagGroupAnnotation = "mamba_annotation_flag_group"
+
+// MarkFlagRequired instructs Execute to fail when the named flag is not set.
+// The flag must be defined on the command's local flags.
+func (c *Command) MarkFlagRequired(name string) error {
+	return markFlagRequired(c.Flags(), name)
+}
+
+// MarkPersistentFlagRequired instructs Execute to fail when the named
+// persistent flag is not set on this command or any of its subcommands.
+func (c *Command) MarkPersistentFlagRequired(name string) error {
+	return markFlagRequired(c.PersistentFlags(), name)
+}
+
+func markFlagRequired(flags *pflag.FlagSet, name string) error {
+	return flags.SetAnnotation(name, requiredFlagAnnotation, []string{"true"})
+}
+
+// validateRequiredFlags returns an error listing every required flag that
+// was not set on the command line or from a binding
+func (c *Command) validateRequiredFlags() error {
+	if c.DisableFlagParsing {
+		return nil
+	}
+
+	var missing []string
+	c.Flags().VisitAll(func(f *pflag.Flag) {
+		if required, ok := f.Annotations[requiredFlagAnnotation]; ok && len(required) > 0 && required[0] == "true" && !c.flagProvided(f) {
+			missing = append(missing, f.Name)
+		}
+	})
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return &RequiredFlagError{Flags: missing}
+	}
+	return nil
+}
+
+// MarkFlagsRequiredTogether instructs Execute to fail when some, but not all,
+// of the named flags are set
+func (c *Command) MarkFlagsRequiredTogether(names ...string) {
+	c.markFlagGroup(requiredTogetherAnnotation, "required together", names)
+}
+
+// MarkFlagsMutuallyExclusive instructs Execute to fail when more than one of
+// the named flags is set
+func (c *Command) MarkFlagsMutuallyExclusive(names ...string) {
+	c.markFlagGroup(mutuallyExclusiveAnnotation, "mutually exclusive", names)
+}
+
+// markFlagGroup records the group on each flag's annotations. It panics if a
+// flag does not exist, as that is a programming error.
+func (c *Command) markFlagGroup(annotation, kind string, names []string) {
+	c.mergePersistentFlags()
+	group := strings.Join(names, " ")
+	for _, name := range names {
+		f := c.Flags().Lookup(name)
+		if f == nil {
+			panic(fmt.Sprintf("failed to find flag %q and mark it as being %s", name, kind))
+		}
+		if err := c.Flags().SetAnnotation(name, annotation, append(f.Annotations[annotation], group)); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// validateFlagGroups checks the required-together and mutually exclusive
+// flag groups against the flags set on the command line or from a binding
+func (c *Command) validateFlagGroups() error {
+	if c.DisableFlagParsing {
+		return nil
+	}
+
+	set := make(map[string]bool)
+	requiredTogether := make(map[string]bool)
+	exclusive := make(map[string]bool)
+	c.Flags().VisitAll(func(f *pflag.Flag) {
+		set[f.Name] = c.flagProvided(f)
+		for _, group := range f.Annotations[requiredTogetherAnnotation] {
+			requiredTogether[group] = true
+		}
+		for _, group := range f.Annotations[mutuallyExclusiveAnnotation] {
+			exclusive[group] = true
+		}
+	})
+
+	for _, group := range sortedKeys(requiredTogether) {
+		var missing []string
+		names := strings.Fields(group)
+		for _, name := range names {
+			if !set[name] {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 && len(missing) < len(names) {
+			sort.Strings(missing)
+			return fmt.Errorf("if any flags in the group [%s] are set they must all be set; missing %v", group, missing)
+		}
+	}
+
+	for _, group := range sortedKeys(exclusive) {
+		var present []string
+		for _, name := range strings.Fields(group) {
+			if set[name] {
+				present = append(present, name)
+			}
+		}
+		if len(present) > 1 {
+			sort.Strings(present)
+			return fmt.Errorf("if any flags in the group [%s] are set none of the others can be; %v were all set", group, present)
+		}
+	}
+
+	return nil
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lookupOwnFlag finds a flag defined on the command's local or persistent flags
+func (c *Command) lookupOwnFlag(name string) (*pflag.Flag, error) {
+	if f := c.Flags().Lookup(name); f != nil {
+		return f, nil
+	}
+	if f := c.PersistentFlags().Lookup(name); f != nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("flag %q does not exist", name)
+}
+
+// MarkFlagHidden hides the named flag from help output. The flag still parses.
+func (c *Command) MarkFlagHidden(name string) error {
+	f, err := c.lookupOwnFlag(name)
+	if err != nil {
+		return err
+	}
+	f.Hidden = true
+	return nil
+}
+
+// MarkFlagDeprecated hides the named flag from help output and prints message
+// to stderr when it is used. The flag still parses.
+func (c *Command) MarkFlagDeprecated(name, message string) error {
+	f, err := c.lookupOwnFlag(name)
+	if err != nil {
+		return err
+	}
+	if message == "" {
+		return fmt.Errorf("deprecated message for flag %q must be set", name)
+	}
+	f.Hidden = true
+	if f.Annotations == nil {
+		f.Annotations = make(map[string][]string)
+	}
+	f.Annotations[deprecatedFlagAnnotation] = []string{message}
+	return nil
+}
+
+// SetFlagTypeHint sets the value hint shown for the named flag in help, such
+// as "file" for <file>. An empty hint hides it.
+func (c *Command) SetFlagTypeHint(name, hint string) error {
+	f, err := c.lookupOwnFlag(name)
+	if err != nil {
+		return err
+	}
+	if f.Annotations == nil {
+		f.Annotations = make(map[string][]string)
+	}
+	f.Annotations[typeHintAnnotation] = []string{hint}
+	return nil
+}
+
+// SetFlagGroup lists the named flag under the group heading in help, such as
+// "Network" or "Auth", instead of under "Flags". An empty group moves it back.
+func (c *Command) SetFlagGroup(name, group string) error {
+	f, err := c.lookupOwnFlag(name)
+	if err != nil {
+		return err
+	}
+	if group == "" {
+		delete(f.Annotations, flagGroupAnnotation)
+		return nil
+	}
+	if f.Annotations == nil {
+		f.Annotations = make(map[string][]string)
+	}
+	f.Annotations[flagGroupAnnotation] = []string{group}
+	return nil
+}
+
+// warnDeprecatedFlags prints one warning for each deprecated flag that was set
+func (c *Command) warnDeprecatedFlags() {
+	c.Flags().VisitAll(func(f *pflag.Flag) {
+		if message, ok := f.Annotations[deprecatedFlagAnnotation]; ok && f.Changed {
+			fmt.Fprintln(c.ErrOrStderr(), c.render(c.ErrOrStderr(), c.Theme().Warning(fmt.Sprintf("Flag --%s has been deprecated, %s", f.Name, message[0]))))
+		}
+	})
+}
+
+// Flag returns the named flag from the command's own flags or the persistent
+// flags it inherits, or nil if there is none
+func (c *Command) Flag(name string) *pflag.Flag {
+	c.mergePersistentFlags()
+	return c.Flags().Lookup(name)
+}
+
+// GetStringFlag returns the value of the named string flag and whether it was
+// set on the command line
+func (c *Command) GetStringFlag(name string) (string, bool) {
+	f := c.Flag(name)
+	if f == nil || f.Value.Type() != "string" {
+		return "", false
+	}
+	return f.Value.String(), f.Changed
+}
+
+// GetBoolFlag returns the value of the named bool flag and whether it was set
+// on the command line
+func (c *Command) GetBoolFlag(name string) (bool, bool) {
+	f := c.Flag(name)
+	if f == nil || f.Value.Type() != "bool" {
+		return false, false
+	}
+	v, _ := strconv.ParseBool(f.Value.String())
+	return v, f.Changed
+}
+
+// GetIntFlag returns the value of the named int flag and whether it was set
+// on the command line
+func (c *Command) GetIntFlag(name string) (int, bool) {
+	f := c.Flag(name)
+	if f == nil || f.Value.Type() != "int" {
+		return 0, false
+	}
+	v, _ := strconv.Atoi(f.Value.String())
+	return v, f.Changed
+}
+
+// SetFlagErrorFunc sets a function that rewrites flag parse errors, such as
+// "unknown flag: --foo", for this command and its subcommands
+func (c *Command) SetFlagErrorFunc(f func(*Command, error) error) {
+	c.flagErrorFunc = f
+}
+
+// FlagErrorFunc returns the flag error function, inherited from the nearest
+// ancestor with one set. The default returns the error unchanged.
+func (c *Command) FlagErrorFunc() func(*Command, error) error {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.flagErrorFunc != nil {
+			return cmd.flagErrorFunc
+		}
+	}
+	return func(c *Command, err error) error { return err }
+}
@@ -0,0 +1,388 @@
+package mamba
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestCommand_MarkFlagRequired(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"single missing", []string{"--name", "x"}, `required flag(s) "region" not set`},
+		{"multiple missing", []string{}, `required flag(s) "name", "region" not set`},
+		{"all set", []string{"--name", "x", "--region", "eu"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := false
+			cmd := &Command{
+				Use:          "test",
+				SilenceUsage: true,
+				Run:          func(cmd *Command, args []string) { ran = true },
+			}
+			cmd.SetErr(new(bytes.Buffer))
+			cmd.Flags().String("name", "", "Name flag")
+			cmd.Flags().String("region", "", "Region flag")
+			if err := cmd.MarkFlagRequired("name"); err != nil {
+				t.Fatalf("MarkFlagRequired() error = %v", err)
+			}
+			if err := cmd.MarkFlagRequired("region"); err != nil {
+				t.Fatalf("MarkFlagRequired() error = %v", err)
+			}
+
+			err := cmd.execute(tt.args)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("execute() error = %v", err)
+				}
+				if !ran {
+					t.Error("Expected Run to be called")
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("execute() error = %v, want %q", err, tt.wantErr)
+			}
+			if ran {
+				t.Error("Expected Run not to be called when required flags are missing")
+			}
+		})
+	}
+}
+
+func TestCommand_MarkFlagRequiredUnknownFlag(t *testing.T) {
+	cmd := &Command{Use: "test"}
+	if err := cmd.MarkFlagRequired("missing"); err == nil {
+		t.Error("Expected error when marking an undefined flag as required")
+	}
+}
+
+func TestCommand_MarkPersistentFlagRequired(t *testing.T) {
+	errBuf := new(bytes.Buffer)
+	rootCmd := &Command{Use: "root", SilenceUsage: true}
+	rootCmd.PersistentFlags().String("config", "", "Config file")
+	if err := rootCmd.MarkPersistentFlagRequired("config"); err != nil {
+		t.Fatalf("MarkPersistentFlagRequired() error = %v", err)
+	}
+
+	ran := false
+	subCmd := &Command{
+		Use: "sub",
+		Run: func(cmd *Command, args []string) { ran = true },
+	}
+	rootCmd.AddCommand(subCmd)
+	rootCmd.SetErr(errBuf)
+
+	if err := rootCmd.execute([]string{"sub", "--config", "app.yaml"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if !ran {
+		t.Error("Expected Run to be called when the required persistent flag is set")
+	}
+}
+
+func TestCommand_RequiredFlagErrorOutput(t *testing.T) {
+	errBuf := new(bytes.Buffer)
+	cmd := &Command{
+		Use:          "test",
+		SilenceUsage: true,
+		Run:          func(cmd *Command, args []string) {},
+	}
+	cmd.SetErr(errBuf)
+	cmd.Flags().String("name", "", "Name flag")
+	cmd.MarkFlagRequired("name")
+
+	if err := cmd.execute([]string{}); err == nil {
+		t.Fatal("Expected error for missing required flag")
+	}
+	if !strings.Contains(errBuf.String(), `required flag(s) "name" not set`) {
+		t.Errorf("Expected error to be printed, got: %s", errBuf.String())
+	}
+
+	errBuf.Reset()
+	cmd.SilenceErrors = true
+	cmd.execute([]string{})
+	if errBuf.String() != "" {
+		t.Errorf("Expected no error output when SilenceErrors is true, got: %s", errBuf.String())
+	}
+}
+
+func TestCommand_MarkFlagsGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"exclusive none set", []string{}, ""},
+		{"exclusive one set", []string{"--json"}, ""},
+		{"exclusive both set", []string{"--json", "--yaml"}, "if any flags in the group [json yaml] are set none of the others can be; [json yaml] were all set"},
+		{"together all set", []string{"--user", "u", "--password", "p"}, ""},
+		{"together partly set", []string{"--user", "u"}, "if any flags in the group [user password] are set they must all be set; missing [password]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran := false
+			cmd := &Command{
+				Use:          "test",
+				SilenceUsage: true,
+				Run:          func(cmd *Command, args []string) { ran = true },
+			}
+			cmd.SetErr(new(bytes.Buffer))
+			cmd.Flags().Bool("json", false, "JSON output")
+			cmd.Flags().Bool("yaml", false, "YAML output")
+			cmd.Flags().String("user", "", "User name")
+			cmd.Flags().String("password", "", "Password")
+			cmd.MarkFlagsMutuallyExclusive("json", "yaml")
+			cmd.MarkFlagsRequiredTogether("user", "password")
+
+			err := cmd.execute(tt.args)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("execute() error = %v", err)
+				}
+				if !ran {
+					t.Error("Expected Run to be called")
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("execute() error = %v, want %q", err, tt.wantErr)
+			}
+			if ran {
+				t.Error("Expected Run not to be called when a flag group is violated")
+			}
+		})
+	}
+}
+
+func TestCommand_MarkFlagsGroupUnknownFlag(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic when marking an unknown flag")
+		}
+	}()
+
+	cmd := &Command{Use: "test"}
+	cmd.Flags().Bool("json", false, "JSON output")
+	cmd.MarkFlagsMutuallyExclusive("json", "missing")
+}
+
+func TestCommand_MarkFlagDeprecated(t *testing.T) {
+	var got string
+	stderr := new(bytes.Buffer)
+	cmd := &Command{
+		Use: "test",
+		Run: func(cmd *Command, args []string) {
+			got, _ = cmd.Flags().GetString("old-name")
+		},
+	}
+	cmd.Flags().String("old-name", "", "Old name flag")
+	cmd.Flags().String("name", "", "Name flag")
+	cmd.SetOutput(new(bytes.Buffer))
+	cmd.SetErr(stderr)
+
+	if err := cmd.MarkFlagDeprecated("old-name", "use --name instead"); err != nil {
+		t.Fatalf("MarkFlagDeprecated() error = %v", err)
+	}
+
+	if err := cmd.execute([]string{"--old-name", "a", "--old-name", "b"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if got != "b" {
+		t.Errorf("deprecated flag value = %q, want %q", got, "b")
+	}
+	if n := strings.Count(stderr.String(), "Flag --old-name has been deprecated, use --name instead"); n != 1 {
+		t.Errorf("deprecation warning printed %d times, want 1: %q", n, stderr.String())
+	}
+	if strings.Contains(cmd.ModernHelp(), "old-name") {
+		t.Error("deprecated flag should not appear in help")
+	}
+}
+
+func TestCommand_MarkFlagHidden(t *testing.T) {
+	var got bool
+	cmd := &Command{
+		Use: "test",
+		Run: func(cmd *Command, args []string) {
+			got, _ = cmd.Flags().GetBool("experimental")
+		},
+	}
+	cmd.Flags().Bool("experimental", false, "Experimental mode")
+	cmd.Flags().String("name", "", "Name flag")
+	cmd.SetOutput(new(bytes.Buffer))
+
+	if err := cmd.MarkFlagHidden("experimental"); err != nil {
+		t.Fatalf("MarkFlagHidden() error = %v", err)
+	}
+	if err := cmd.MarkFlagHidden("missing"); err == nil {
+		t.Error("MarkFlagHidden() on an unknown flag should error")
+	}
+
+	help := cmd.ModernHelp()
+	if strings.Contains(help, "experimental") {
+		t.Errorf("hidden flag should not appear in ModernHelp, got: %s", help)
+	}
+	if !strings.Contains(help, "--name") {
+		t.Errorf("visible flags should still appear in ModernHelp, got: %s", help)
+	}
+
+	if err := cmd.execute([]string{"--experimental"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if !got {
+		t.Error("hidden flag should still parse")
+	}
+}
+
+func TestCommand_Flag(t *testing.T) {
+	rootCmd := &Command{Use: "app"}
+	rootCmd.PersistentFlags().String("region", "us-east-1", "Region")
+	remoteCmd := &Command{Use: "remote"}
+	remoteCmd.PersistentFlags().Bool("verbose", false, "Verbose output")
+	addCmd := &Command{Use: "add"}
+	addCmd.Flags().Int("port", 22, "Port")
+	remoteCmd.AddCommand(addCmd)
+	rootCmd.AddCommand(remoteCmd)
+
+	for _, name := range []string{"region", "verbose", "port"} {
+		if addCmd.Flag(name) == nil {
+			t.Errorf("Flag(%q) should find the flag before Execute", name)
+		}
+	}
+	if addCmd.Flag("missing") != nil {
+		t.Error("Flag() should return nil for unknown flags")
+	}
+}
+
+func TestCommand_GetTypedFlags(t *testing.T) {
+	var region string
+	var verbose bool
+	var port int
+	rootCmd := &Command{Use: "app"}
+	rootCmd.PersistentFlags().String("region", "us-east-1", "Region")
+	rootCmd.PersistentFlags().Bool("verbose", false, "Verbose output")
+	addCmd := &Command{
+		Use: "add",
+		Run: func(cmd *Command, args []string) {
+			region, _ = cmd.GetStringFlag("region")
+			verbose, _ = cmd.GetBoolFlag("verbose")
+			port, _ = cmd.GetIntFlag("port")
+		},
+	}
+	addCmd.Flags().Int("port", 22, "Port")
+	rootCmd.AddCommand(addCmd)
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	if err := rootCmd.execute([]string{"add", "--verbose", "--port", "2222"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if region != "us-east-1" || !verbose || port != 2222 {
+		t.Errorf("got region=%q verbose=%v port=%d, want us-east-1, true, 2222", region, verbose, port)
+	}
+
+	if _, changed := addCmd.GetStringFlag("region"); changed {
+		t.Error("GetStringFlag should report unset flags as unchanged")
+	}
+	if _, changed := addCmd.GetBoolFlag("verbose"); !changed {
+		t.Error("GetBoolFlag should report set flags as changed")
+	}
+	if v, changed := addCmd.GetIntFlag("region"); v != 0 || changed {
+		t.Errorf("GetIntFlag on a string flag = %d, %v, want 0, false", v, changed)
+	}
+}
+
+func TestCommand_SetFlagErrorFunc(t *testing.T) {
+	rootCmd := &Command{Use: "app"}
+	deployCmd := &Command{Use: "deploy", Run: func(cmd *Command, args []string) {}}
+	deployCmd.Flags().Int("replicas", 1, "Replica count")
+	rootCmd.AddCommand(deployCmd)
+	rootCmd.SetOutput(new(bytes.Buffer))
+	rootCmd.SetFlagErrorFunc(func(cmd *Command, err error) error {
+		return fmt.Errorf("%s: %w\nRun '%s --help' for usage.", cmd.Name(), err, cmd.CommandPath())
+	})
+
+	err := rootCmd.execute([]string{"deploy", "--replics", "3"})
+	if err == nil {
+		t.Fatal("execute() should fail on an unknown flag")
+	}
+	want := "deploy: unknown flag: --replics\nRun 'app deploy --help' for usage."
+	if err.Error() != want {
+		t.Errorf("execute() error = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestCommand_FlagErrorFuncDefault(t *testing.T) {
+	cmd := &Command{Use: "app", Run: func(cmd *Command, args []string) {}}
+	cmd.SetOutput(new(bytes.Buffer))
+
+	err := cmd.execute([]string{"--nope"})
+	if err == nil || err.Error() != "unknown flag: --nope" {
+		t.Errorf("execute() error = %v, want the unchanged pflag error", err)
+	}
+}
+
+// normalizeTestFlagName makes flag names case, dash, and underscore insensitive
+func normalizeTestFlagName(f *pflag.FlagSet, name string) pflag.NormalizedName {
+	return pflag.NormalizedName(strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(name)))
+}
+
+func TestCommand_SetGlobalNormalizationFunc(t *testing.T) {
+	for _, args := range [][]string{
+		{"deploy", "--my-flag", "x", "--log-level", "debug"},
+		{"deploy", "--my_flag", "x", "--log_level", "debug"},
+		{"deploy", "--myFlag", "x", "--logLevel", "debug"},
+	} {
+		t.Run(args[1], func(t *testing.T) {
+			var myFlag, logLevel string
+			rootCmd := &Command{Use: "app"}
+			rootCmd.PersistentFlags().String("log-level", "info", "Log level")
+			deployCmd := &Command{
+				Use: "deploy",
+				Run: func(cmd *Command, args []string) {
+					myFlag, _ = cmd.Flags().GetString("my-flag")
+					logLevel, _ = cmd.Flags().GetString("log-level")
+				},
+			}
+			deployCmd.Flags().String("my-flag", "", "My flag")
+
+			// Set before the subcommand is added to check it is propagated
+			rootCmd.SetGlobalNormalizationFunc(normalizeTestFlagName)
+			rootCmd.AddCommand(deployCmd)
+			rootCmd.SetOutput(new(bytes.Buffer))
+
+			if err := rootCmd.execute(args); err != nil {
+				t.Fatalf("execute() error = %v", err)
+			}
+			if myFlag != "x" || logLevel != "debug" {
+				t.Errorf("my-flag = %q, log-level = %q, want x and debug", myFlag, logLevel)
+			}
+		})
+	}
+}
+
+func TestCommand_SetGlobalNormalizationFuncExistingChildren(t *testing.T) {
+	rootCmd := &Command{Use: "app"}
+	subCmd := &Command{Use: "sub"}
+	leafCmd := &Command{Use: "leaf"}
+	rootCmd.AddCommand(subCmd)
+	subCmd.AddCommand(leafCmd)
+	leafCmd.Flags().Bool("dry-run", false, "Dry run")
+
+	rootCmd.SetGlobalNormalizationFunc(normalizeTestFlagName)
+
+	if leafCmd.GlobalNormalizationFunc() == nil {
+		t.Fatal("GlobalNormalizationFunc() should be set on existing descendants")
+	}
+	if leafCmd.Flags().Lookup("DRY_RUN") == nil {
+		t.Error("Lookup() should find the flag by a normalized name")
+	}
+}
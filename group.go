@@ -0,0 +1,77 @@
+package mamba
+
+// Group is a titled section of subcommands in help output
+type Group struct {
+	ID    string
+	Title string
+}
+
+// commandSection is a titled list of visible subcommands
+type commandSection struct {
+	title    string
+	commands []*Command
+}
+
+// AddGroup registers a group that subcommands can join by setting GroupID
+func (c *Command) AddGroup(id, title string) {
+	c.groups = append(c.groups, &Group{ID: id, Title: title})
+}
+
+// Groups returns the registered command groups
+func (c *Command) Groups() []*Group {
+	return c.groups
+}
+
+// ContainsGroup reports whether a group with the given ID is registered
+func (c *Command) ContainsGroup(id string) bool {
+	for _, g := range c.groups {
+		if g.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// commandSections buckets the visible subcommands by group. Commands without
+// a registered group are listed under "Additional Commands", or under
+// "Available Commands" when no groups are registered.
+func (c *Command) commandSections() []commandSection {
+	var visible []*Command
+	for _, cmd := range c.commands {
+		if !cmd.Hidden {
+			visible = append(visible, cmd)
+		}
+	}
+
+	if len(c.groups) == 0 {
+		if len(visible) == 0 {
+			return nil
+		}
+		return []commandSection{{title: "Available Commands", commands: visible}}
+	}
+
+	var sections []commandSection
+	for _, g := range c.groups {
+		section := commandSection{title: g.Title}
+		for _, cmd := range visible {
+			if cmd.GroupID == g.ID {
+				section.commands = append(section.commands, cmd)
+			}
+		}
+		if len(section.commands) > 0 {
+			sections = append(sections, section)
+		}
+	}
+
+	additional := commandSection{title: "Additional Commands"}
+	for _, cmd := range visible {
+		if !c.ContainsGroup(cmd.GroupID) {
+			additional.commands = append(additional.commands, cmd)
+		}
+	}
+	if len(additional.commands) > 0 {
+		sections = append(sections, additional)
+	}
+
+	return sections
+}
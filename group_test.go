@@ -0,0 +1,101 @@
+package mamba
+
+import (
+	"strings"
+	"testing"
+)
+
+func newGroupTestTree() *Command {
+	rootCmd := &Command{Use: "app"}
+	rootCmd.AddGroup("core", "Core Commands")
+	rootCmd.AddGroup("mgmt", "Management Commands")
+
+	rootCmd.AddCommand(
+		&Command{Use: "run", Short: "Run the app", GroupID: "core"},
+		&Command{Use: "build", Short: "Build the app", GroupID: "core"},
+		&Command{Use: "users", Short: "Manage users", GroupID: "mgmt"},
+		&Command{Use: "version", Short: "Print the version"},
+	)
+	return rootCmd
+}
+
+// helpSection returns the lines listed under title, up to the next blank line
+func helpSection(help, title string) []string {
+	lines := strings.Split(help, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != title && strings.TrimSpace(line) != title+":" {
+			continue
+		}
+		var section []string
+		for _, l := range lines[i+1:] {
+			if strings.TrimSpace(l) == "" {
+				break
+			}
+			section = append(section, l)
+		}
+		return section
+	}
+	return nil
+}
+
+func TestCommand_ModernHelpGroups(t *testing.T) {
+	help := newGroupTestTree().ModernHelp()
+
+	tests := []struct {
+		title string
+		want  []string
+	}{
+		{"Core Commands", []string{"run", "build"}},
+		{"Management Commands", []string{"users"}},
+		{"Additional Commands", []string{"version"}},
+	}
+
+	for _, tt := range tests {
+		section := helpSection(help, tt.title)
+		if len(section) != len(tt.want) {
+			t.Fatalf("%s = %q, want commands %v", tt.title, section, tt.want)
+		}
+		for i, name := range tt.want {
+			if strings.Fields(section[i])[0] != name {
+				t.Errorf("%s[%d] = %q, want %q", tt.title, i, section[i], name)
+			}
+		}
+	}
+
+	if strings.Contains(help, "Available Commands") {
+		t.Error("Grouped help should not contain a flat Available Commands section")
+	}
+
+	// Descriptions line up within a group
+	core := helpSection(help, "Core Commands")
+	if strings.Index(core[0], "Run the app") != strings.Index(core[1], "Build the app") {
+		t.Errorf("Core Commands should be aligned, got %q", core)
+	}
+}
+
+func TestCommand_UsageStringGroups(t *testing.T) {
+	usage := newGroupTestTree().UsageString()
+
+	for _, title := range []string{"Core Commands:", "Management Commands:", "Additional Commands:"} {
+		if !strings.Contains(usage, title) {
+			t.Errorf("UsageString should contain %q, got: %s", title, usage)
+		}
+	}
+	if section := helpSection(usage, "Management Commands"); len(section) != 1 || !strings.Contains(section[0], "users") {
+		t.Errorf("Management Commands = %q, want users", section)
+	}
+}
+
+func TestCommand_Groups(t *testing.T) {
+	rootCmd := newGroupTestTree()
+
+	if len(rootCmd.Groups()) != 2 {
+		t.Fatalf("Groups() returned %d groups, want 2", len(rootCmd.Groups()))
+	}
+	if !rootCmd.ContainsGroup("core") {
+		t.Error("ContainsGroup(core) should be true")
+	}
+	if rootCmd.ContainsGroup("missing") {
+		t.Error("ContainsGroup(missing) should be false")
+	}
+}
@@ -2,252 +2,476 @@ package mamba
 
 import (
 	"fmt"
+	"io"
+	"strconv"
 	"strings"
 
 	"github.com/base-go/mamba/pkg/style"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/term"
 	"github.com/spf13/pflag"
 )
 
+// Help is wrapped to the terminal width, capped at maxHelpWidth, or to
+// defaultHelpWidth when the width is unknown
+const (
+	defaultHelpWidth = 80
+	maxHelpWidth     = 100
+)
+
+// SetHelpWidth sets the width help text is wrapped to for this command and
+// its subcommands, instead of the terminal width
+func (c *Command) SetHelpWidth(width int) {
+	c.helpWidthOverride = width
+}
+
+// helpWidth returns the width to wrap help text to
+func (c *Command) helpWidth() int {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.helpWidthOverride > 0 {
+			return cmd.helpWidthOverride
+		}
+	}
+	if f, ok := c.OutOrStdout().(interface{ Fd() uintptr }); ok {
+		if w, _, err := term.GetSize(f.Fd()); err == nil && w > 0 {
+			return min(w, maxHelpWidth)
+		}
+	}
+	return defaultHelpWidth
+}
+
+// renderLines styles each line of s on its own, so lipgloss does not pad the
+// shorter lines of multi-line text to a block
+func renderLines(s string, render func(string) string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = render(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapIndent wraps s to fit in width when it starts indent columns in, and
+// indents the continuation lines to match. Line breaks in s are kept.
+func wrapIndent(s string, width, indent int) string {
+	limit := width - indent
+	if limit < 20 {
+		limit = 20
+	}
+	wrapped := ansi.Wrap(s, limit, "")
+	return strings.ReplaceAll(wrapped, "\n", "\n"+strings.Repeat(" ", indent))
+}
+
 // ModernHelp generates a modern styled help message
 func (c *Command) ModernHelp() string {
+	t := c.Theme()
+	width := c.helpWidth()
+
 	var sb strings.Builder
 
 	// Header
 	if c.Long != "" {
-		sb.WriteString(style.Header(c.Name()))
+		sb.WriteString(t.Header(c.Name()))
 		sb.WriteString("\n\n")
-		sb.WriteString(style.Muted(c.Long))
+		sb.WriteString(wrapIndent(renderLines(c.Long, t.Muted), width, 0))
 		sb.WriteString("\n\n")
 	} else if c.Short != "" {
-		sb.WriteString(style.Header(c.Name()))
+		sb.WriteString(t.Header(c.Name()))
 		sb.WriteString("\n\n")
-		sb.WriteString(style.Muted(c.Short))
+		sb.WriteString(wrapIndent(renderLines(c.Short, t.Muted), width, 0))
 		sb.WriteString("\n\n")
 	}
 
 	// Usage
-	sb.WriteString(style.SubHeader("Usage"))
-	sb.WriteString("\n  ")
-	sb.WriteString(style.Command(c.UseLine()))
-	sb.WriteString("\n\n")
+	sb.WriteString(t.SubHeader("Usage"))
+	sb.WriteString("\n")
+	for _, line := range c.usageLines() {
+		sb.WriteString("  ")
+		sb.WriteString(t.Command(line))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
 
 	// Examples
 	if c.Example != "" {
-		sb.WriteString(style.SubHeader("Examples"))
+		sb.WriteString(t.SubHeader("Examples"))
 		sb.WriteString("\n")
 		examples := strings.Split(c.Example, "\n")
 		for _, example := range examples {
 			if strings.TrimSpace(example) != "" {
 				sb.WriteString("  ")
-				sb.WriteString(style.Dim(example))
+				sb.WriteString(wrapIndent(t.Dim(example), width, 2))
 				sb.WriteString("\n")
 			}
 		}
 		sb.WriteString("\n")
 	}
 
-	// Available Commands
-	if len(c.commands) > 0 {
-		sb.WriteString(style.SubHeader("Available Commands"))
+	// Available Commands, bucketed by group
+	for _, section := range c.commandSections() {
+		sb.WriteString(t.SubHeader(section.title))
 		sb.WriteString("\n")
 
 		maxLen := 0
-		visibleCmds := []*Command{}
-		for _, cmd := range c.commands {
-			if !cmd.Hidden {
-				visibleCmds = append(visibleCmds, cmd)
-				if len(cmd.Name()) > maxLen {
-					maxLen = len(cmd.Name())
-				}
+		for _, cmd := range section.commands {
+			if len(cmd.Name()) > maxLen {
+				maxLen = len(cmd.Name())
 			}
 		}
 
-		for _, cmd := range visibleCmds {
+		for _, cmd := range section.commands {
 			sb.WriteString("  ")
-			sb.WriteString(style.Command(fmt.Sprintf("%-*s", maxLen, cmd.Name())))
+			sb.WriteString(t.Command(fmt.Sprintf("%-*s", maxLen, cmd.Name())))
 			sb.WriteString("  ")
-			sb.WriteString(style.Muted(cmd.Short))
+			sb.WriteString(t.Muted(cmd.Short))
+			if cmd.Deprecated != "" {
+				sb.WriteString(" ")
+				sb.WriteString(t.Styles().Warning.Render("(deprecated)"))
+			}
 			sb.WriteString("\n")
 		}
 		sb.WriteString("\n")
 	}
 
-	// Flags
-	if c.Flags().HasFlags() {
-		sb.WriteString(style.SubHeader("Flags"))
+	// Flags, bucketed by SetFlagGroup heading
+	for _, section := range c.flagSections(c.NonInheritedFlags()) {
+		sb.WriteString(t.SubHeader(section.title))
 		sb.WriteString("\n")
-		sb.WriteString(c.modernFlagUsages())
+		sb.WriteString(c.modernFlagUsages(section.flags))
 		sb.WriteString("\n")
 	}
 
-	// Global/Persistent Flags (if not root command)
-	if c.HasParent() && c.parent.PersistentFlags().HasFlags() {
-		sb.WriteString(style.SubHeader("Global Flags"))
+	// Global Flags, inherited from every ancestor
+	if flags := c.globalFlags(); flags.HasAvailableFlags() {
+		sb.WriteString(t.SubHeader("Global Flags"))
 		sb.WriteString("\n")
-		sb.WriteString(c.modernPersistentFlagUsages())
+		sb.WriteString(c.modernFlagUsages(flags))
 		sb.WriteString("\n")
 	}
 
 	// Additional help
 	if c.HasSubCommands() {
-		sb.WriteString(style.Dim(fmt.Sprintf("Use \"%s [command] --help\" for more information about a command.", c.Root().Name())))
+		sb.WriteString(t.Dim(fmt.Sprintf("Use \"%s [command] --help\" for more information about a command.", c.Root().Name())))
 		sb.WriteString("\n")
 	}
 
 	return sb.String()
 }
 
-// modernFlagUsages returns modern styled flag usages
-func (c *Command) modernFlagUsages() string {
-	var sb strings.Builder
-
-	maxLen := 0
-	c.Flags().VisitAll(func(f *pflag.Flag) {
-		flagLen := len(f.Name) + 6 // "--" + name + "  "
-		if f.Shorthand != "" {
-			flagLen += 4 // "-X, "
-		}
-		if flagLen > maxLen {
-			maxLen = flagLen
-		}
-	})
-
-	c.Flags().VisitAll(func(f *pflag.Flag) {
-		sb.WriteString("  ")
+// typeHints maps pflag value types to the hint shown in help. Types that take
+// no value map to an empty hint; unlisted types show their type name.
+var typeHints = map[string]string{
+	"bool":           "",
+	"count":          "",
+	"boolSlice":      "bools",
+	"stringSlice":    "strings",
+	"stringArray":    "strings",
+	"intSlice":       "ints",
+	"int32Slice":     "ints",
+	"int64Slice":     "ints",
+	"uintSlice":      "uints",
+	"float32Slice":   "floats",
+	"float64Slice":   "floats",
+	"durationSlice":  "durations",
+	"int8":           "int",
+	"int16":          "int",
+	"int32":          "int",
+	"int64":          "int",
+	"uint8":          "uint",
+	"uint16":         "uint",
+	"uint32":         "uint",
+	"uint64":         "uint",
+	"float32":        "float",
+	"float64":        "float",
+	"stringToString": "key=value",
+	"stringToInt":    "key=int",
+	"stringToInt64":  "key=int",
+}
 
-		flagStr := ""
-		if f.Shorthand != "" {
-			flagStr = style.Flag(fmt.Sprintf("-%s, --%s", f.Shorthand, f.Name))
-		} else {
-			flagStr = style.Flag(fmt.Sprintf("    --%s", f.Name))
-		}
+// flagTypeHint returns the hint shown in help for the value of f, preferring
+// one set with SetFlagTypeHint
+func flagTypeHint(f *pflag.Flag) string {
+	if hint, ok := f.Annotations[typeHintAnnotation]; ok {
+		return hint[0]
+	}
+	if hint, ok := typeHints[f.Value.Type()]; ok {
+		return hint
+	}
+	return f.Value.Type()
+}
 
-		// Pad to align descriptions
-		padding := maxLen - len(f.Name) - 6
-		if f.Shorthand != "" {
-			padding -= 4
+// globalFlags returns the persistent flags inherited from all ancestors,
+// leaving out any shadowed by a flag of the same name on the command
+func (c *Command) globalFlags() *pflag.FlagSet {
+	own := c.NonInheritedFlags()
+	global := c.newFlagSet()
+	c.InheritedFlags().VisitAll(func(f *pflag.Flag) {
+		if own.Lookup(f.Name) == nil {
+			global.AddFlag(f)
 		}
+	})
+	return global
+}
 
-		sb.WriteString(flagStr)
-		sb.WriteString(strings.Repeat(" ", padding))
-		sb.WriteString("  ")
+// flagSection is a heading in help and the flags listed under it
+type flagSection struct {
+	title string
+	flags *pflag.FlagSet
+}
 
-		// Add type hint for non-boolean flags
-		if f.Value.Type() != "bool" {
-			sb.WriteString(style.Argument(fmt.Sprintf("<%s>", f.Value.Type())))
-			sb.WriteString("  ")
+// flagSections splits fs into the ungrouped flags under "Flags", followed by
+// one section per SetFlagGroup heading in the order its first flag is listed.
+// Sections with only hidden flags are left out.
+func (c *Command) flagSections(fs *pflag.FlagSet) []flagSection {
+	sections := []flagSection{{title: "Flags", flags: c.newFlagSet()}}
+	index := map[string]int{"": 0}
+
+	fs.VisitAll(func(f *pflag.Flag) {
+		group := ""
+		if g, ok := f.Annotations[flagGroupAnnotation]; ok {
+			group = g[0]
 		}
-
-		sb.WriteString(style.Muted(f.Usage))
-
-		// Show default value if it's not empty and not "false" for bools
-		if f.DefValue != "" && !(f.Value.Type() == "bool" && f.DefValue == "false") {
-			sb.WriteString(style.Dim(fmt.Sprintf(" (default: %s)", f.DefValue)))
+		i, ok := index[group]
+		if !ok {
+			i = len(sections)
+			index[group] = i
+			sections = append(sections, flagSection{title: group, flags: c.newFlagSet()})
 		}
-
-		sb.WriteString("\n")
+		sections[i].flags.AddFlag(f)
 	})
 
-	return sb.String()
+	var visible []flagSection
+	for _, section := range sections {
+		if section.flags.HasAvailableFlags() {
+			visible = append(visible, section)
+		}
+	}
+	return visible
 }
 
-// modernPersistentFlagUsages returns modern styled persistent flag usages
-func (c *Command) modernPersistentFlagUsages() string {
+// modernFlagUsages returns modern styled usages for the flags in fs
+func (c *Command) modernFlagUsages(fs *pflag.FlagSet) string {
+	t := c.Theme()
+	width := c.helpWidth()
+
 	var sb strings.Builder
 
 	maxLen := 0
-	c.parent.PersistentFlags().VisitAll(func(f *pflag.Flag) {
-		flagLen := len(f.Name) + 6
-		if f.Shorthand != "" {
-			flagLen += 4
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
 		}
+		flagLen := len(f.Name) + 6 // "-X, --" + name or "    --" + name
 		if flagLen > maxLen {
 			maxLen = flagLen
 		}
 	})
 
-	c.parent.PersistentFlags().VisitAll(func(f *pflag.Flag) {
-		// Skip if already shown in local flags
-		if c.Flags().Lookup(f.Name) != nil {
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
 			return
 		}
-
-		sb.WriteString("  ")
+		var line strings.Builder
+		line.WriteString("  ")
 
 		flagStr := ""
 		if f.Shorthand != "" {
-			flagStr = style.Flag(fmt.Sprintf("-%s, --%s", f.Shorthand, f.Name))
+			flagStr = t.Flag(fmt.Sprintf("-%s, --%s", f.Shorthand, f.Name))
 		} else {
-			flagStr = style.Flag(fmt.Sprintf("    --%s", f.Name))
+			flagStr = t.Flag(fmt.Sprintf("    --%s", f.Name))
 		}
 
+		// Pad to align descriptions
 		padding := maxLen - len(f.Name) - 6
-		if f.Shorthand != "" {
-			padding -= 4
-		}
 
-		sb.WriteString(flagStr)
-		sb.WriteString(strings.Repeat(" ", padding))
-		sb.WriteString("  ")
+		line.WriteString(flagStr)
+		line.WriteString(strings.Repeat(" ", padding))
+		line.WriteString("  ")
 
-		if f.Value.Type() != "bool" {
-			sb.WriteString(style.Argument(fmt.Sprintf("<%s>", f.Value.Type())))
-			sb.WriteString("  ")
+		// Add type hint for flags that take a value
+		if hint := flagTypeHint(f); hint != "" {
+			line.WriteString(t.Argument(fmt.Sprintf("<%s>", hint)))
+			line.WriteString("  ")
 		}
 
-		sb.WriteString(style.Muted(f.Usage))
+		desc := t.Muted(f.Usage)
 
+		// Show default value if it's not empty and not "false" for bools
 		if f.DefValue != "" && !(f.Value.Type() == "bool" && f.DefValue == "false") {
-			sb.WriteString(style.Dim(fmt.Sprintf(" (default: %s)", f.DefValue)))
+			desc += t.Dim(fmt.Sprintf(" (default: %s)", f.DefValue))
 		}
 
+		// Wrapped description lines line up under the first one
+		indent := lipgloss.Width(line.String())
+		sb.WriteString(line.String())
+		sb.WriteString(wrapIndent(desc, width, indent))
 		sb.WriteString("\n")
 	})
 
 	return sb.String()
 }
 
+// printDecorative writes styled output to stdout, unless a structured output
+// format is active and stdout must stay machine-parseable
+func (c *Command) printDecorative(s string) {
+	if c.machineOutput() {
+		return
+	}
+	fmt.Fprintln(c.OutOrStdout(), c.render(c.OutOrStdout(), s))
+}
+
+// printMessage writes a status message like printDecorative, unless quiet
+// mode is on. Data such as tables and lists is still written when quiet.
+func (c *Command) printMessage(s string) {
+	if c.Quiet() {
+		return
+	}
+	c.printDecorative(s)
+}
+
+// render prepares styled s for writing to w, removing styling when plain
+// reports it should be
+func (c *Command) render(w io.Writer, s string) string {
+	if c.plain(w) {
+		return ansi.Strip(s)
+	}
+	return s
+}
+
+// plain reports whether output to w is written without styling: when colors
+// are disabled for the command, or when they are auto-detected and either
+// DisableStyling was called or w is not a terminal and color is not forced,
+// so redirecting stdout or stderr to a file keeps it plain.
+func (c *Command) plain(w io.Writer) bool {
+	if enabled := c.colorSetting(); enabled != nil {
+		return !*enabled
+	}
+	if !style.Styling() {
+		return true
+	}
+	return !isTerminal(w) && !colorForced()
+}
+
 // PrintSuccess prints a success message
 func (c *Command) PrintSuccess(msg string) {
-	fmt.Fprintln(c.OutOrStdout(), style.Success(msg))
+	c.printMessage(c.dryRunPrefix() + c.Theme().Success(msg))
 }
 
 // PrintError prints an error message
 func (c *Command) PrintError(msg string) {
-	fmt.Fprintln(c.ErrOrStderr(), style.Error(msg))
+	fmt.Fprintln(c.ErrOrStderr(), c.render(c.ErrOrStderr(), c.Theme().Error(msg)))
 }
 
 // PrintWarning prints a warning message
 func (c *Command) PrintWarning(msg string) {
-	fmt.Fprintln(c.OutOrStdout(), style.Warning(msg))
+	c.printMessage(c.Theme().Warning(msg))
 }
 
 // PrintInfo prints an info message
 func (c *Command) PrintInfo(msg string) {
-	fmt.Fprintln(c.OutOrStdout(), style.Info(msg))
+	c.printMessage(c.dryRunPrefix() + c.Theme().Info(msg))
 }
 
 // PrintHeader prints a header
 func (c *Command) PrintHeader(msg string) {
-	fmt.Fprintln(c.OutOrStdout(), style.Header(msg))
+	c.printMessage(c.Theme().Header(msg))
 }
 
 // PrintSubHeader prints a sub-header
 func (c *Command) PrintSubHeader(msg string) {
-	fmt.Fprintln(c.OutOrStdout(), style.SubHeader(msg))
+	c.printMessage(c.Theme().SubHeader(msg))
 }
 
 // PrintBullet prints a bullet point
 func (c *Command) PrintBullet(msg string) {
-	fmt.Fprintln(c.OutOrStdout(), style.Bullet(msg))
+	c.printMessage(c.Theme().Bullet(msg))
+}
+
+// PrintNumberedList prints items as an ordered list starting at 1, wrapping
+// long items under their text
+func (c *Command) PrintNumberedList(items []string) {
+	indent := len(strconv.Itoa(len(items))) + 2 // digits + ". "
+	wrapped := make([]string, len(items))
+	for i, item := range items {
+		wrapped[i] = ansi.Wrap(item, max(c.helpWidth()-indent, 20), "")
+	}
+	c.printDecorative(c.Theme().NumberedList(wrapped))
+}
+
+// PrintCheckList prints items with a checkmark when done and an empty box
+// otherwise, wrapping long items under their text
+func (c *Command) PrintCheckList(items []style.CheckItem) {
+	const indent = 4 // widest marker, "[x] "
+	wrapped := make([]style.CheckItem, len(items))
+	for i, item := range items {
+		wrapped[i] = style.CheckItem{Text: ansi.Wrap(item.Text, max(c.helpWidth()-indent, 20), ""), Done: item.Done}
+	}
+	c.printDecorative(c.Theme().CheckList(wrapped))
+}
+
+// PrintColumns prints items in as many columns as fit the terminal width,
+// like ls
+func (c *Command) PrintColumns(items []string) {
+	if len(items) == 0 {
+		return
+	}
+	c.printDecorative(style.Columns(items, c.helpWidth()))
+}
+
+// PrintKeyValues prints pairs as aligned "key: value" lines, such as the
+// details shown by a describe command
+func (c *Command) PrintKeyValues(pairs []style.KeyValue) {
+	if len(pairs) == 0 {
+		return
+	}
+	c.printDecorative(c.Theme().KeyValues(pairs))
 }
 
 // PrintBox prints text in a box
 func (c *Command) PrintBox(title, content string) {
-	fmt.Fprintln(c.OutOrStdout(), style.Box(title, content))
+	c.printMessage(c.Theme().Box(title, content))
+}
+
+// PrintBoxWithOptions prints text in a box using opts
+func (c *Command) PrintBoxWithOptions(title, content string, opts style.BoxOptions) {
+	c.printMessage(c.Theme().BoxWithOptions(title, content, opts))
 }
 
 // PrintCode prints code or technical text
 func (c *Command) PrintCode(code string) {
-	fmt.Fprintln(c.OutOrStdout(), style.Code(code))
+	c.printDecorative(c.Theme().Code(code))
+}
+
+// PrintTable prints rows as an aligned table with a styled header row
+func (c *Command) PrintTable(headers []string, rows [][]string) {
+	c.printDecorative(c.Theme().Table(headers, rows))
+}
+
+// PrintTableWithOptions prints rows as an aligned table using opts
+func (c *Command) PrintTableWithOptions(headers []string, rows [][]string, opts style.TableOptions) {
+	c.printDecorative(c.Theme().TableWithOptions(headers, rows, opts))
+}
+
+// PrintBanner prints text in a gradient from the theme's primary to its
+// secondary color
+func (c *Command) PrintBanner(text string) {
+	c.printMessage(c.Theme().Gradient(text))
+}
+
+// PrintDiff prints a line-based diff from oldText to newText in a box
+func (c *Command) PrintDiff(oldText, newText string) {
+	c.printDecorative(c.Theme().Diff(oldText, newText))
+}
+
+// PrintLink prints text as a clickable hyperlink to url, or "text (url)"
+// when the output is plain
+func (c *Command) PrintLink(text, url string) {
+	if c.plain(c.OutOrStdout()) || style.ColorProfile() == style.ProfileASCII {
+		c.printDecorative(style.PlainLink(text, url))
+		return
+	}
+	if text == "" {
+		text = url
+	}
+	c.printDecorative(ansi.SetHyperlink(url) + text + ansi.ResetHyperlink())
 }
@@ -2,8 +2,13 @@ package mamba
 
 import (
 	"bytes"
+	"io"
 	"strings"
 	"testing"
+
+	"github.com/base-go/mamba/pkg/style"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 func TestCommand_ModernHelp(t *testing.T) {
@@ -270,3 +275,712 @@ func TestCommand_UsageWithPlainHelp(t *testing.T) {
 		t.Errorf("Plain usage should contain 'Usage:', got: %s", output)
 	}
 }
+
+func TestCommand_HelpPlainWhenNotTerminal(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := &Command{
+		Use:   "test",
+		Short: "Test command",
+	}
+	cmd.Flags().String("name", "", "Name flag")
+	cmd.SetOutput(buf)
+
+	if err := cmd.Help(); err != nil {
+		t.Errorf("Help() error = %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, "Usage:") {
+		t.Errorf("Help to a non-terminal should use plain usage, got: %s", output)
+	}
+	if strings.Contains(output, "\x1b[") {
+		t.Errorf("Help to a non-terminal should not contain ANSI codes, got: %q", output)
+	}
+}
+
+func TestCommand_HelpModernWhenTerminal(t *testing.T) {
+	original := isTerminal
+	isTerminal = func(w io.Writer) bool { return true }
+	defer func() { isTerminal = original }()
+
+	buf := new(bytes.Buffer)
+	cmd := &Command{Use: "test", Short: "Test command"}
+	cmd.SetOutput(buf)
+
+	cmd.Help()
+
+	if strings.Contains(buf.String(), "Usage:") {
+		t.Errorf("Help to a terminal should use modern help, got: %s", buf.String())
+	}
+
+	// An explicit setting wins over detection
+	disabled := false
+	cmd.EnableColors = &disabled
+	buf.Reset()
+	cmd.Help()
+
+	if !strings.Contains(buf.String(), "Usage:") {
+		t.Errorf("Explicit EnableColors=false should use plain usage, got: %s", buf.String())
+	}
+}
+
+func TestCommand_HelpInheritsEnableColors(t *testing.T) {
+	original := isTerminal
+	isTerminal = func(w io.Writer) bool { return true }
+	defer func() { isTerminal = original }()
+
+	disabled := false
+	enabled := true
+	buf := new(bytes.Buffer)
+	root := &Command{Use: "root", EnableColors: &disabled}
+	mid := &Command{Use: "mid"}
+	leaf := &Command{Use: "leaf", Short: "Leaf command", Run: func(*Command, []string) {}}
+	root.AddCommand(mid)
+	mid.AddCommand(leaf)
+	root.SetOutput(buf)
+
+	leaf.Help()
+
+	if !strings.Contains(buf.String(), "Usage:") {
+		t.Errorf("Nested subcommand should inherit EnableColors=false, got: %s", buf.String())
+	}
+
+	// The nearest explicit setting wins
+	mid.EnableColors = &enabled
+	buf.Reset()
+	leaf.Help()
+
+	if strings.Contains(buf.String(), "Usage:") {
+		t.Errorf("EnableColors=true on the parent should override the root, got: %s", buf.String())
+	}
+}
+
+func TestCommand_SetTheme(t *testing.T) {
+	originalProfile := style.ColorProfile()
+	style.SetColorProfile(style.ProfileTrueColor)
+	defer style.SetColorProfile(originalProfile)
+
+	theme := style.DefaultTheme()
+	theme.SuccessColor = lipgloss.Color("#FF0000")
+
+	buf := new(bytes.Buffer)
+	withTerminalWriters(t, buf)
+	rootCmd := &Command{Use: "root"}
+	subCmd := &Command{Use: "sub"}
+	rootCmd.AddCommand(subCmd)
+	rootCmd.SetTheme(theme)
+	rootCmd.SetOutput(buf)
+
+	subCmd.PrintSuccess("done")
+
+	if !strings.Contains(buf.String(), "38;2;255;0;0") {
+		t.Errorf("PrintSuccess should use the inherited command theme, got: %q", buf.String())
+	}
+	if style.CurrentTheme().SuccessColor == theme.SuccessColor {
+		t.Error("Command.SetTheme should not change the global theme")
+	}
+}
+
+func TestCommand_PrintTable(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := &Command{Use: "test"}
+	cmd.SetOutput(buf)
+
+	cmd.PrintTable([]string{"Name", "Status"}, [][]string{{"api", "running"}, {"worker", "stopped"}})
+
+	output := buf.String()
+	for _, want := range []string{"Name", "Status", "api", "running", "worker", "stopped"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("PrintTable should output %q, got: %s", want, output)
+		}
+	}
+}
+
+func newFlagTree() (root, mid, leaf *Command) {
+	root = &Command{Use: "app"}
+	root.PersistentFlags().String("config", "", "Config file")
+	mid = &Command{Use: "remote"}
+	mid.PersistentFlags().Bool("verbose", false, "Verbose output")
+	mid.Flags().String("mid-only", "", "Not inherited")
+	leaf = &Command{Use: "add"}
+	leaf.Flags().Int("port", 22, "Port")
+	mid.AddCommand(leaf)
+	root.AddCommand(mid)
+	return root, mid, leaf
+}
+
+func TestCommand_InheritedFlags(t *testing.T) {
+	_, _, leaf := newFlagTree()
+
+	inherited := leaf.InheritedFlags()
+	for _, name := range []string{"config", "verbose"} {
+		if inherited.Lookup(name) == nil {
+			t.Errorf("InheritedFlags should include ancestor persistent flag %q", name)
+		}
+	}
+	if inherited.Lookup("mid-only") != nil || inherited.Lookup("port") != nil {
+		t.Error("InheritedFlags should only include ancestor persistent flags")
+	}
+
+	own := leaf.NonInheritedFlags()
+	if own.Lookup("port") == nil {
+		t.Error("NonInheritedFlags should include the command's own flags")
+	}
+	if own.Lookup("config") != nil || own.Lookup("verbose") != nil {
+		t.Error("NonInheritedFlags should not include inherited flags")
+	}
+}
+
+func TestCommand_GrandparentPersistentFlagParses(t *testing.T) {
+	root, _, leaf := newFlagTree()
+	var config string
+	leaf.Run = func(cmd *Command, args []string) { config, _ = cmd.GetStringFlag("config") }
+	root.SetOutput(new(bytes.Buffer))
+
+	if err := root.execute([]string{"remote", "add", "--config", "app.yaml"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if config != "app.yaml" {
+		t.Errorf("config = %q, want app.yaml", config)
+	}
+}
+
+func TestCommand_ModernHelpGlobalFlagsFromAllAncestors(t *testing.T) {
+	_, _, leaf := newFlagTree()
+
+	help := leaf.ModernHelp()
+	global := strings.Join(helpSection(help, "Global Flags"), "\n")
+	local := strings.Join(helpSection(help, "Flags"), "\n")
+
+	for _, want := range []string{"--config", "--verbose"} {
+		if !strings.Contains(global, want) {
+			t.Errorf("Global Flags should contain %s, got:\n%s", want, global)
+		}
+	}
+	if !strings.Contains(local, "--port") || strings.Contains(local, "--config") {
+		t.Errorf("Flags should only contain the command's own flags, got:\n%s", local)
+	}
+}
+
+func TestCommand_GlobalFlagsShadowedAndDeduplicated(t *testing.T) {
+	root := &Command{Use: "app"}
+	root.PersistentFlags().String("config", "", "Config file")
+	root.PersistentFlags().String("region", "", "Root region")
+	mid := &Command{Use: "remote"}
+	mid.PersistentFlags().String("region", "", "Remote region")
+	leaf := &Command{Use: "add"}
+	leaf.Flags().String("config", "", "Leaf config")
+	mid.AddCommand(leaf)
+	root.AddCommand(mid)
+
+	for name, help := range map[string]string{"modern": leaf.ModernHelp(), "plain": leaf.UsageString()} {
+		global := strings.Join(helpSection(help, "Global Flags"), "\n")
+		if strings.Contains(global, "--config") {
+			t.Errorf("%s: Global Flags should skip flags shadowed by local ones, got:\n%s", name, global)
+		}
+		if strings.Count(global, "--region") != 1 || !strings.Contains(global, "Remote region") {
+			t.Errorf("%s: Global Flags should list --region once, from the closest ancestor, got:\n%s", name, global)
+		}
+	}
+}
+
+func TestCommand_UsageStringGlobalFlags(t *testing.T) {
+	_, _, leaf := newFlagTree()
+
+	global := strings.Join(helpSection(leaf.UsageString(), "Global Flags"), "\n")
+	if !strings.Contains(global, "--config") {
+		t.Errorf("plain usage should list the grandparent's --config under Global Flags, got:\n%s", global)
+	}
+}
+
+func TestCommand_ModernHelpTypeHints(t *testing.T) {
+	cmd := &Command{Use: "test"}
+	cmd.Flags().StringSlice("tags", nil, "Tags")
+	cmd.Flags().StringArray("env", nil, "Environment")
+	cmd.Flags().IntSlice("ports", nil, "Ports")
+	cmd.Flags().Duration("timeout", 0, "Timeout")
+	cmd.Flags().CountP("verbose", "v", "Verbosity")
+	cmd.Flags().Int64("size", 0, "Size")
+	cmd.Flags().String("config", "", "Config file")
+	if err := cmd.SetFlagTypeHint("config", "file"); err != nil {
+		t.Fatalf("SetFlagTypeHint() error = %v", err)
+	}
+
+	help := cmd.ModernHelp()
+
+	tests := map[string]string{
+		"--tags":    "<strings>",
+		"--env":     "<strings>",
+		"--ports":   "<ints>",
+		"--timeout": "<duration>",
+		"--size":    "<int>",
+		"--config":  "<file>",
+	}
+	for flag, hint := range tests {
+		line := helpLine(help, flag)
+		if !strings.Contains(line, hint) {
+			t.Errorf("%s should have hint %s, got: %q", flag, hint, line)
+		}
+	}
+	if line := helpLine(help, "--verbose"); strings.Contains(line, "<") {
+		t.Errorf("count flags should have no hint, got: %q", line)
+	}
+
+	if err := cmd.SetFlagTypeHint("missing", "x"); err == nil {
+		t.Error("SetFlagTypeHint should fail for unknown flags")
+	}
+}
+
+func TestCommand_ModernHelpFlagGroups(t *testing.T) {
+	cmd := &Command{Use: "deploy", Run: func(cmd *Command, args []string) {}}
+	cmd.Flags().String("host", "", "Server host")
+	cmd.Flags().Int("port", 0, "Server port")
+	cmd.Flags().String("token", "", "API token")
+	cmd.Flags().String("username", "", "Login name")
+	cmd.Flags().Bool("force", false, "Skip checks")
+	for flag, group := range map[string]string{"host": "Network", "port": "Network", "token": "Auth", "username": "Auth"} {
+		if err := cmd.SetFlagGroup(flag, group); err != nil {
+			t.Fatalf("SetFlagGroup(%q) error = %v", flag, err)
+		}
+	}
+	cmd.InitDefaultHelpFlag()
+
+	help := ansi.Strip(cmd.ModernHelp())
+
+	sections := map[string][]string{
+		"Flags":   {"--force", "--help"},
+		"Network": {"--host", "--port"},
+		"Auth":    {"--token", "--username"},
+	}
+	for title, flags := range sections {
+		section := helpSection(help, title)
+		if len(section) != len(flags) {
+			t.Errorf("%s section = %q, want %d flags", title, section, len(flags))
+			continue
+		}
+		column := -1
+		for i, flag := range flags {
+			if !strings.Contains(section[i], flag) {
+				t.Errorf("%s section line %d = %q, want %s", title, i, section[i], flag)
+			}
+			// Descriptions line up within each group
+			start := strings.Index(section[i], flag) + len(flag)
+			desc := start + len(section[i][start:]) - len(strings.TrimLeft(section[i][start:], " "))
+			if column == -1 {
+				column = desc
+			} else if desc != column {
+				t.Errorf("%s section is misaligned: %q", title, section)
+			}
+		}
+	}
+
+	// Groups follow the ungrouped flags, in order of their first flag
+	if !(strings.Index(help, "Flags") < strings.Index(help, "Network") && strings.Index(help, "Network") < strings.Index(help, "Auth")) {
+		t.Errorf("flag sections out of order:\n%s", help)
+	}
+
+	if err := cmd.SetFlagGroup("missing", "Network"); err == nil {
+		t.Error("SetFlagGroup should fail for unknown flags")
+	}
+}
+
+// helpLine returns the first line of help containing s
+func helpLine(help, s string) string {
+	for _, line := range strings.Split(help, "\n") {
+		if strings.Contains(line, s) {
+			return line
+		}
+	}
+	return ""
+}
+
+func TestCommand_ModernHelpWrapsToWidth(t *testing.T) {
+	cmd := &Command{
+		Use:     "deploy",
+		Long:    "Deploy builds the application, uploads the artifacts to every configured region, and waits for health checks to pass.\n\nRollbacks happen automatically.",
+		Example: "deploy --region us-east-1 --region eu-west-1 --replicas 3 --wait --timeout 10m production",
+	}
+	cmd.Flags().String("strategy", "rolling", "How instances are replaced: rolling replaces one at a time, recreate stops everything first")
+	cmd.SetHelpWidth(60)
+
+	help := cmd.ModernHelp()
+
+	for _, line := range strings.Split(help, "\n") {
+		if w := lipgloss.Width(line); w > 60 {
+			t.Errorf("line is %d columns wide, want at most 60: %q", w, line)
+		}
+	}
+	if !strings.Contains(help, "pass.\n\nRollbacks") {
+		t.Errorf("paragraph breaks should be kept, got:\n%s", help)
+	}
+
+	// Continuation lines of a flag description line up under the first line
+	lines := strings.Split(help, "\n")
+	for i, line := range lines {
+		if strings.Contains(line, "--strategy") {
+			descCol := strings.Index(line, "How")
+			if next := lines[i+1]; strings.TrimSpace(next) == "" || strings.Index(next, strings.TrimSpace(next)) != descCol {
+				t.Errorf("wrapped flag description should align at column %d, got %q", descCol, next)
+			}
+		}
+	}
+}
+
+func TestCommand_ModernHelpDefaultWidth(t *testing.T) {
+	cmd := &Command{Use: "test", Long: strings.Repeat("word ", 30)}
+
+	if w := cmd.helpWidth(); w != defaultHelpWidth {
+		t.Errorf("helpWidth() = %d, want %d when the output is not a terminal", w, defaultHelpWidth)
+	}
+	for _, line := range strings.Split(cmd.ModernHelp(), "\n") {
+		if w := lipgloss.Width(line); w > defaultHelpWidth {
+			t.Errorf("line is %d columns wide, want at most %d: %q", w, defaultHelpWidth, line)
+		}
+	}
+}
+
+func TestCommand_PrintLink(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := &Command{Use: "test"}
+	cmd.SetOutput(buf)
+
+	cmd.PrintLink("docs", "https://example.com/docs")
+
+	if got := buf.String(); got != "docs (https://example.com/docs)\n" {
+		t.Errorf("PrintLink should fall back to plain text, got: %q", got)
+	}
+}
+
+func TestCommand_PrintHelpersRespectEnableColors(t *testing.T) {
+	originalProfile := style.ColorProfile()
+	style.SetColorProfile(style.ProfileTrueColor)
+	defer style.SetColorProfile(originalProfile)
+
+	disabled := false
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	root := &Command{Use: "root", EnableColors: &disabled}
+	sub := &Command{Use: "sub"}
+	root.AddCommand(sub)
+	root.SetOutput(stdout)
+	root.SetErr(stderr)
+
+	sub.PrintSuccess("deployed")
+	sub.PrintWarning("slow")
+	sub.PrintInfo("3 pods")
+	sub.PrintLink("docs", "https://example.com/docs")
+	sub.PrintError("failed")
+
+	out := stdout.String() + stderr.String()
+	if strings.Contains(out, "\x1b") {
+		t.Errorf("Print helpers with EnableColors=false should not emit escape codes, got: %q", out)
+	}
+	for _, want := range []string{
+		style.SuccessIcon + " deployed",
+		"slow",
+		"3 pods",
+		"docs (https://example.com/docs)",
+		"failed",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output should contain %q, got: %q", want, out)
+		}
+	}
+
+	enabled := true
+	root.EnableColors = &enabled
+	stdout.Reset()
+	sub.PrintSuccess("deployed")
+
+	if !strings.Contains(stdout.String(), "\x1b[") {
+		t.Errorf("PrintSuccess with EnableColors=true should be styled, got: %q", stdout.String())
+	}
+}
+
+// withTerminalWriters makes only the given writers count as terminals for
+// the duration of a test
+func withTerminalWriters(t *testing.T, writers ...io.Writer) {
+	t.Helper()
+	original := isTerminal
+	isTerminal = func(w io.Writer) bool {
+		for _, tty := range writers {
+			if w == tty {
+				return true
+			}
+		}
+		return false
+	}
+	t.Cleanup(func() { isTerminal = original })
+}
+
+// withDetectedColor makes a color profile set by the test behave as if it
+// were detected rather than forced
+func withDetectedColor(t *testing.T) {
+	t.Helper()
+	original := colorForced
+	colorForced = func() bool { return false }
+	t.Cleanup(func() { colorForced = original })
+}
+
+func TestCommand_PrintPerWriterTerminal(t *testing.T) {
+	originalProfile := style.ColorProfile()
+	style.SetColorProfile(style.ProfileTrueColor)
+	defer style.SetColorProfile(originalProfile)
+	withDetectedColor(t)
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	withTerminalWriters(t, stdout)
+	cmd := &Command{Use: "test"}
+	cmd.SetOutput(stdout)
+	cmd.SetErr(stderr)
+
+	cmd.PrintInfo("3 pods")
+	cmd.PrintError("failed")
+
+	if !strings.Contains(stdout.String(), "\x1b[") {
+		t.Errorf("PrintInfo to a terminal should be styled, got: %q", stdout.String())
+	}
+	if strings.Contains(stderr.String(), "\x1b") || !strings.Contains(stderr.String(), "failed") {
+		t.Errorf("PrintError to a redirected stderr should be plain, got: %q", stderr.String())
+	}
+
+	// With stderr a terminal and stdout redirected, it is the other way around
+	stdout.Reset()
+	stderr.Reset()
+	withTerminalWriters(t, stderr)
+
+	cmd.PrintInfo("3 pods")
+	cmd.PrintError("failed")
+
+	if strings.Contains(stdout.String(), "\x1b") {
+		t.Errorf("PrintInfo to a redirected stdout should be plain, got: %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "\x1b[") {
+		t.Errorf("PrintError to a terminal should be styled, got: %q", stderr.String())
+	}
+}
+
+func TestCommand_PrintForcedColorRedirected(t *testing.T) {
+	originalProfile := style.ColorProfile()
+	style.SetColorProfile(style.ProfileTrueColor)
+	defer style.SetColorProfile(originalProfile)
+
+	buf := new(bytes.Buffer)
+	cmd := &Command{Use: "test"}
+	cmd.SetOutput(buf)
+
+	cmd.PrintInfo("3 pods")
+	cmd.PrintLink("docs", "https://example.com")
+
+	if !strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("forced color should be kept when redirected, got: %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "\x1b]8;;https://example.com") {
+		t.Errorf("PrintLink should keep the hyperlink with forced color, got: %q", buf.String())
+	}
+
+	// Without forced color the link falls back to its plain form
+	withDetectedColor(t)
+	buf.Reset()
+	cmd.PrintLink("docs", "https://example.com")
+
+	if got := buf.String(); got != "docs (https://example.com)\n" {
+		t.Errorf("PrintLink to a redirected writer = %q, want the plain link", got)
+	}
+}
+
+func TestCommand_PrintNumberedList(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := &Command{Use: "test"}
+	cmd.SetOutput(buf)
+	cmd.SetHelpWidth(30)
+
+	cmd.PrintNumberedList([]string{"Build", "Run the integration tests against staging", "Deploy"})
+
+	want := strings.Join([]string{
+		"1. Build",
+		"2. Run the integration tests",
+		"   against staging",
+		"3. Deploy",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Errorf("PrintNumberedList() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCommand_PrintCheckList(t *testing.T) {
+	withUnicode(t, false)
+	buf := new(bytes.Buffer)
+	cmd := &Command{Use: "test"}
+	cmd.SetOutput(buf)
+
+	cmd.PrintCheckList([]style.CheckItem{{Text: "Build", Done: true}, {Text: "Deploy"}})
+
+	if got, want := buf.String(), "[x] Build\n[ ] Deploy\n"; got != want {
+		t.Errorf("PrintCheckList() = %q, want %q", got, want)
+	}
+}
+
+func TestCommand_PrintColumns(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := &Command{Use: "test"}
+	cmd.SetOutput(buf)
+	cmd.SetHelpWidth(22)
+
+	cmd.PrintColumns([]string{"api", "cli", "docs", "web", "worker"})
+
+	want := "api     docs    worker\ncli     web\n"
+	if got := buf.String(); got != want {
+		t.Errorf("PrintColumns() = %q, want %q", got, want)
+	}
+}
+
+func TestCommand_PrintKeyValues(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := &Command{Use: "test"}
+	cmd.SetOutput(buf)
+
+	cmd.PrintKeyValues([]style.KeyValue{
+		{Key: "Name", Value: "api"},
+		{Key: "Events", Value: "Pulled image\nStarted container"},
+	})
+
+	want := "  Name: api\nEvents: Pulled image\n        Started container\n"
+	if got := buf.String(); got != want {
+		t.Errorf("PrintKeyValues() = %q, want %q", got, want)
+	}
+}
+
+func TestCommand_PrintBanner(t *testing.T) {
+	originalProfile := style.ColorProfile()
+	style.SetColorProfile(style.ProfileTrueColor)
+	defer style.SetColorProfile(originalProfile)
+
+	buf := new(bytes.Buffer)
+	withTerminalWriters(t, buf)
+	cmd := &Command{Use: "test"}
+	cmd.SetOutput(buf)
+
+	cmd.PrintBanner("Mamba")
+
+	if got := strings.Count(buf.String(), "38;2;"); got != 5 {
+		t.Errorf("PrintBanner should color each rune, got %d colors in %q", got, buf.String())
+	}
+	if got := ansi.Strip(buf.String()); got != "Mamba\n" {
+		t.Errorf("PrintBanner text = %q, want %q", got, "Mamba\n")
+	}
+}
+
+func TestCommand_PrintDiff(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := &Command{Use: "test"}
+	cmd.SetOutput(buf)
+
+	cmd.PrintDiff("replicas: 1\n", "replicas: 3\n")
+
+	for _, want := range []string{"- replicas: 1", "+ replicas: 3"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("PrintDiff should contain %q, got:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestCommand_HelpCommand(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd := &Command{Use: "app", Short: "My application"}
+	rootCmd.AddCommand(&Command{Use: "deploy", Short: "Deploy the app", Run: func(*Command, []string) {}})
+	rootCmd.SetOutput(buf)
+
+	if err := rootCmd.execute([]string{"help", "deploy"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "app deploy") {
+		t.Errorf("help deploy should show the deploy help, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := rootCmd.execute([]string{"help"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if line := helpLine(buf.String(), "Help about any command"); !strings.HasPrefix(strings.TrimSpace(line), "help ") {
+		t.Errorf("root help should list the help command, got:\n%s", buf.String())
+	}
+
+	rootCmd.SetErr(new(bytes.Buffer))
+	if err := rootCmd.execute([]string{"help", "nope"}); err == nil {
+		t.Error("help for an unknown command should fail")
+	}
+}
+
+func TestCommand_DisableAutoHelpCommand(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd := &Command{Use: "app", DisableAutoHelpCommand: true}
+	rootCmd.AddCommand(&Command{Use: "deploy", Run: func(*Command, []string) {}})
+	rootCmd.SetOutput(buf)
+	rootCmd.SetErr(new(bytes.Buffer))
+
+	if err := rootCmd.execute([]string{"help"}); err == nil {
+		t.Error("help should be an unknown command when the help command is disabled")
+	}
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == "help" {
+			t.Error("Expected no help command to be added")
+		}
+	}
+	if strings.Contains(buf.String(), "Help about any command") {
+		t.Errorf("help output should not list the help command, got:\n%s", buf.String())
+	}
+}
+
+func TestCommand_HiddenAutoHelpCommand(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd := &Command{Use: "app", HiddenAutoHelpCommand: true}
+	rootCmd.AddCommand(&Command{Use: "deploy", Short: "Deploy the app", Run: func(*Command, []string) {}})
+	rootCmd.SetOutput(buf)
+
+	if err := rootCmd.execute([]string{"--help"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "Help about any command") {
+		t.Errorf("help output should not list the hidden help command, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := rootCmd.execute([]string{"help", "deploy"}); err != nil {
+		t.Fatalf("hidden help command should still run, got error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "app deploy") {
+		t.Errorf("help deploy should show the deploy help, got:\n%s", buf.String())
+	}
+}
+
+func TestCommand_SetHelpCommand(t *testing.T) {
+	called := false
+	rootCmd := &Command{Use: "app"}
+	rootCmd.AddCommand(&Command{Use: "deploy", Run: func(*Command, []string) {}})
+	rootCmd.SetHelpCommand(&Command{Use: "help", Hidden: true, Run: func(*Command, []string) { called = true }})
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	if err := rootCmd.execute([]string{"help"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if !called {
+		t.Error("the custom help command should run")
+	}
+}
+
+func TestCommand_PrintBoxWithOptions(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := &Command{Use: "test"}
+	cmd.SetOutput(buf)
+
+	cmd.PrintBoxWithOptions("", "one two three four five six seven", style.BoxOptions{Width: 16})
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if w := lipgloss.Width(line); w != 16 {
+			t.Errorf("line %q is %d columns wide, want 16", line, w)
+		}
+	}
+}
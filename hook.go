@@ -0,0 +1,51 @@
+package mamba
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExecutionHook observes command runs, for example to record metrics or
+// traces. AfterRun is called even when the run returns an error or panics.
+type ExecutionHook interface {
+	BeforeRun(cmd *Command, args []string)
+	AfterRun(cmd *Command, args []string, err error, dur time.Duration)
+}
+
+// SetExecutionHook sets the hook called around the run phase of this command
+// and its subcommands
+func (c *Command) SetExecutionHook(h ExecutionHook) {
+	c.executionHook = h
+}
+
+// ExecutionHook returns the execution hook, inherited from the nearest
+// ancestor with one set
+func (c *Command) ExecutionHook() ExecutionHook {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.executionHook != nil {
+			return cmd.executionHook
+		}
+	}
+	return nil
+}
+
+// executeRunWithHook runs the command, reporting to the execution hook.
+// A panic is reported as an error and then re-raised.
+func (c *Command) executeRunWithHook(args []string) (err error) {
+	h := c.ExecutionHook()
+	if h == nil {
+		return c.executeRun(args)
+	}
+
+	h.BeforeRun(c, args)
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			h.AfterRun(c, args, fmt.Errorf("panic: %v", r), time.Since(start))
+			panic(r)
+		}
+		h.AfterRun(c, args, err, time.Since(start))
+	}()
+
+	return c.executeRun(args)
+}
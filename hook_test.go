@@ -0,0 +1,77 @@
+package mamba
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordingHook struct {
+	before []string
+	after  []string
+	err    error
+	dur    time.Duration
+}
+
+func (h *recordingHook) BeforeRun(cmd *Command, args []string) {
+	h.before = append(h.before, cmd.Name())
+}
+
+func (h *recordingHook) AfterRun(cmd *Command, args []string, err error, dur time.Duration) {
+	h.after = append(h.after, cmd.Name())
+	h.err = err
+	h.dur = dur
+}
+
+func TestCommand_ExecutionHook(t *testing.T) {
+	hook := &recordingHook{}
+	runErr := errors.New("deploy failed")
+	rootCmd := &Command{Use: "app", SilenceErrors: true, SilenceUsage: true}
+	rootCmd.AddCommand(&Command{
+		Use: "deploy",
+		RunE: func(cmd *Command, args []string) error {
+			time.Sleep(time.Millisecond)
+			return runErr
+		},
+	})
+	rootCmd.SetExecutionHook(hook)
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	err := rootCmd.execute([]string{"deploy"})
+
+	if !errors.Is(err, runErr) {
+		t.Fatalf("execute() error = %v, want %v", err, runErr)
+	}
+	if len(hook.before) != 1 || hook.before[0] != "deploy" || len(hook.after) != 1 || hook.after[0] != "deploy" {
+		t.Errorf("hook should be inherited and called once, before=%v after=%v", hook.before, hook.after)
+	}
+	if !errors.Is(hook.err, runErr) {
+		t.Errorf("AfterRun error = %v, want %v", hook.err, runErr)
+	}
+	if hook.dur <= 0 {
+		t.Errorf("AfterRun duration = %v, want > 0", hook.dur)
+	}
+}
+
+func TestCommand_ExecutionHookPanic(t *testing.T) {
+	hook := &recordingHook{}
+	cmd := &Command{
+		Use: "app",
+		Run: func(cmd *Command, args []string) { panic("boom") },
+	}
+	cmd.SetExecutionHook(hook)
+	cmd.SetOutput(new(bytes.Buffer))
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("panic should be re-raised, got: %v", r)
+		}
+		if hook.err == nil || !strings.Contains(hook.err.Error(), "boom") {
+			t.Errorf("AfterRun should see the panic as an error, got: %v", hook.err)
+		}
+	}()
+
+	cmd.execute(nil)
+}
@@ -0,0 +1,30 @@
+package mamba
+
+var (
+	initializers []func()
+	finalizers   []func()
+)
+
+// OnInitialize registers functions to run when a command is executed, after
+// its flags are parsed and before its hooks and Run, such as to load config
+func OnInitialize(funcs ...func()) {
+	initializers = append(initializers, funcs...)
+}
+
+// OnFinalize registers functions to run after a command is executed, even
+// when it returns an error
+func OnFinalize(funcs ...func()) {
+	finalizers = append(finalizers, funcs...)
+}
+
+func runInitializers() {
+	for _, f := range initializers {
+		f()
+	}
+}
+
+func runFinalizers() {
+	for _, f := range finalizers {
+		f()
+	}
+}
@@ -0,0 +1,80 @@
+package mamba
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// withInitializers clears the registered callbacks for the test
+func withInitializers(t *testing.T) {
+	t.Helper()
+	savedInit, savedFinal := initializers, finalizers
+	initializers, finalizers = nil, nil
+	t.Cleanup(func() { initializers, finalizers = savedInit, savedFinal })
+}
+
+func TestOnInitializeAndOnFinalize(t *testing.T) {
+	withInitializers(t)
+
+	var events []string
+	var configAtInit string
+	OnInitialize(func() { events = append(events, "init") })
+	OnFinalize(func() { events = append(events, "final") })
+
+	rootCmd := &Command{
+		Use:              "app",
+		PersistentPreRun: func(cmd *Command, args []string) { events = append(events, "persistent-pre") },
+	}
+	var config string
+	rootCmd.PersistentFlags().StringVar(&config, "config", "", "Config file")
+	OnInitialize(func() { configAtInit = config })
+
+	remoteCmd := &Command{Use: "remote"}
+	remoteCmd.AddCommand(&Command{
+		Use: "add",
+		Run: func(cmd *Command, args []string) { events = append(events, "run") },
+	})
+	rootCmd.AddCommand(remoteCmd)
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	if err := rootCmd.execute([]string{"remote", "add", "--config", "app.yaml"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+
+	want := []string{"init", "persistent-pre", "run", "final"}
+	if len(events) != len(want) {
+		t.Fatalf("events = %v, want %v", events, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("events = %v, want %v", events, want)
+			break
+		}
+	}
+	if configAtInit != "app.yaml" {
+		t.Errorf("initializers should see parsed flags, got config = %q", configAtInit)
+	}
+}
+
+func TestOnFinalizeRunsOnError(t *testing.T) {
+	withInitializers(t)
+
+	finalized := 0
+	OnFinalize(func() { finalized++ })
+
+	cmd := &Command{
+		Use:           "app",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE:          func(cmd *Command, args []string) error { return errors.New("failed") },
+	}
+	cmd.SetOutput(new(bytes.Buffer))
+
+	if err := cmd.execute(nil); err == nil {
+		t.Fatal("execute() should return the run error")
+	}
+	if finalized != 1 {
+		t.Errorf("finalizers ran %d times, want 1", finalized)
+	}
+}
@@ -0,0 +1,81 @@
+package mamba
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/base-go/mamba/pkg/style"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PrintJSON prints v as indented JSON, syntax-highlighted when the output is
+// styled and the color profile has color
+func (c *Command) PrintJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	out := string(data)
+	if !c.plain(c.OutOrStdout()) && style.ColorProfile() != style.ProfileASCII {
+		out = highlightJSON(out, c.Theme())
+	}
+	_, err = fmt.Fprintln(c.OutOrStdout(), out)
+	return err
+}
+
+// highlightJSON colors the keys, strings, numbers, and literals of valid
+// JSON. Punctuation and whitespace are left as is.
+func highlightJSON(s string, t style.Theme) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		switch ch := s[i]; {
+		case ch == '"':
+			end := i + 1
+			for end < len(s) && s[end] != '"' {
+				if s[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end++
+			color := t.SuccessColor
+			if isJSONKey(s[end:]) {
+				color = t.InfoColor
+			}
+			sb.WriteString(colorize(s[i:end], color))
+			i = end
+		case ch == '-' || (ch >= '0' && ch <= '9'):
+			end := i + 1
+			for end < len(s) && strings.IndexByte("0123456789.eE+-", s[end]) >= 0 {
+				end++
+			}
+			sb.WriteString(colorize(s[i:end], t.AccentColor))
+			i = end
+		case ch == 't' || ch == 'f' || ch == 'n':
+			end := i + 1
+			for end < len(s) && s[end] >= 'a' && s[end] <= 'z' {
+				end++
+			}
+			sb.WriteString(colorize(s[i:end], t.PrimaryColor))
+			i = end
+		default:
+			sb.WriteByte(ch)
+			i++
+		}
+	}
+	return sb.String()
+}
+
+// colorize renders s in color. Unlike style.Colorize it ignores
+// DisableStyling, since PrintJSON has already decided to highlight.
+func colorize(s string, color lipgloss.Color) string {
+	return lipgloss.NewStyle().Foreground(color).Render(s)
+}
+
+// isJSONKey reports whether the string that was just read is an object key,
+// that is, rest starts with a colon after any whitespace
+func isJSONKey(rest string) bool {
+	return strings.HasPrefix(strings.TrimLeft(rest, " \t\r\n"), ":")
+}
@@ -0,0 +1,98 @@
+package mamba
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/base-go/mamba/pkg/style"
+	"github.com/charmbracelet/x/ansi"
+)
+
+type jsonTestRecord struct {
+	Name    string  `json:"name"`
+	Quote   string  `json:"quote"`
+	Replica int     `json:"replica"`
+	Ratio   float64 `json:"ratio"`
+	Ready   bool    `json:"ready"`
+	Owner   *string `json:"owner"`
+}
+
+var jsonTestValue = jsonTestRecord{Name: "api", Quote: `say "hi"`, Replica: -3, Ratio: 1.5e-7, Ready: true}
+
+func TestCommand_PrintJSONPlain(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := &Command{Use: "app"}
+	cmd.SetOutput(buf)
+
+	if err := cmd.PrintJSON(jsonTestValue); err != nil {
+		t.Fatalf("PrintJSON() error = %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("PrintJSON to a non-terminal should not contain escape codes, got: %q", out)
+	}
+	var got jsonTestRecord
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("PrintJSON output is not valid JSON: %v\n%s", err, out)
+	}
+	if got != jsonTestValue {
+		t.Errorf("PrintJSON round trip = %+v, want %+v", got, jsonTestValue)
+	}
+}
+
+func TestCommand_PrintJSONHighlighted(t *testing.T) {
+	originalProfile := style.ColorProfile()
+	style.SetColorProfile(style.ProfileTrueColor)
+	defer style.SetColorProfile(originalProfile)
+
+	enabled := true
+	buf := new(bytes.Buffer)
+	cmd := &Command{Use: "app", EnableColors: &enabled}
+	cmd.SetOutput(buf)
+
+	if err := cmd.PrintJSON(jsonTestValue); err != nil {
+		t.Fatalf("PrintJSON() error = %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "\x1b[") {
+		t.Errorf("PrintJSON with colors enabled should contain escape codes, got: %q", out)
+	}
+	var got jsonTestRecord
+	if err := json.Unmarshal([]byte(ansi.Strip(out)), &got); err != nil {
+		t.Fatalf("PrintJSON output is not valid JSON once stripped: %v\n%s", err, out)
+	}
+	if got != jsonTestValue {
+		t.Errorf("PrintJSON round trip = %+v, want %+v", got, jsonTestValue)
+	}
+}
+
+func TestCommand_PrintJSONColorsDisabled(t *testing.T) {
+	originalProfile := style.ColorProfile()
+	style.SetColorProfile(style.ProfileTrueColor)
+	defer style.SetColorProfile(originalProfile)
+
+	disabled := false
+	buf := new(bytes.Buffer)
+	cmd := &Command{Use: "app", EnableColors: &disabled}
+	cmd.SetOutput(buf)
+
+	if err := cmd.PrintJSON(map[string]int{"count": 1}); err != nil {
+		t.Fatalf("PrintJSON() error = %v", err)
+	}
+	if want := "{\n  \"count\": 1\n}\n"; buf.String() != want {
+		t.Errorf("PrintJSON() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestCommand_PrintJSONError(t *testing.T) {
+	cmd := &Command{Use: "app"}
+	cmd.SetOutput(new(bytes.Buffer))
+
+	if err := cmd.PrintJSON(make(chan int)); err == nil {
+		t.Error("PrintJSON should return the marshal error for unsupported values")
+	}
+}
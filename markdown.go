@@ -0,0 +1,128 @@
+package mamba
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// GenMarkdown writes Markdown documentation for the command to w, with its
+// synopsis, description, examples, flags, and links to related commands
+func (c *Command) GenMarkdown(w io.Writer) error {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "## %s\n\n", c.CommandPath())
+	if c.Short != "" {
+		fmt.Fprintf(&sb, "%s\n\n", c.Short)
+	}
+
+	sb.WriteString("### Synopsis\n\n")
+	if c.Long != "" {
+		fmt.Fprintf(&sb, "%s\n\n", c.Long)
+	}
+	fmt.Fprintf(&sb, "```\n%s\n```\n\n", strings.Join(c.usageLines(), "\n"))
+
+	if c.Example != "" {
+		fmt.Fprintf(&sb, "### Examples\n\n```\n%s\n```\n\n", strings.Trim(c.Example, "\n"))
+	}
+
+	if flags := c.NonInheritedFlags(); flags.HasAvailableFlags() {
+		sb.WriteString("### Options\n\n")
+		writeMarkdownFlags(&sb, flags)
+	}
+	if flags := c.globalFlags(); flags.HasAvailableFlags() {
+		sb.WriteString("### Options inherited from parent commands\n\n")
+		writeMarkdownFlags(&sb, flags)
+	}
+
+	var related []*Command
+	if c.HasParent() {
+		related = append(related, c.Parent())
+	}
+	for _, cmd := range c.commands {
+		if !cmd.Hidden {
+			related = append(related, cmd)
+		}
+	}
+	if len(related) > 0 {
+		sb.WriteString("### See also\n\n")
+		for _, cmd := range related {
+			fmt.Fprintf(&sb, "* [%s](%s)", cmd.CommandPath(), markdownFilename(cmd))
+			if cmd.Short != "" {
+				fmt.Fprintf(&sb, " - %s", cmd.Short)
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, strings.TrimSuffix(sb.String(), "\n"))
+	if err == nil {
+		_, err = io.WriteString(w, "\n")
+	}
+	return err
+}
+
+// GenMarkdownTree writes Markdown documentation for the command and its
+// visible subcommands to dir, one file per command named by its full path,
+// such as "app_config_set.md"
+func (c *Command) GenMarkdownTree(dir string) error {
+	if c.Hidden {
+		return nil
+	}
+	for _, cmd := range c.commands {
+		if err := cmd.GenMarkdownTree(dir); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(filepath.Join(dir, markdownFilename(c)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := c.GenMarkdown(f); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// markdownFilename returns the name of the file documenting cmd
+func markdownFilename(cmd *Command) string {
+	return strings.ReplaceAll(cmd.CommandPath(), " ", "_") + ".md"
+}
+
+// writeMarkdownFlags writes the visible flags in fs as a Markdown table
+func writeMarkdownFlags(sb *strings.Builder, fs *pflag.FlagSet) {
+	sb.WriteString("| Flag | Type | Default | Description |\n")
+	sb.WriteString("| --- | --- | --- | --- |\n")
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		name := "`--" + f.Name + "`"
+		if f.Shorthand != "" {
+			name = "`-" + f.Shorthand + "`, " + name
+		}
+		typ := flagTypeHint(f)
+		if typ == "" {
+			typ = f.Value.Type()
+		}
+		def := ""
+		if f.DefValue != "" && f.DefValue != "[]" {
+			def = "`" + f.DefValue + "`"
+		}
+		fmt.Fprintf(sb, "| %s | %s | %s | %s |\n", name, typ, def, markdownCell(f.Usage))
+	})
+	sb.WriteString("\n")
+}
+
+// markdownCell escapes s for use in a Markdown table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
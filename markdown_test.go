@@ -0,0 +1,118 @@
+package mamba
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestCommand_GenMarkdown(t *testing.T) {
+	rootCmd := &Command{Use: "app", Short: "My application"}
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Verbose output")
+
+	deployCmd := &Command{
+		Use:     "deploy [env]",
+		Short:   "Deploy the app",
+		Long:    "Deploy the app to the given environment.",
+		Example: "app deploy prod --replicas 3",
+		Run:     func(*Command, []string) {},
+	}
+	deployCmd.Flags().IntP("replicas", "r", 1, "Number of replicas")
+	deployCmd.Flags().String("region", "", "Region, such as us|eu")
+	deployCmd.AddCommand(&Command{Use: "rollback", Short: "Roll back a deploy", Run: func(*Command, []string) {}})
+
+	rootCmd.AddCommand(deployCmd)
+
+	buf := new(bytes.Buffer)
+	if err := deployCmd.GenMarkdown(buf); err != nil {
+		t.Fatalf("GenMarkdown() error = %v", err)
+	}
+	doc := buf.String()
+
+	for _, want := range []string{
+		"## app deploy\n\nDeploy the app\n",
+		"### Synopsis\n\nDeploy the app to the given environment.\n\n```\napp deploy [env]\napp deploy [command]\n```",
+		"### Examples\n\n```\napp deploy prod --replicas 3\n```",
+		"### Options\n\n| Flag | Type | Default | Description |",
+		"| `-r`, `--replicas` | int | `1` | Number of replicas |",
+		"| `--region` | string |  | Region, such as us\\|eu |",
+		"### Options inherited from parent commands",
+		"| `-v`, `--verbose` | bool | `false` | Verbose output |",
+		"* [app](app.md) - My application",
+		"* [app deploy rollback](app_deploy_rollback.md) - Roll back a deploy",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("GenMarkdown() should contain %q, got:\n%s", want, doc)
+		}
+	}
+}
+
+func TestCommand_GenMarkdownSkipsHidden(t *testing.T) {
+	rootCmd := &Command{Use: "app", Short: "My application"}
+	rootCmd.AddCommand(
+		&Command{Use: "deploy", Short: "Deploy the app", Run: func(*Command, []string) {}},
+		&Command{Use: "debug", Short: "Internal tools", Hidden: true, Run: func(*Command, []string) {}},
+	)
+
+	buf := new(bytes.Buffer)
+	if err := rootCmd.GenMarkdown(buf); err != nil {
+		t.Fatalf("GenMarkdown() error = %v", err)
+	}
+
+	if strings.Contains(buf.String(), "debug") {
+		t.Errorf("GenMarkdown() should not link hidden commands, got:\n%s", buf.String())
+	}
+	if strings.Contains(buf.String(), "### Examples") {
+		t.Errorf("GenMarkdown() should omit empty sections, got:\n%s", buf.String())
+	}
+}
+
+func TestCommand_GenMarkdownTree(t *testing.T) {
+	rootCmd := &Command{Use: "app", Short: "My application"}
+	deployCmd := &Command{Use: "deploy", Short: "Deploy the app", Run: func(*Command, []string) {}}
+	deployCmd.AddCommand(&Command{Use: "rollback", Short: "Roll back a deploy", Run: func(*Command, []string) {}})
+	rootCmd.AddCommand(
+		deployCmd,
+		&Command{Use: "debug", Short: "Internal tools", Hidden: true, Run: func(*Command, []string) {}},
+	)
+
+	dir := t.TempDir()
+	if err := rootCmd.GenMarkdownTree(dir); err != nil {
+		t.Fatalf("GenMarkdownTree() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+
+	want := []string{"app.md", "app_deploy.md", "app_deploy_rollback.md"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("GenMarkdownTree() wrote %v, want %v", names, want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "app_deploy_rollback.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "## app deploy rollback\n") {
+		t.Errorf("app_deploy_rollback.md should start with its command path, got:\n%s", data)
+	}
+}
+
+func TestCommand_GenMarkdownTreeMissingDir(t *testing.T) {
+	rootCmd := &Command{Use: "app", Short: "My application"}
+
+	dir := filepath.Join(t.TempDir(), "missing")
+	if err := rootCmd.GenMarkdownTree(dir); err == nil {
+		t.Error("GenMarkdownTree() should fail when the directory does not exist")
+	}
+}
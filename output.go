@@ -0,0 +1,134 @@
+package mamba
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OutputFormat selects how PrintData renders results
+type OutputFormat string
+
+// Supported output formats
+const (
+	OutputText OutputFormat = "text"
+	OutputJSON OutputFormat = "json"
+	OutputYAML OutputFormat = "yaml"
+)
+
+// String returns the format name
+func (f *OutputFormat) String() string {
+	if *f == "" {
+		return string(OutputText)
+	}
+	return string(*f)
+}
+
+// Set parses a format name, so OutputFormat can be used as a flag value
+func (f *OutputFormat) Set(s string) error {
+	switch OutputFormat(strings.ToLower(s)) {
+	case OutputText, OutputJSON, OutputYAML:
+		*f = OutputFormat(strings.ToLower(s))
+		return nil
+	}
+	return fmt.Errorf("invalid output format %q (must be text, json, or yaml)", s)
+}
+
+// Type returns the flag type name shown in help
+func (f *OutputFormat) Type() string {
+	return "format"
+}
+
+// outputFormat returns the format set on the command or its nearest ancestor
+func (c *Command) outputFormat() OutputFormat {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.OutputFormat != "" {
+			return cmd.OutputFormat
+		}
+	}
+	return OutputText
+}
+
+// machineOutput reports whether a structured output format is active, in
+// which case decorative output is suppressed
+func (c *Command) machineOutput() bool {
+	return c.outputFormat() != OutputText
+}
+
+// initDefaultOutputFlag adds a persistent --output/-o flag to a root command
+// that sets OutputFormat. The -o shorthand is only used if it is free.
+func (c *Command) initDefaultOutputFlag() {
+	if c.OutputFormat == "" || c.PersistentFlags().Lookup("output") != nil {
+		return
+	}
+
+	usage := "output format (text, json, yaml)"
+	if c.PersistentFlags().ShorthandLookup("o") == nil && c.LocalFlags().ShorthandLookup("o") == nil {
+		c.PersistentFlags().VarP(&c.OutputFormat, "output", "o", usage)
+	} else {
+		c.PersistentFlags().Var(&c.OutputFormat, "output", usage)
+	}
+}
+
+// PrintData prints v in the active output format: indented JSON, YAML, or a
+// styled key/value listing in text mode
+func (c *Command) PrintData(v interface{}) error {
+	out := c.OutOrStdout()
+
+	switch c.outputFormat() {
+	case OutputJSON:
+		return c.PrintJSON(v)
+	case OutputYAML:
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	default:
+		_, err := fmt.Fprintln(out, c.render(out, c.formatText(v)))
+		return err
+	}
+}
+
+// formatText renders structs and maps as key/value lines, slices as bullets,
+// and anything else with fmt
+func (c *Command) formatText(v interface{}) string {
+	t := c.Theme()
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	var lines []string
+	switch rv.Kind() {
+	case reflect.Struct:
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			if !rt.Field(i).IsExported() {
+				continue
+			}
+			lines = append(lines, t.Flag(rt.Field(i).Name+":")+" "+fmt.Sprint(rv.Field(i).Interface()))
+		}
+	case reflect.Map:
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, k := range keys {
+			lines = append(lines, t.Flag(fmt.Sprint(k.Interface())+":")+" "+fmt.Sprint(rv.MapIndex(k).Interface()))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			lines = append(lines, t.Bullet(fmt.Sprint(rv.Index(i).Interface())))
+		}
+	default:
+		return fmt.Sprint(v)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,145 @@
+package mamba
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/base-go/mamba/pkg/style"
+	"gopkg.in/yaml.v3"
+)
+
+type outputTestRecord struct {
+	Name    string   `json:"name" yaml:"name"`
+	Replica int      `json:"replica" yaml:"replica"`
+	Tags    []string `json:"tags" yaml:"tags"`
+}
+
+func TestCommand_PrintData(t *testing.T) {
+	want := outputTestRecord{Name: "api", Replica: 3, Tags: []string{"web", "prod"}}
+
+	tests := []struct {
+		format    OutputFormat
+		unmarshal func([]byte, interface{}) error
+	}{
+		{OutputJSON, json.Unmarshal},
+		{OutputYAML, yaml.Unmarshal},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			cmd := &Command{Use: "test", OutputFormat: tt.format}
+			cmd.SetOutput(buf)
+
+			if err := cmd.PrintData(want); err != nil {
+				t.Fatalf("PrintData() error = %v", err)
+			}
+
+			var got outputTestRecord
+			if err := tt.unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("output is not valid %s: %v\n%s", tt.format, err, buf.String())
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestCommand_PrintDataText(t *testing.T) {
+	buf := new(bytes.Buffer)
+	cmd := &Command{Use: "test"}
+	cmd.SetOutput(buf)
+
+	if err := cmd.PrintData(outputTestRecord{Name: "api", Replica: 3}); err != nil {
+		t.Fatalf("PrintData() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"Name: api", "Replica: 3"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("text output should contain %q, got: %s", want, output)
+		}
+	}
+}
+
+func TestCommand_PrintDataTextPlain(t *testing.T) {
+	originalProfile := style.ColorProfile()
+	style.SetColorProfile(style.ProfileTrueColor)
+	defer style.SetColorProfile(originalProfile)
+
+	buf := new(bytes.Buffer)
+	withTerminalWriters(t, buf)
+	disabled := false
+	cmd := &Command{Use: "test", EnableColors: &disabled}
+	cmd.SetOutput(buf)
+
+	if err := cmd.PrintData(map[string]string{"a": "b"}); err != nil {
+		t.Fatalf("PrintData() error = %v", err)
+	}
+	if got := buf.String(); got != "a: b\n" {
+		t.Errorf("PrintData() with colors disabled = %q, want %q", got, "a: b\n")
+	}
+
+	// Auto-detection also applies, so redirected output is plain
+	buf.Reset()
+	withTerminalWriters(t)
+	withDetectedColor(t)
+	cmd.EnableColors = nil
+	if err := cmd.PrintData(map[string]string{"a": "b"}); err != nil {
+		t.Fatalf("PrintData() error = %v", err)
+	}
+	if got := buf.String(); got != "a: b\n" {
+		t.Errorf("PrintData() to a redirected writer = %q, want %q", got, "a: b\n")
+	}
+}
+
+func TestCommand_OutputFlag(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd := &Command{Use: "app", OutputFormat: OutputText}
+	subCmd := &Command{
+		Use: "status",
+		RunE: func(cmd *Command, args []string) error {
+			cmd.PrintSuccess("fetched status")
+			return cmd.PrintData(map[string]string{"status": "ok"})
+		},
+	}
+	rootCmd.AddCommand(subCmd)
+	rootCmd.SetOutput(buf)
+
+	if err := rootCmd.execute([]string{"status", "-o", "json"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+
+	var got map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("stdout should be pure JSON, got %q: %v", buf.String(), err)
+	}
+	if got["status"] != "ok" {
+		t.Errorf("status = %q, want %q", got["status"], "ok")
+	}
+}
+
+func TestCommand_OutputFlagInvalid(t *testing.T) {
+	rootCmd := &Command{Use: "app", OutputFormat: OutputText, Run: func(cmd *Command, args []string) {}}
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	if err := rootCmd.execute([]string{"--output", "xml"}); err == nil {
+		t.Error("execute() with an invalid output format should error")
+	}
+}
+
+func TestCommand_NoOutputFlagByDefault(t *testing.T) {
+	rootCmd := &Command{Use: "app", Run: func(cmd *Command, args []string) {}}
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	if err := rootCmd.execute(nil); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if rootCmd.PersistentFlags().Lookup("output") != nil {
+		t.Error("--output should only be registered when OutputFormat is set")
+	}
+}
@@ -0,0 +1,55 @@
+package mamba
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ExecuteCommands runs the Run functions of the named subcommands
+// concurrently, for "run all" style commands. Each child sees a context
+// derived from ctx that is canceled as soon as one of them fails, so the
+// others can stop early. The returned error joins the error of every child
+// that failed, prefixed with its name. Names may also be aliases; a name
+// given twice runs once.
+func (c *Command) ExecuteCommands(ctx context.Context, names ...string) error {
+	var cmds []*Command
+	seen := make(map[*Command]bool)
+	for _, name := range names {
+		cmd := c.findSubCommand(name)
+		if cmd == nil {
+			return &UnknownCommandError{Name: name, Command: c.CommandPath()}
+		}
+		if !seen[cmd] {
+			seen[cmd] = true
+			cmds = append(cmds, cmd)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The derived context is canceled on return, so each child gets its
+	// previous context back once all have finished
+	errs := make([]error, len(cmds))
+	var wg sync.WaitGroup
+	for i, cmd := range cmds {
+		previous := cmd.ctx
+		defer func() { cmd.ctx = previous }()
+		cmd.ctx = ctx
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := cmd.runHook(cmd.RunContextE, cmd.RunE, cmd.Run, nil); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", cmd.Name(), err)
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
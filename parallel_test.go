@@ -0,0 +1,78 @@
+package mamba
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCommand_ExecuteCommands(t *testing.T) {
+	var ran atomic.Int32
+	root := &Command{Use: "app"}
+	root.AddCommand(
+		&Command{Use: "lint", Run: func(cmd *Command, args []string) { ran.Add(1) }},
+		&Command{Use: "test", Aliases: []string{"t"}, RunE: func(cmd *Command, args []string) error {
+			ran.Add(1)
+			return errors.New("2 tests failed")
+		}},
+	)
+
+	err := root.ExecuteCommands(context.Background(), "lint", "t")
+	if err == nil {
+		t.Fatal("ExecuteCommands() error = nil, want the failing child's error")
+	}
+	if !strings.Contains(err.Error(), "test: 2 tests failed") {
+		t.Errorf("ExecuteCommands() error = %q, want it to mention the failing child", err)
+	}
+	if got := ran.Load(); got != 2 {
+		t.Errorf("ran %d children, want 2", got)
+	}
+
+	if err := root.ExecuteCommands(context.Background(), "lint"); err != nil {
+		t.Errorf("ExecuteCommands(lint) error = %v, want nil", err)
+	}
+}
+
+func TestCommand_ExecuteCommandsCancel(t *testing.T) {
+	root := &Command{Use: "app"}
+	root.AddCommand(
+		&Command{Use: "fail", RunE: func(cmd *Command, args []string) error {
+			return errors.New("boom")
+		}},
+		&Command{Use: "wait", RunContextE: func(ctx context.Context, cmd *Command, args []string) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	)
+
+	// The waiting child only returns once its sibling's failure cancels it
+	err := root.ExecuteCommands(context.Background(), "fail", "wait")
+	if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "fail: boom") {
+		t.Errorf("ExecuteCommands() error = %v, want both the failure and the cancellation", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := root.ExecuteCommands(ctx, "wait"); !errors.Is(err, context.Canceled) {
+		t.Errorf("ExecuteCommands() with a canceled context error = %v, want context.Canceled", err)
+	}
+
+	if err := root.ExecuteCommands(context.Background(), "nope"); err == nil || !strings.Contains(err.Error(), `unknown command "nope"`) {
+		t.Errorf("ExecuteCommands(nope) error = %v, want unknown command", err)
+	}
+}
+
+func TestCommand_ExecuteCommandsRestoresContext(t *testing.T) {
+	root := &Command{Use: "app"}
+	lint := &Command{Use: "lint", Run: func(cmd *Command, args []string) {}}
+	root.AddCommand(lint)
+
+	if err := root.ExecuteCommands(context.Background(), "lint"); err != nil {
+		t.Fatalf("ExecuteCommands() error = %v", err)
+	}
+	if err := lint.Context().Err(); err != nil {
+		t.Errorf("Context().Err() after ExecuteCommands = %v, want nil", err)
+	}
+}
@@ -1,11 +1,55 @@
 package interactive
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/huh"
 )
 
+// promptInput and promptOutput replace the terminal when set, so prompts can
+// be driven from tests
+var (
+	promptInput  io.Reader
+	promptOutput io.Writer
+)
+
+// newForm creates a form wired to the prompt input and output
+func newForm(groups ...*huh.Group) *huh.Form {
+	form := huh.NewForm(groups...)
+	if promptInput != nil {
+		form = form.WithInput(promptInput)
+	}
+	if promptOutput != nil {
+		form = form.WithOutput(promptOutput)
+	}
+	return form
+}
+
+// run runs a single field
+func run(field huh.Field) error {
+	return newForm(huh.NewGroup(field)).WithShowHelp(false).Run()
+}
+
+// validator combines the required check with a custom validation function
+func validator(required bool, validate func(string) error) func(string) error {
+	if !required && validate == nil {
+		return nil
+	}
+	return func(s string) error {
+		if required && s == "" {
+			return fmt.Errorf("this field is required")
+		}
+		if validate != nil {
+			return validate(s)
+		}
+		return nil
+	}
+}
+
 // Prompt represents a simple text input prompt
 type Prompt struct {
 	Title       string
@@ -13,6 +57,7 @@ type Prompt struct {
 	Placeholder string
 	Value       *string
 	Required    bool
+	Validate    func(string) error
 }
 
 // Run executes the prompt
@@ -23,16 +68,11 @@ func (p *Prompt) Run() error {
 		Placeholder(p.Placeholder).
 		Value(p.Value)
 
-	if p.Required {
-		input = input.Validate(func(s string) error {
-			if s == "" {
-				return fmt.Errorf("this field is required")
-			}
-			return nil
-		})
+	if validate := validator(p.Required, p.Validate); validate != nil {
+		input = input.Validate(validate)
 	}
 
-	return input.Run()
+	return run(input)
 }
 
 // Confirm represents a yes/no confirmation prompt
@@ -58,7 +98,35 @@ func (c *Confirm) Run() error {
 		confirm = confirm.Negative(c.Negative)
 	}
 
-	return confirm.Run()
+	return run(confirm)
+}
+
+// Password represents a text input prompt that hides what is typed
+type Password struct {
+	Title       string
+	Description string
+	Value       *string
+	Required    bool
+}
+
+// Run executes the password prompt
+func (p *Password) Run() error {
+	return run(p.input())
+}
+
+// input builds the huh field for the password prompt
+func (p *Password) input() *huh.Input {
+	input := huh.NewInput().
+		Title(p.Title).
+		Description(p.Description).
+		EchoMode(huh.EchoModePassword).
+		Value(p.Value)
+
+	if validate := validator(p.Required, nil); validate != nil {
+		input = input.Validate(validate)
+	}
+
+	return input
 }
 
 // Select represents a selection prompt
@@ -67,27 +135,74 @@ type Select struct {
 	Description string
 	Options     []SelectOption
 	Value       *string
+	Validate    func(string) error
+
+	// Default is the key of the option highlighted at the start, used when
+	// Value is empty
+	Default string
+
+	// Filterable starts the prompt with type-to-filter enabled. It is
+	// always enabled for more than filterThreshold options.
+	Filterable bool
+}
+
+// filterThreshold is the number of options above which selects are
+// filterable by default
+const filterThreshold = 15
+
+// filtering reports whether a select over n options starts filtering
+func filtering(filterable bool, n int) bool {
+	return filterable || n > filterThreshold
 }
 
 // SelectOption represents an option in a select prompt
 type SelectOption struct {
 	Key   string
 	Value string
+
+	// Description is shown beneath the option
+	Description string
+}
+
+// huhOptions converts options to huh options, labelled by Value with the
+// Description on the following line
+func huhOptions(opts []SelectOption) []huh.Option[string] {
+	options := make([]huh.Option[string], len(opts))
+	for i, opt := range opts {
+		label := opt.Value
+		if opt.Description != "" {
+			label += "\n  " + opt.Description
+		}
+		options[i] = huh.NewOption(label, opt.Key)
+	}
+	return options
 }
 
 // Run executes the select prompt
 func (s *Select) Run() error {
-	options := make([]huh.Option[string], len(s.Options))
-	for i, opt := range s.Options {
-		options[i] = huh.NewOption(opt.Value, opt.Key)
+	options := huhOptions(s.Options)
+
+	// huh highlights the option matching the current value
+	if s.Default != "" && s.Value != nil && *s.Value == "" {
+		*s.Value = s.Default
 	}
 
-	return huh.NewSelect[string]().
+	sel := huh.NewSelect[string]().
 		Title(s.Title).
 		Description(s.Description).
 		Options(options...).
-		Value(s.Value).
-		Run()
+		Value(s.Value)
+
+	if filtering(s.Filterable, len(s.Options)) {
+		sel = sel.Filtering(true)
+	}
+
+	// Validation runs against the key of the chosen option
+	if s.Validate != nil {
+		sel = sel.Validate(s.Validate)
+	}
+
+	return run(sel)
 }
 
 // MultiSelect represents a multi-selection prompt
@@ -97,14 +212,15 @@ type MultiSelect struct {
 	Options     []SelectOption
 	Value       *[]string
 	Limit       int
+
+	// Filterable starts the prompt with type-to-filter enabled. It is
+	// always enabled for more than filterThreshold options.
+	Filterable bool
 }
 
 // Run executes the multi-select prompt
 func (m *MultiSelect) Run() error {
-	options := make([]huh.Option[string], len(m.Options))
-	for i, opt := range m.Options {
-		options[i] = huh.NewOption(opt.Value, opt.Key)
-	}
+	options := huhOptions(m.Options)
 
 	multiSelect := huh.NewMultiSelect[string]().
 		Title(m.Title).
@@ -116,7 +232,11 @@ func (m *MultiSelect) Run() error {
 		multiSelect = multiSelect.Limit(m.Limit)
 	}
 
-	return multiSelect.Run()
+	if filtering(m.Filterable, len(m.Options)) {
+		multiSelect = multiSelect.Filtering(true)
+	}
+
+	return run(multiSelect)
 }
 
 // Text represents a multi-line text input prompt
@@ -127,6 +247,7 @@ type Text struct {
 	Value       *string
 	CharLimit   int
 	Required    bool
+	Validate    func(string) error
 }
 
 // Run executes the text prompt
@@ -141,16 +262,11 @@ func (t *Text) Run() error {
 		text = text.CharLimit(t.CharLimit)
 	}
 
-	if t.Required {
-		text = text.Validate(func(s string) error {
-			if s == "" {
-				return fmt.Errorf("this field is required")
-			}
-			return nil
-		})
+	if validate := validator(t.Required, t.Validate); validate != nil {
+		text = text.Validate(validate)
 	}
 
-	return text.Run()
+	return run(text)
 }
 
 // Form represents a group of prompts
@@ -177,7 +293,7 @@ func (f *Form) Run() error {
 		groups[i] = group
 	}
 
-	return huh.NewForm(groups...).Run()
+	return newForm(groups...).Run()
 }
 
 // Helper functions for common prompts
@@ -195,6 +311,92 @@ func AskString(title, placeholder string) (string, error) {
 	return value, err
 }
 
+// AskStringValidated prompts for a string input that must pass validate
+func AskStringValidated(title, placeholder string, validate func(string) error) (string, error) {
+	var value string
+	p := &Prompt{
+		Title:       title,
+		Placeholder: placeholder,
+		Value:       &value,
+		Required:    true,
+		Validate:    validate,
+	}
+	err := p.Run()
+	return value, err
+}
+
+// AskWithRetry prompts for a string and passes it to check, which may be slow,
+// such as a call to a server. When check fails, its error is shown and the
+// prompt is asked again, up to attempts times in total. The last error is
+// returned if every attempt fails.
+func AskWithRetry(title, placeholder string, attempts int, check func(string) error) (string, error) {
+	var lastErr error
+	for i := 0; i < max(attempts, 1); i++ {
+		var value string
+		p := &Prompt{
+			Title:       title,
+			Placeholder: placeholder,
+			Value:       &value,
+			Required:    true,
+		}
+		if lastErr != nil {
+			p.Description = "✗ " + lastErr.Error()
+		}
+		if err := p.Run(); err != nil {
+			return "", err
+		}
+
+		if lastErr = check(value); lastErr == nil {
+			return value, nil
+		}
+	}
+	return "", lastErr
+}
+
+// AskInt prompts for a whole number, re-prompting until the input is valid
+func AskInt(title, placeholder string) (int, error) {
+	return askInt(title, placeholder, func(n int) error { return nil }, "please enter a whole number")
+}
+
+// AskIntRange prompts for a whole number between min and max inclusive
+func AskIntRange(title string, min, max int) (int, error) {
+	msg := fmt.Sprintf("please enter a whole number between %d and %d", min, max)
+	return askInt(title, fmt.Sprintf("%d-%d", min, max), func(n int) error {
+		if n < min || n > max {
+			return errors.New(msg)
+		}
+		return nil
+	}, msg)
+}
+
+func askInt(title, placeholder string, check func(int) error, msg string) (int, error) {
+	value, err := AskStringValidated(title, placeholder, func(s string) error {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return errors.New(msg)
+		}
+		return check(n)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(value))
+}
+
+// AskFloat prompts for a number, re-prompting until the input is valid
+func AskFloat(title, placeholder string) (float64, error) {
+	value, err := AskStringValidated(title, placeholder, func(s string) error {
+		if _, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err != nil {
+			return errors.New("please enter a number")
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(strings.TrimSpace(value), 64)
+}
+
 // AskConfirm prompts for a yes/no confirmation
 func AskConfirm(title string, defaultValue bool) (bool, error) {
 	value := defaultValue
@@ -206,6 +408,85 @@ func AskConfirm(title string, defaultValue bool) (bool, error) {
 	return value, err
 }
 
+// ConfirmResult is the answer to AskConfirmAll
+type ConfirmResult int
+
+const (
+	// ConfirmYes applies to the current item only
+	ConfirmYes ConfirmResult = iota
+	// ConfirmNo skips the current item only
+	ConfirmNo
+	// ConfirmYesToAll applies to the current and all remaining items
+	ConfirmYesToAll
+	// ConfirmNoToAll skips the current and all remaining items
+	ConfirmNoToAll
+)
+
+// confirmAllOptions are the choices offered by AskConfirmAll, keyed by the
+// result they map to
+var confirmAllOptions = []struct {
+	result ConfirmResult
+	option SelectOption
+}{
+	{ConfirmYes, SelectOption{Key: "yes", Value: "Yes"}},
+	{ConfirmNo, SelectOption{Key: "no", Value: "No"}},
+	{ConfirmYesToAll, SelectOption{Key: "all", Value: "Yes to all"}},
+	{ConfirmNoToAll, SelectOption{Key: "none", Value: "No to all"}},
+}
+
+// String returns the label shown for the result
+func (r ConfirmResult) String() string {
+	for _, o := range confirmAllOptions {
+		if o.result == r {
+			return o.option.Value
+		}
+	}
+	return "ConfirmResult(" + strconv.Itoa(int(r)) + ")"
+}
+
+// All reports whether the answer applies to all remaining items, so the
+// caller should stop prompting
+func (r ConfirmResult) All() bool {
+	return r == ConfirmYesToAll || r == ConfirmNoToAll
+}
+
+// Yes reports whether the current item should be applied
+func (r ConfirmResult) Yes() bool {
+	return r == ConfirmYes || r == ConfirmYesToAll
+}
+
+// AskConfirmAll prompts for yes, no, yes to all, or no to all, for batch
+// operations that confirm each item until an "all" answer is given:
+//
+//	answer := interactive.ConfirmNo
+//	for _, f := range files {
+//		if !answer.All() {
+//			if answer, err = interactive.AskConfirmAll("Delete " + f + "?"); err != nil {
+//				return err
+//			}
+//		}
+//		if answer.Yes() {
+//			os.Remove(f)
+//		}
+//	}
+func AskConfirmAll(title string) (ConfirmResult, error) {
+	options := make([]SelectOption, len(confirmAllOptions))
+	for i, o := range confirmAllOptions {
+		options[i] = o.option
+	}
+
+	key, err := AskSelect(title, options)
+	if err != nil {
+		return ConfirmNo, err
+	}
+	for _, o := range confirmAllOptions {
+		if o.option.Key == key {
+			return o.result, nil
+		}
+	}
+	return ConfirmNo, nil
+}
+
 // AskSelect prompts for a selection from a list
 func AskSelect(title string, options []SelectOption) (string, error) {
 	var value string
@@ -218,6 +499,83 @@ func AskSelect(title string, options []SelectOption) (string, error) {
 	return value, err
 }
 
+// AskSelectOption prompts for a selection from a list like AskSelect, but
+// returns the whole chosen option rather than just its key
+func AskSelectOption(title string, options []SelectOption) (SelectOption, error) {
+	key, err := AskSelect(title, options)
+	if err != nil {
+		return SelectOption{}, err
+	}
+	for _, opt := range options {
+		if opt.Key == key {
+			return opt, nil
+		}
+	}
+	return SelectOption{}, nil
+}
+
+// Choice is an option for SelectOf that carries a typed payload
+type Choice[T any] struct {
+	Label string
+	Value T
+
+	// Description is shown beneath the option
+	Description string
+}
+
+// SelectOf prompts for one of choices, labelled by their Label, and returns
+// the Value of the chosen one:
+//
+//	region, err := interactive.SelectOf("Region", []interactive.Choice[Region]{
+//		{Label: "Frankfurt", Value: Region{Code: "eu-central-1"}},
+//		{Label: "Virginia", Value: Region{Code: "us-east-1"}},
+//	})
+func SelectOf[T any](title string, choices []Choice[T]) (T, error) {
+	options := make([]SelectOption, len(choices))
+	for i, c := range choices {
+		options[i] = SelectOption{Key: strconv.Itoa(i), Value: c.Label, Description: c.Description}
+	}
+
+	var zero T
+	key, err := AskSelect(title, options)
+	if err != nil {
+		return zero, err
+	}
+	i, err := strconv.Atoi(key)
+	if err != nil || i < 0 || i >= len(choices) {
+		return zero, nil
+	}
+	return choices[i].Value, nil
+}
+
+// AskSelectFilter prompts for a selection from a long list, filtering the
+// options as the user types. It returns the key of the chosen option.
+func AskSelectFilter(title string, options []SelectOption) (string, error) {
+	var value string
+	s := &Select{
+		Title:      title,
+		Options:    options,
+		Value:      &value,
+		Filterable: true,
+	}
+	err := s.Run()
+	return value, err
+}
+
+// AskSelectDefault prompts for a selection from a list with the option keyed
+// defaultKey highlighted, so accepting without moving returns it
+func AskSelectDefault(title string, options []SelectOption, defaultKey string) (string, error) {
+	var value string
+	s := &Select{
+		Title:   title,
+		Options: options,
+		Value:   &value,
+		Default: defaultKey,
+	}
+	err := s.Run()
+	return value, err
+}
+
 // AskMultiSelect prompts for multiple selections from a list
 func AskMultiSelect(title string, options []SelectOption, limit int) ([]string, error) {
 	var value []string
@@ -230,3 +588,34 @@ func AskMultiSelect(title string, options []SelectOption, limit int) ([]string,
 	err := m.Run()
 	return value, err
 }
+
+// AskPassword prompts for a password without echoing it
+func AskPassword(title string) (string, error) {
+	var value string
+	p := &Password{
+		Title:    title,
+		Value:    &value,
+		Required: true,
+	}
+	err := p.Run()
+	return value, err
+}
+
+// ErrPasswordMismatch is returned when the confirmation does not match the password
+var ErrPasswordMismatch = errors.New("passwords do not match")
+
+// AskPasswordConfirm prompts for a password twice and returns an error if the
+// entries differ
+func AskPasswordConfirm(title string) (string, error) {
+	var value, confirm string
+	p := &Password{Title: title, Value: &value, Required: true}
+	c := &Password{Title: "Confirm " + title, Value: &confirm, Required: true}
+
+	if err := newForm(huh.NewGroup(p.input(), c.input())).WithShowHelp(false).Run(); err != nil {
+		return "", err
+	}
+	if value != confirm {
+		return "", ErrPasswordMismatch
+	}
+	return value, nil
+}
@@ -0,0 +1,413 @@
+package interactive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/huh"
+)
+
+// pacedReader returns one line of keystrokes per read, pausing between lines
+// so the form can move to the next field before more input arrives
+type pacedReader struct {
+	lines []string
+	read  int
+}
+
+func (r *pacedReader) Read(p []byte) (int, error) {
+	if len(r.lines) == 0 {
+		return 0, io.EOF
+	}
+	if r.read > 0 {
+		time.Sleep(50 * time.Millisecond)
+	}
+	n := copy(p, r.lines[0])
+	r.lines = r.lines[1:]
+	r.read++
+	return n, nil
+}
+
+// withInput feeds keystrokes to prompts for the duration of a test
+func withInput(t *testing.T, input string) {
+	t.Helper()
+	lines := strings.SplitAfter(input, "\r")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	promptInput = &pacedReader{lines: lines}
+	promptOutput = io.Discard
+	t.Cleanup(func() {
+		promptInput = nil
+		promptOutput = nil
+	})
+}
+
+func TestAskPassword(t *testing.T) {
+	withInput(t, "s3cret\r")
+
+	got, err := AskPassword("Password")
+	if err != nil {
+		t.Fatalf("AskPassword() error = %v", err)
+	}
+	if got != "s3cret" {
+		t.Errorf("AskPassword() = %q, want %q", got, "s3cret")
+	}
+}
+
+func TestAskPasswordConfirm(t *testing.T) {
+	withInput(t, "s3cret\rs3cret\r")
+
+	got, err := AskPasswordConfirm("Password")
+	if err != nil {
+		t.Fatalf("AskPasswordConfirm() error = %v", err)
+	}
+	if got != "s3cret" {
+		t.Errorf("AskPasswordConfirm() = %q, want %q", got, "s3cret")
+	}
+}
+
+func TestAskPasswordConfirmMismatch(t *testing.T) {
+	withInput(t, "s3cret\rother\r")
+
+	got, err := AskPasswordConfirm("Password")
+	if !errors.Is(err, ErrPasswordMismatch) {
+		t.Fatalf("AskPasswordConfirm() error = %v, want %v", err, ErrPasswordMismatch)
+	}
+	if got != "" {
+		t.Errorf("AskPasswordConfirm() should not return a value on mismatch, got %q", got)
+	}
+}
+
+func TestAskStringValidated(t *testing.T) {
+	// The first entry is rejected, erased, and replaced with a valid one
+	withInput(t, "bob\r\x7f\x7f\x7fbob@example.com\r")
+
+	var rejected []string
+	got, err := AskStringValidated("Email", "", func(s string) error {
+		if !strings.Contains(s, "@") {
+			rejected = append(rejected, s)
+			return errors.New("must be an email address")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("AskStringValidated() error = %v", err)
+	}
+	if got != "bob@example.com" {
+		t.Errorf("AskStringValidated() = %q, want %q", got, "bob@example.com")
+	}
+	if len(rejected) == 0 || rejected[0] != "bob" {
+		t.Errorf("invalid input should be rejected, rejected = %v", rejected)
+	}
+}
+
+func TestSelectValidate(t *testing.T) {
+	// Submitting the first option is rejected, so move down and submit again
+	withInput(t, "\rj\r")
+
+	var value string
+	s := &Select{
+		Title: "Region",
+		Options: []SelectOption{
+			{Key: "us-east", Value: "US East"},
+			{Key: "eu-west", Value: "EU West"},
+		},
+		Value: &value,
+		Validate: func(key string) error {
+			if key == "us-east" {
+				return errors.New("us-east is full")
+			}
+			return nil
+		},
+	}
+
+	if err := s.Run(); err != nil {
+		t.Fatalf("Select.Run() error = %v", err)
+	}
+	if value != "eu-west" {
+		t.Errorf("Select value = %q, want %q", value, "eu-west")
+	}
+}
+
+func TestAskInt(t *testing.T) {
+	withInput(t, "42\r")
+
+	got, err := AskInt("Count", "")
+	if err != nil {
+		t.Fatalf("AskInt() error = %v", err)
+	}
+	if got != 42 {
+		t.Errorf("AskInt() = %d, want 42", got)
+	}
+}
+
+func TestAskIntRejectsNonNumeric(t *testing.T) {
+	// "abc" and "1.5" are rejected before "7" is accepted
+	withInput(t, "abc\r\x7f\x7f\x7f1.5\r\x7f\x7f\x7f7\r")
+
+	got, err := AskInt("Count", "")
+	if err != nil {
+		t.Fatalf("AskInt() error = %v", err)
+	}
+	if got != 7 {
+		t.Errorf("AskInt() = %d, want 7", got)
+	}
+}
+
+func TestAskIntRange(t *testing.T) {
+	// 11 is out of range and rejected before 3 is accepted
+	withInput(t, "11\r\x7f\x7f3\r")
+
+	got, err := AskIntRange("Replicas", 1, 10)
+	if err != nil {
+		t.Fatalf("AskIntRange() error = %v", err)
+	}
+	if got != 3 {
+		t.Errorf("AskIntRange() = %d, want 3", got)
+	}
+}
+
+func TestAskFloat(t *testing.T) {
+	withInput(t, "x\r\x7f2.5\r")
+
+	got, err := AskFloat("Ratio", "")
+	if err != nil {
+		t.Fatalf("AskFloat() error = %v", err)
+	}
+	if got != 2.5 {
+		t.Errorf("AskFloat() = %v, want 2.5", got)
+	}
+}
+
+func TestAskConfirmTimeout(t *testing.T) {
+	withInput(t, "")
+
+	start := time.Now()
+	got, err := AskConfirmTimeout("Continue?", true, 100*time.Millisecond)
+
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("AskConfirmTimeout() error = %v, want ErrTimeout", err)
+	}
+	if !got {
+		t.Error("AskConfirmTimeout() should return the default on timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("AskConfirmTimeout() took %v, want about 100ms", elapsed)
+	}
+}
+
+func TestAskConfirmTimeoutAnswered(t *testing.T) {
+	withInput(t, "n\r")
+
+	got, err := AskConfirmTimeout("Continue?", true, 5*time.Second)
+	if err != nil {
+		t.Fatalf("AskConfirmTimeout() error = %v", err)
+	}
+	if got {
+		t.Error("AskConfirmTimeout() should return the answer when one is given")
+	}
+}
+
+func TestCountdownTitle(t *testing.T) {
+	confirm := huh.NewConfirm()
+	m := &countdownModel{confirm: confirm, title: "Continue?", answer: "yes", deadline: time.Now().Add(9500 * time.Millisecond)}
+
+	m.updateTitle()
+
+	if view := confirm.View(); !strings.Contains(view, "Continue? [auto-yes in 10s]") {
+		t.Errorf("title should count down in whole seconds, got: %q", view)
+	}
+}
+
+func TestAskSelectDefault(t *testing.T) {
+	withInput(t, "\r")
+
+	options := []SelectOption{
+		{Key: "dev", Value: "Development"},
+		{Key: "staging", Value: "Staging"},
+		{Key: "prod", Value: "Production"},
+	}
+	got, err := AskSelectDefault("Environment", options, "staging")
+	if err != nil {
+		t.Fatalf("AskSelectDefault() error = %v", err)
+	}
+	if got != "staging" {
+		t.Errorf("AskSelectDefault() = %q, want the default %q", got, "staging")
+	}
+}
+
+func TestAskSelectOption(t *testing.T) {
+	withInput(t, "\x1b[B\r")
+	options := []SelectOption{
+		{Key: "dev", Value: "Development"},
+		{Key: "staging", Value: "Staging", Description: "Pre-production"},
+		{Key: "prod", Value: "Production"},
+	}
+
+	got, err := AskSelectOption("Environment", options)
+	if err != nil {
+		t.Fatalf("AskSelectOption() error = %v", err)
+	}
+	if got != options[1] {
+		t.Errorf("AskSelectOption() = %+v, want %+v", got, options[1])
+	}
+}
+
+func TestSelectOf(t *testing.T) {
+	type region struct {
+		code  string
+		zones int
+	}
+	withInput(t, "\x1b[B\r")
+
+	got, err := SelectOf("Region", []Choice[region]{
+		{Label: "Frankfurt", Value: region{"eu-central-1", 3}},
+		{Label: "Virginia", Value: region{"us-east-1", 6}, Description: "Lowest latency to the US"},
+	})
+	if err != nil {
+		t.Fatalf("SelectOf() error = %v", err)
+	}
+	if want := (region{"us-east-1", 6}); got != want {
+		t.Errorf("SelectOf() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAskConfirmAll(t *testing.T) {
+	const down = "\x1b[B"
+	tests := []struct {
+		keys     string
+		want     ConfirmResult
+		yes      bool
+		applyAll bool
+	}{
+		{"\r", ConfirmYes, true, false},
+		{down + "\r", ConfirmNo, false, false},
+		{strings.Repeat(down, 2) + "\r", ConfirmYesToAll, true, true},
+		{strings.Repeat(down, 3) + "\r", ConfirmNoToAll, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want.String(), func(t *testing.T) {
+			withInput(t, tt.keys)
+
+			got, err := AskConfirmAll("Delete file?")
+			if err != nil {
+				t.Fatalf("AskConfirmAll() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("AskConfirmAll() = %v, want %v", got, tt.want)
+			}
+			if got.Yes() != tt.yes || got.All() != tt.applyAll {
+				t.Errorf("%v: Yes() = %v, All() = %v, want %v, %v", got, got.Yes(), got.All(), tt.yes, tt.applyAll)
+			}
+		})
+	}
+}
+
+func TestAskSelectFilter(t *testing.T) {
+	withInput(t, "stag\r")
+	options := []SelectOption{
+		{Key: "dev", Value: "Development"},
+		{Key: "staging", Value: "Staging"},
+		{Key: "prod", Value: "Production"},
+	}
+
+	got, err := AskSelectFilter("Environment", options)
+	if err != nil {
+		t.Fatalf("AskSelectFilter() error = %v", err)
+	}
+	if got != "staging" {
+		t.Errorf("AskSelectFilter() = %q, want the key of the filtered option %q", got, "staging")
+	}
+}
+
+func TestSelectFilterableByDefaultForLongLists(t *testing.T) {
+	if filtering(false, filterThreshold) {
+		t.Errorf("a select with %d options should not filter by default", filterThreshold)
+	}
+	if !filtering(false, filterThreshold+1) {
+		t.Errorf("a select with %d options should filter by default", filterThreshold+1)
+	}
+
+	withInput(t, "region-17\r")
+	var options []SelectOption
+	for i := 0; i <= filterThreshold+5; i++ {
+		key := fmt.Sprintf("region-%d", i)
+		options = append(options, SelectOption{Key: key, Value: key})
+	}
+
+	got, err := AskSelect("Region", options)
+	if err != nil {
+		t.Fatalf("AskSelect() error = %v", err)
+	}
+	if got != "region-17" {
+		t.Errorf("AskSelect() = %q, want %q", got, "region-17")
+	}
+}
+
+func TestSelectOptionDescriptions(t *testing.T) {
+	options := huhOptions([]SelectOption{
+		{Key: "dev", Value: "Development", Description: "Local cluster"},
+		{Key: "prod", Value: "Production"},
+	})
+
+	if options[0].Key != "Development\n  Local cluster" || options[0].Value != "dev" {
+		t.Errorf("option with description = %+v, want the description beneath the label", options[0])
+	}
+	if options[1].Key != "Production" || options[1].Value != "prod" {
+		t.Errorf("option without description = %+v", options[1])
+	}
+}
+
+// feedNext gives the next prompt its own reader, since a finished prompt may
+// still be reading from the previous one
+func feedNext(input string) {
+	promptInput = &pacedReader{lines: []string{input}}
+}
+
+func TestAskWithRetry(t *testing.T) {
+	withInput(t, "taken\r")
+	next := []string{"used\r", "free\r"}
+
+	var checked []string
+	got, err := AskWithRetry("Username", "", 3, func(s string) error {
+		checked = append(checked, s)
+		if s != "free" {
+			feedNext(next[0])
+			next = next[1:]
+			return errors.New("username is taken")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("AskWithRetry() error = %v", err)
+	}
+	if got != "free" {
+		t.Errorf("AskWithRetry() = %q, want %q", got, "free")
+	}
+	if strings.Join(checked, ",") != "taken,used,free" {
+		t.Errorf("check was called with %v, want [taken used free]", checked)
+	}
+}
+
+func TestAskWithRetryExhausted(t *testing.T) {
+	withInput(t, "taken\r")
+
+	errTaken := errors.New("username is taken")
+	calls := 0
+	got, err := AskWithRetry("Username", "", 2, func(s string) error {
+		calls++
+		feedNext("used\r")
+		return errTaken
+	})
+	if !errors.Is(err, errTaken) {
+		t.Fatalf("AskWithRetry() error = %v, want %v", err, errTaken)
+	}
+	if got != "" || calls != 2 {
+		t.Errorf("AskWithRetry() = %q after %d checks, want no value after 2", got, calls)
+	}
+}
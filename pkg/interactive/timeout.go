@@ -0,0 +1,113 @@
+package interactive
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+// ErrTimeout is returned with the default value when a timed prompt is not
+// answered in time
+var ErrTimeout = errors.New("prompt timed out")
+
+// AskConfirmTimeout prompts for a yes/no confirmation that accepts
+// defaultValue after timeout without input. The remaining time counts down
+// in the title, like "Continue? [auto-yes in 10s]". When the timeout is
+// reached it returns defaultValue and ErrTimeout.
+func AskConfirmTimeout(title string, defaultValue bool, timeout time.Duration) (bool, error) {
+	value := defaultValue
+	confirm := huh.NewConfirm().Value(&value)
+
+	m := &countdownModel{
+		form:     newForm(huh.NewGroup(confirm)).WithShowHelp(false),
+		confirm:  confirm,
+		title:    title,
+		answer:   "no",
+		deadline: time.Now().Add(timeout),
+	}
+	if defaultValue {
+		m.answer = "yes"
+	}
+	m.form.SubmitCmd = tea.Quit
+	m.form.CancelCmd = tea.Quit
+	m.updateTitle()
+
+	var opts []tea.ProgramOption
+	if promptInput != nil {
+		opts = append(opts, tea.WithInput(promptInput))
+	}
+	if promptOutput != nil {
+		opts = append(opts, tea.WithOutput(promptOutput))
+	}
+	if _, err := tea.NewProgram(m, opts...).Run(); err != nil {
+		return defaultValue, err
+	}
+
+	switch {
+	case m.timedOut:
+		return defaultValue, ErrTimeout
+	case m.form.State == huh.StateAborted:
+		return defaultValue, huh.ErrUserAborted
+	}
+	return value, nil
+}
+
+// countdownTickMsg updates the countdown in the title
+type countdownTickMsg struct{}
+
+// countdownModel runs a confirm form until it is answered or the deadline
+// passes
+type countdownModel struct {
+	form     *huh.Form
+	confirm  *huh.Confirm
+	title    string
+	answer   string
+	deadline time.Time
+	timedOut bool
+}
+
+func (m *countdownModel) Init() tea.Cmd {
+	return tea.Batch(m.form.Init(), m.tick())
+}
+
+// tick schedules the next countdown update, at most a second away
+func (m *countdownModel) tick() tea.Cmd {
+	return tea.Tick(min(time.Until(m.deadline), time.Second), func(time.Time) tea.Msg {
+		return countdownTickMsg{}
+	})
+}
+
+func (m *countdownModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	if _, ok := msg.(countdownTickMsg); ok {
+		if !time.Now().Before(m.deadline) {
+			m.timedOut = true
+			return m, tea.Quit
+		}
+		m.updateTitle()
+		cmds = append(cmds, m.tick())
+	}
+
+	form, cmd := m.form.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.form = f
+	}
+	return m, tea.Batch(append(cmds, cmd)...)
+}
+
+func (m *countdownModel) View() string {
+	if m.timedOut || m.form.State != huh.StateNormal {
+		return ""
+	}
+	return m.form.View()
+}
+
+// updateTitle shows the whole seconds left before the default is taken
+func (m *countdownModel) updateTitle() {
+	secs := int(math.Ceil(time.Until(m.deadline).Seconds()))
+	m.confirm.Title(fmt.Sprintf("%s [auto-%s in %ds]", m.title, m.answer, secs))
+}
@@ -0,0 +1,77 @@
+package interactive
+
+import "github.com/charmbracelet/huh"
+
+// WizardStep is one prompt of a Wizard
+type WizardStep struct {
+	// Name is the key the step's answer is stored under
+	Name string
+
+	// Field is the prompt shown for the step, such as huh.NewInput()
+	Field huh.Field
+
+	// ShouldRun skips the step when it returns false. It receives the
+	// answers to the earlier steps that ran.
+	ShouldRun func(state map[string]interface{}) bool
+}
+
+// Wizard runs a guided sequence of prompts, one step per screen. Pressing
+// shift+tab returns to the previous step that ran, keeping its answer so it
+// can be edited.
+//
+// Example:
+//
+//	w := &interactive.Wizard{Steps: []interactive.WizardStep{
+//		{Name: "env", Field: huh.NewSelect[string]().Title("Environment").Options(huh.NewOptions("dev", "prod")...)},
+//		{Name: "approver", Field: huh.NewInput().Title("Approver"), ShouldRun: func(state map[string]interface{}) bool {
+//			return state["env"] == "prod"
+//		}},
+//	}}
+//	answers, err := w.Run()
+type Wizard struct {
+	Steps []WizardStep
+}
+
+// Run executes the steps in order and returns the answers keyed by step
+// name. Skipped steps are left out.
+func (w *Wizard) Run() (map[string]interface{}, error) {
+	groups := make([]*huh.Group, len(w.Steps))
+	for i, step := range w.Steps {
+		i := i
+		groups[i] = huh.NewGroup(step.Field).WithHideFunc(func() bool {
+			return !w.runs(i)
+		})
+	}
+
+	if err := newForm(groups...).Run(); err != nil {
+		return nil, err
+	}
+	return w.state(len(w.Steps)), nil
+}
+
+// runs reports whether step i runs given the answers before it
+func (w *Wizard) runs(i int) bool {
+	_, ran := w.walk(i + 1)
+	return ran[i]
+}
+
+// state returns the answers to the steps before step n that ran
+func (w *Wizard) state(n int) map[string]interface{} {
+	state, _ := w.walk(n)
+	return state
+}
+
+// walk decides in one pass which of the steps before step n run, calling
+// each ShouldRun once with the answers gathered so far. It returns those
+// answers and whether each step ran.
+func (w *Wizard) walk(n int) (map[string]interface{}, []bool) {
+	state := make(map[string]interface{})
+	ran := make([]bool, n)
+	for i, step := range w.Steps[:n] {
+		ran[i] = step.ShouldRun == nil || step.ShouldRun(state)
+		if ran[i] {
+			state[step.Name] = step.Field.GetValue()
+		}
+	}
+	return state, ran
+}
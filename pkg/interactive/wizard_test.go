@@ -0,0 +1,94 @@
+package interactive
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/charmbracelet/huh"
+)
+
+func newTestWizard() *Wizard {
+	return &Wizard{Steps: []WizardStep{
+		{Name: "name", Field: huh.NewInput().Title("Project name")},
+		{Name: "env", Field: huh.NewSelect[string]().Title("Environment").Options(huh.NewOptions("dev", "prod")...)},
+		{
+			Name:  "approver",
+			Field: huh.NewInput().Title("Approver"),
+			ShouldRun: func(state map[string]interface{}) bool {
+				return state["env"] == "prod"
+			},
+		},
+		{Name: "confirm", Field: huh.NewConfirm().Title("Create it?")},
+	}}
+}
+
+func TestWizard(t *testing.T) {
+	withInput(t, "demo\r\x1b[B\ralice\ry")
+
+	got, err := newTestWizard().Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := map[string]interface{}{"name": "demo", "env": "prod", "approver": "alice", "confirm": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Run() = %v, want %v", got, want)
+	}
+}
+
+func TestWizardSkipsStep(t *testing.T) {
+	withInput(t, "demo\r\r\x1b[D\r")
+
+	got, err := newTestWizard().Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := map[string]interface{}{"name": "demo", "env": "dev", "confirm": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Run() = %v, want %v", got, want)
+	}
+}
+
+func TestWizardBack(t *testing.T) {
+	withInput(t, "")
+	// shift+tab is read on its own so the form returns to the first step
+	// before the edit is typed
+	promptInput = &pacedReader{lines: []string{"demo\r", "\x1b[Z", "\x7f\x7f\x7f\x7fapp\r", "\r", "\x1b[D\r"}}
+
+	got, err := newTestWizard().Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got["name"] != "app" {
+		t.Errorf("name = %v, want the answer edited after going back", got["name"])
+	}
+}
+
+func TestWizardManyConditionalSteps(t *testing.T) {
+	calls := 0
+	w := &Wizard{}
+	for i := 0; i < 20; i++ {
+		w.Steps = append(w.Steps, WizardStep{
+			Name:  strconv.Itoa(i),
+			Field: huh.NewInput(),
+			ShouldRun: func(state map[string]interface{}) bool {
+				calls++
+				return i%2 == 0
+			},
+		})
+	}
+
+	w.runs(19)
+	if calls != 20 {
+		t.Errorf("runs(19) called ShouldRun %d times, want 20", calls)
+	}
+	calls = 0
+	if got := w.state(20); len(got) != 10 {
+		t.Errorf("state(20) has %d answers, want 10", len(got))
+	}
+	if calls != 20 {
+		t.Errorf("state(20) called ShouldRun %d times, want 20", calls)
+	}
+}
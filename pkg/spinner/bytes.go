@@ -0,0 +1,49 @@
+package spinner
+
+import (
+	"fmt"
+	"io"
+)
+
+// NewByteProgress creates a progress bar that counts bytes and shows the
+// transferred and total sizes, like "12.4 MB / 50.0 MB"
+func NewByteProgress(message string, totalBytes int64) *Progress {
+	p := NewProgress(message, int(totalBytes))
+	p.bytes = true
+	return p
+}
+
+// AddBytes advances the progress by n bytes
+func (p *Progress) AddBytes(n int64) {
+	p.Set(p.current + int(n))
+}
+
+// Writer returns an io.Writer that advances the progress by the number of
+// bytes written to it, for use with io.Copy and io.MultiWriter
+func (p *Progress) Writer() io.Writer {
+	return progressWriter{p}
+}
+
+type progressWriter struct {
+	p *Progress
+}
+
+func (w progressWriter) Write(b []byte) (int, error) {
+	w.p.AddBytes(int64(len(b)))
+	return len(b), nil
+}
+
+// formatBytes formats a byte count using binary units, like "12.4 MB"
+func formatBytes(n float64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%.0f B", n)
+	}
+
+	units := []string{"KB", "MB", "GB", "TB", "PB"}
+	i := 0
+	for n /= unit; n >= unit && i < len(units)-1; n /= unit {
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", n, units[i])
+}
@@ -0,0 +1,215 @@
+package spinner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SpinnerGroup runs named tasks concurrently, showing one spinner line per task.
+// When the output is not a terminal, a line is printed as each task finishes
+// instead; in quiet mode nothing is printed.
+//
+// Example:
+//
+//	g := spinner.NewSpinnerGroup()
+//	g.Go("Fetch users", fetchUsers)
+//	g.Go("Fetch orders", fetchOrders)
+//	if err := g.Wait(); err != nil {
+//		return err
+//	}
+type SpinnerGroup struct {
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	tasks   []*groupTask
+	spinner spinner.Model
+	style   lipgloss.Style
+	output  io.Writer
+	input   io.Reader
+	program *tea.Program
+	done    chan struct{}
+	started bool
+}
+
+type groupTask struct {
+	name string
+	done bool
+	err  error
+}
+
+type groupModel struct {
+	spinner spinner.Model
+	style   lipgloss.Style
+	tasks   []groupTask
+}
+
+type taskAddedMsg struct{ name string }
+type taskDoneMsg struct {
+	index int
+	err   error
+}
+type groupDoneMsg struct{}
+
+func (m groupModel) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m groupModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		return m, nil
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case taskAddedMsg:
+		m.tasks = append(m.tasks, groupTask{name: msg.name})
+		return m, nil
+	case taskDoneMsg:
+		m.tasks[msg.index].done = true
+		m.tasks[msg.index].err = msg.err
+		return m, nil
+	case groupDoneMsg:
+		return m, tea.Quit
+	default:
+		return m, nil
+	}
+}
+
+func (m groupModel) View() string {
+	lines := make([]string, len(m.tasks))
+	for i, task := range m.tasks {
+		switch {
+		case task.done && task.err != nil:
+			lines[i] = m.style.Foreground(lipgloss.Color("#EF4444")).Render("✗ " + task.name + ": " + task.err.Error())
+		case task.done:
+			lines[i] = m.style.Foreground(lipgloss.Color("#10B981")).Render("✓ " + task.name)
+		default:
+			lines[i] = m.spinner.View() + " " + m.style.Render(task.name)
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// NewSpinnerGroup creates an empty spinner group
+func NewSpinnerGroup() *SpinnerGroup {
+	s := spinner.New()
+	s.Spinner = spinner.Spinner(Dot)
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#7C3AED"))
+
+	return &SpinnerGroup{
+		spinner: s,
+		style:   lipgloss.NewStyle().Foreground(lipgloss.Color("#F3F4F6")),
+		output:  os.Stdout,
+	}
+}
+
+// SetStyle sets the spinner frames used for every task
+func (g *SpinnerGroup) SetStyle(style SpinnerStyle) {
+	g.spinner.Spinner = spinner.Spinner(style)
+}
+
+// SetOutput sets the output writer
+func (g *SpinnerGroup) SetOutput(w io.Writer) {
+	g.output = w
+}
+
+// SetInput sets the reader keyboard input (such as ctrl+c) is read from.
+// By default the terminal is used.
+func (g *SpinnerGroup) SetInput(r io.Reader) {
+	g.input = r
+}
+
+// Go runs fn in a goroutine, shown as its own line labelled name
+func (g *SpinnerGroup) Go(name string, fn func() error) {
+	g.mu.Lock()
+	if !g.started {
+		g.start()
+	}
+	index := len(g.tasks)
+	g.tasks = append(g.tasks, &groupTask{name: name})
+	if g.program != nil {
+		g.program.Send(taskAddedMsg{name: name})
+	}
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		err := fn()
+
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.tasks[index].done = true
+		g.tasks[index].err = err
+		if g.program == nil {
+			if err != nil {
+				fmt.Fprintf(g.output, "✗ %s: %v\n", name, err)
+			} else {
+				fmt.Fprintf(g.output, "✓ %s\n", name)
+			}
+			return
+		}
+		g.program.Send(taskDoneMsg{index: index, err: err})
+	}()
+}
+
+// start launches the program that renders all task lines together on a
+// terminal
+func (g *SpinnerGroup) start() {
+	g.started = true
+	if Quiet() {
+		g.output = io.Discard
+	}
+	if !isTerminal(g.output) {
+		return
+	}
+
+	model := groupModel{
+		spinner: g.spinner,
+		style:   g.style,
+	}
+	model.spinner.Spinner = withFPS(model.spinner.Spinner, 0)
+	opts := []tea.ProgramOption{tea.WithOutput(g.output)}
+	if g.input != nil {
+		opts = append(opts, tea.WithInput(g.input))
+	}
+	g.program = tea.NewProgram(model, opts...)
+	g.done = make(chan struct{})
+	go func() {
+		g.program.Run()
+		close(g.done)
+	}()
+}
+
+// Wait waits for all tasks to finish and returns their errors joined in the
+// order the tasks were started
+func (g *SpinnerGroup) Wait() error {
+	g.wg.Wait()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.program != nil {
+		g.program.Send(groupDoneMsg{})
+		<-g.done
+	}
+
+	var errs []error
+	for _, task := range g.tasks {
+		if task.err != nil {
+			errs = append(errs, task.err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,131 @@
+package spinner
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSpinnerGroup(t *testing.T) {
+	withTerminal(t)
+	buf := new(bytes.Buffer)
+	g := NewSpinnerGroup()
+	g.SetOutput(buf)
+	g.SetInput(strings.NewReader(""))
+
+	errDeploy := errors.New("deploy failed")
+	g.Go("build", func() error { return nil })
+	g.Go("test", func() error { return nil })
+	g.Go("deploy", func() error { return errDeploy })
+
+	err := g.Wait()
+	if !errors.Is(err, errDeploy) {
+		t.Fatalf("Wait() error = %v, want %v", err, errDeploy)
+	}
+
+	for i, want := range []struct {
+		name string
+		err  error
+	}{{"build", nil}, {"test", nil}, {"deploy", errDeploy}} {
+		task := g.tasks[i]
+		if task.name != want.name || !task.done || task.err != want.err {
+			t.Errorf("task %d = %+v, want %s done with err %v", i, *task, want.name, want.err)
+		}
+	}
+
+	output := buf.String()
+	for _, want := range []string{"✓ build", "✓ test", "✗ deploy: deploy failed"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q, got: %q", want, output)
+		}
+	}
+}
+
+func TestSpinnerGroupJoinsErrors(t *testing.T) {
+	g := NewSpinnerGroup()
+	g.SetOutput(new(bytes.Buffer))
+	g.SetInput(strings.NewReader(""))
+
+	errA, errB := errors.New("a failed"), errors.New("b failed")
+	g.Go("a", func() error { return errA })
+	g.Go("b", func() error { return errB })
+
+	err := g.Wait()
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("Wait() error = %v, want both task errors", err)
+	}
+}
+
+func TestSpinnerGroupEmpty(t *testing.T) {
+	if err := NewSpinnerGroup().Wait(); err != nil {
+		t.Errorf("Wait() on an empty group error = %v", err)
+	}
+}
+
+func TestGroupModelView(t *testing.T) {
+	m := groupModel{spinner: NewSpinnerGroup().spinner}
+	for _, msg := range []interface{}{
+		taskAddedMsg{name: "one"},
+		taskAddedMsg{name: "two"},
+		taskDoneMsg{index: 0},
+	} {
+		updated, _ := m.Update(msg)
+		m = updated.(groupModel)
+	}
+
+	lines := strings.Split(strings.TrimRight(m.View(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("view should have one line per task, got %q", lines)
+	}
+	if !strings.Contains(lines[0], "✓ one") {
+		t.Errorf("finished task line = %q, want ✓ one", lines[0])
+	}
+	if strings.Contains(lines[1], "✓") || !strings.Contains(lines[1], "two") {
+		t.Errorf("running task line = %q, want a spinner and two", lines[1])
+	}
+}
+
+func TestSpinnerGroupPlain(t *testing.T) {
+	buf := new(bytes.Buffer)
+	g := NewSpinnerGroup()
+	g.SetOutput(buf)
+
+	errDeploy := errors.New("deploy failed")
+	g.Go("build", func() error { return nil })
+	g.Go("deploy", func() error { return errDeploy })
+
+	if err := g.Wait(); !errors.Is(err, errDeploy) {
+		t.Fatalf("Wait() error = %v, want %v", err, errDeploy)
+	}
+
+	output := buf.String()
+	if strings.Contains(output, "\x1b") {
+		t.Errorf("output to a non-terminal should have no escape codes, got: %q", output)
+	}
+	for _, want := range []string{"✓ build\n", "✗ deploy: deploy failed\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q, got: %q", want, output)
+		}
+	}
+}
+
+func TestSpinnerGroupQuiet(t *testing.T) {
+	withTerminal(t)
+	SetQuiet(true)
+	t.Cleanup(func() { SetQuiet(false) })
+	buf := new(bytes.Buffer)
+
+	g := NewSpinnerGroup()
+	g.SetOutput(buf)
+	g.SetInput(strings.NewReader(""))
+	errDeploy := errors.New("deploy failed")
+	g.Go("deploy", func() error { return errDeploy })
+
+	if err := g.Wait(); !errors.Is(err, errDeploy) {
+		t.Errorf("Wait() error = %v, want %v", err, errDeploy)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("quiet mode should print nothing, got: %q", buf.String())
+	}
+}
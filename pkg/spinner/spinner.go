@@ -1,34 +1,141 @@
 package spinner
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
 )
 
+// now returns the current time; tests replace it to control elapsed time
+var now = time.Now
+
+// isTerminal reports whether w is connected to a terminal.
+// It is a variable so tests can simulate a TTY.
+var isTerminal = func(w io.Writer) bool {
+	f, ok := w.(interface{ Fd() uintptr })
+	if !ok {
+		return false
+	}
+	return term.IsTerminal(f.Fd())
+}
+
+// terminalWidth returns the number of columns of the terminal w is connected
+// to, or 0 when it is not a terminal. It is a variable so tests can simulate
+// a terminal size.
+var terminalWidth = func(w io.Writer) int {
+	f, ok := w.(interface{ Fd() uintptr })
+	if !ok {
+		return 0
+	}
+	width, _, err := term.GetSize(f.Fd())
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// quiet suppresses spinners and progress bars
+var quiet atomic.Bool
+
+// SetQuiet turns quiet mode on or off. In quiet mode spinners and progress
+// bars print nothing, while the functions they wrap still run.
+func SetQuiet(enabled bool) {
+	quiet.Store(enabled)
+}
+
+// Quiet reports whether quiet mode is on
+func Quiet() bool {
+	return quiet.Load()
+}
+
+// defaultFPS is the frame rate set by SetDefaultFPS
+var defaultFPS atomic.Int64
+
+// SetDefaultFPS sets the frame rate of spinners without their own SetFPS. A
+// value of 0 restores the rate of each spinner style.
+func SetDefaultFPS(fps int) {
+	defaultFPS.Store(int64(fps))
+}
+
+// withFPS returns style drawn at fps frames per second, or at the default
+// rate when fps is not positive. Without either, style is unchanged.
+func withFPS(style spinner.Spinner, fps int) spinner.Spinner {
+	if fps <= 0 {
+		fps = int(defaultFPS.Load())
+	}
+	if fps > 0 {
+		style.FPS = time.Second / time.Duration(fps)
+	}
+	return style
+}
+
 // Spinner represents a loading spinner
 type Spinner struct {
-	message string
-	style   lipgloss.Style
-	spinner spinner.Model
-	done    bool
-	err     error
-	output  io.Writer
-	program *tea.Program
+	message     string
+	style       lipgloss.Style
+	spinner     spinner.Model
+	done        bool
+	err         error
+	output      io.Writer
+	program     *tea.Program
+	showElapsed bool
+
+	// plain is set when the output is not a terminal; the spinner then
+	// prints one line at start and one at completion instead of animating
+	plain bool
+	start time.Time
+
+	// interrupt is called when the user presses ctrl+c
+	interrupt func()
+
+	// fps overrides the frame rate of the style when positive
+	fps int
+}
+
+// SpinnerStyle is a set of frames and the interval between them
+type SpinnerStyle spinner.Spinner
+
+// Built-in spinner styles
+var (
+	Dot       = SpinnerStyle(spinner.Dot)
+	Line      = SpinnerStyle(spinner.Line)
+	MiniDot   = SpinnerStyle(spinner.MiniDot)
+	Jump      = SpinnerStyle(spinner.Jump)
+	Pulse     = SpinnerStyle(spinner.Pulse)
+	Points    = SpinnerStyle(spinner.Points)
+	Globe     = SpinnerStyle(spinner.Globe)
+	Moon      = SpinnerStyle(spinner.Moon)
+	Monkey    = SpinnerStyle(spinner.Monkey)
+	Meter     = SpinnerStyle(spinner.Meter)
+	Hamburger = SpinnerStyle(spinner.Hamburger)
+	Ellipsis  = SpinnerStyle(spinner.Ellipsis)
+)
+
+// SpinnerFromFrames creates a spinner style from custom frames
+func SpinnerFromFrames(frames []string, interval time.Duration) SpinnerStyle {
+	return SpinnerStyle{Frames: frames, FPS: interval}
 }
 
 type spinnerModel struct {
-	spinner spinner.Model
-	message string
-	style   lipgloss.Style
-	done    bool
-	err     error
+	spinner     spinner.Model
+	message     string
+	style       lipgloss.Style
+	done        bool
+	err         error
+	start       time.Time
+	elapsed     time.Duration
+	showElapsed bool
+	interrupt   func()
 }
 
 func (m spinnerModel) Init() tea.Cmd {
@@ -39,19 +146,31 @@ func (m spinnerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if msg.String() == "ctrl+c" {
+			if m.interrupt != nil {
+				m.interrupt()
+			}
 			return m, tea.Quit
 		}
 		return m, nil
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
+		m.elapsed = now().Sub(m.start)
 		return m, cmd
 	case doneMsg:
+		if msg.message != "" {
+			m.message = msg.message
+		}
 		m.done = true
+		m.elapsed = now().Sub(m.start)
 		return m, tea.Quit
 	case errMsg:
+		if msg.message != "" {
+			m.message = msg.message
+		}
 		m.err = msg.err
 		m.done = true
+		m.elapsed = now().Sub(m.start)
 		return m, tea.Quit
 	default:
 		return m, nil
@@ -61,20 +180,32 @@ func (m spinnerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m spinnerModel) View() string {
 	if m.done {
 		if m.err != nil {
-			return m.style.Foreground(lipgloss.Color("#EF4444")).Render("✗ " + m.message + ": " + m.err.Error())
+			return m.style.Foreground(lipgloss.Color("#EF4444")).Render("✗ " + m.message + ": " + m.err.Error() + m.elapsedSuffix())
 		}
-		return m.style.Foreground(lipgloss.Color("#10B981")).Render("✓ " + m.message)
+		return m.style.Foreground(lipgloss.Color("#10B981")).Render("✓ " + m.message + m.elapsedSuffix())
+	}
+	return m.spinner.View() + " " + m.style.Render(m.message+m.elapsedSuffix())
+}
+
+// elapsedSuffix returns the elapsed time, like " (12s)", when enabled
+func (m spinnerModel) elapsedSuffix() string {
+	if !m.showElapsed {
+		return ""
 	}
-	return m.spinner.View() + " " + m.style.Render(m.message)
+	return fmt.Sprintf(" (%s)", m.elapsed.Round(time.Second))
 }
 
-type doneMsg struct{}
-type errMsg struct{ err error }
+// doneMsg and errMsg finish the spinner, replacing its message when set
+type doneMsg struct{ message string }
+type errMsg struct {
+	err     error
+	message string
+}
 
 // New creates a new spinner
 func New(message string) *Spinner {
 	s := spinner.New()
-	s.Spinner = spinner.Dot
+	s.Spinner = spinner.Spinner(Dot)
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#7C3AED"))
 
 	return &Spinner{
@@ -90,39 +221,117 @@ func (s *Spinner) SetMessage(message string) {
 	s.message = message
 }
 
+// SetStyle sets the spinner frames
+func (s *Spinner) SetStyle(style SpinnerStyle) {
+	s.spinner.Spinner = spinner.Spinner(style)
+}
+
+// SetFPS sets how many frames per second the spinner draws, overriding its
+// style and SetDefaultFPS. Lower rates redraw less often, which saves CPU and
+// bandwidth over slow connections.
+func (s *Spinner) SetFPS(fps int) {
+	s.fps = fps
+}
+
 // SetOutput sets the output writer
 func (s *Spinner) SetOutput(w io.Writer) {
 	s.output = w
 }
 
-// Start starts the spinner
+// SetShowElapsed toggles an elapsed time suffix, like "Building... (12s)"
+func (s *Spinner) SetShowElapsed(show bool) {
+	s.showElapsed = show
+}
+
+// Start starts the spinner. When the output is not a terminal, the message
+// is printed once instead of animated; in quiet mode nothing is printed.
 func (s *Spinner) Start() *Spinner {
-	model := spinnerModel{
-		spinner: s.spinner,
-		message: s.message,
-		style:   s.style,
+	s.start = now()
+	if Quiet() {
+		s.output = io.Discard
+	}
+	if !isTerminal(s.output) {
+		s.plain = true
+		fmt.Fprintln(s.output, s.message)
+		return s
 	}
-	s.program = tea.NewProgram(model, tea.WithOutput(s.output))
+
+	s.program = tea.NewProgram(s.model(), tea.WithOutput(s.output))
 	go s.program.Run()
 	return s
 }
 
+// model returns the model that animates the spinner, at the frame rate set
+// by SetFPS or SetDefaultFPS if any
+func (s *Spinner) model() spinnerModel {
+	m := spinnerModel{
+		spinner:     s.spinner,
+		message:     s.message,
+		style:       s.style,
+		start:       s.start,
+		showElapsed: s.showElapsed,
+		interrupt:   s.interrupt,
+	}
+	m.spinner.Spinner = withFPS(m.spinner.Spinner, s.fps)
+	return m
+}
+
 // Stop stops the spinner
 func (s *Spinner) Stop() {
+	s.Succeed("")
+}
+
+// Succeed stops the spinner, showing msg in place of the start message, such
+// as "Downloaded 42 files" after "Downloading...". An empty msg keeps the
+// start message.
+func (s *Spinner) Succeed(msg string) {
+	if s.plain {
+		s.finishPlain("✓ " + s.finalMessage(msg))
+		return
+	}
 	if s.program != nil {
-		s.program.Send(doneMsg{})
+		s.program.Send(doneMsg{message: msg})
 		time.Sleep(50 * time.Millisecond) // Give it time to render
 	}
 }
 
-// Fail stops the spinner with an error
-func (s *Spinner) Fail(err error) {
+// Fail stops the spinner with an error. An optional msg replaces the start
+// message, as in "✗ Download failed: connection refused".
+func (s *Spinner) Fail(err error, msg ...string) {
+	final := ""
+	if len(msg) > 0 {
+		final = msg[0]
+	}
+	if s.plain {
+		s.finishPlain("✗ " + s.finalMessage(final) + ": " + err.Error())
+		return
+	}
 	if s.program != nil {
-		s.program.Send(errMsg{err: err})
+		s.program.Send(errMsg{err: err, message: final})
 		time.Sleep(50 * time.Millisecond) // Give it time to render
 	}
 }
 
+// finalMessage returns msg, or the start message when msg is empty
+func (s *Spinner) finalMessage(msg string) string {
+	if msg == "" {
+		return s.message
+	}
+	return msg
+}
+
+// finishPlain prints the completion line of a non-animated spinner once
+func (s *Spinner) finishPlain(line string) {
+	if s.done {
+		return
+	}
+	s.done = true
+	if s.showElapsed {
+		line += fmt.Sprintf(" (%s)", now().Sub(s.start).Round(time.Second))
+	}
+	fmt.Fprintln(s.output, line)
+}
+
 // Wait waits for the spinner to finish
 func (s *Spinner) Wait() {
 	if s.program != nil {
@@ -147,7 +356,70 @@ func WithSpinner(message string, fn func() error) error {
 	return err
 }
 
-// Progress represents a progress bar
+// WithSpinnerMsg runs a function with a spinner showing start, which is
+// replaced by success when the function succeeds
+func WithSpinnerMsg(start, success string, fn func() error) error {
+	s := New(start)
+	s.Start()
+
+	err := fn()
+
+	if err != nil {
+		s.Fail(err)
+	} else {
+		s.Succeed(success)
+	}
+
+	s.Wait()
+	return err
+}
+
+// WithSpinnerStyle runs a function with a spinner using the given style
+func WithSpinnerStyle(message string, style SpinnerStyle, fn func() error) error {
+	s := New(message)
+	s.SetStyle(style)
+	s.Start()
+
+	err := fn()
+
+	if err != nil {
+		s.Fail(err)
+	} else {
+		s.Stop()
+	}
+
+	s.Wait()
+	return err
+}
+
+// WithSpinnerContext runs fn with a spinner, passing it a context that is
+// canceled when ctx is done or the user presses ctrl+c so fn can clean up
+func WithSpinnerContext(ctx context.Context, message string, fn func(ctx context.Context) error) error {
+	return New(message).runContext(ctx, fn)
+}
+
+// runContext runs fn while the spinner is shown and reports its result
+func (s *Spinner) runContext(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	s.interrupt = cancel
+	s.Start()
+
+	err := fn(ctx)
+
+	if err != nil {
+		s.Fail(err)
+	} else {
+		s.Stop()
+	}
+
+	s.Wait()
+	return err
+}
+
+// Progress represents a progress bar. A total of zero or less makes it
+// indeterminate: an animated bar bounces back and forth until SetTotal is
+// called or the bar is finished with Done.
 type Progress struct {
 	total   int
 	current int
@@ -155,17 +427,65 @@ type Progress struct {
 	prog    progress.Model
 	output  io.Writer
 	program *tea.Program
+	start   time.Time
+	showETA bool
+	unit    string
+	bytes   bool
+	input   io.Reader
+
+	// interrupt is called when the user presses ctrl+c
+	interrupt func()
 }
 
 type progressModel struct {
-	progress progress.Model
-	current  float64
-	total    float64
-	message  string
-	done     bool
+	progress  progress.Model
+	current   float64
+	total     float64
+	message   string
+	done      bool
+	start     time.Time
+	elapsed   time.Duration
+	showETA   bool
+	unit      string
+	bytes     bool
+	interrupt func()
+
+	// frame advances the indeterminate animation
+	frame int
+}
+
+// maxProgressWidth and minProgressWidth bound the width of the bar, which
+// otherwise follows the terminal width
+const (
+	maxProgressWidth = 80
+	minProgressWidth = 10
+)
+
+// progressWidth returns the bar width for a terminal of the given number of
+// columns
+func progressWidth(columns int) int {
+	return min(max(columns-4, minProgressWidth), maxProgressWidth)
+}
+
+// marqueeInterval is the time between frames of the indeterminate bar
+const marqueeInterval = 80 * time.Millisecond
+
+type marqueeTickMsg struct{}
+
+// marqueeTick schedules the next frame of the indeterminate bar
+func marqueeTick() tea.Cmd {
+	return tea.Tick(marqueeInterval, func(time.Time) tea.Msg { return marqueeTickMsg{} })
+}
+
+// indeterminate reports whether the total is unknown
+func (m progressModel) indeterminate() bool {
+	return m.total <= 0
 }
 
 func (m progressModel) Init() tea.Cmd {
+	if m.indeterminate() {
+		return marqueeTick()
+	}
 	return nil
 }
 
@@ -173,22 +493,45 @@ func (m progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if msg.String() == "ctrl+c" {
+			if m.interrupt != nil {
+				m.interrupt()
+			}
 			return m, tea.Quit
 		}
 		return m, nil
 	case progressMsg:
 		m.current = msg.current
-		if m.current >= m.total {
+		if !msg.at.IsZero() {
+			m.elapsed = msg.at.Sub(m.start)
+		}
+		if !m.indeterminate() && m.current >= m.total {
 			m.done = true
 			return m, tea.Quit
 		}
 		return m, nil
-	case tea.WindowSizeMsg:
-		m.progress.Width = msg.Width - 4
-		if m.progress.Width > 80 {
-			m.progress.Width = 80
+	case totalMsg:
+		wasIndeterminate := m.indeterminate()
+		m.total = msg.total
+		if !m.indeterminate() && m.current >= m.total {
+			m.done = true
+			return m, tea.Quit
+		}
+		if m.indeterminate() && !wasIndeterminate {
+			return m, marqueeTick()
 		}
 		return m, nil
+	case progressDoneMsg:
+		m.done = true
+		return m, tea.Quit
+	case marqueeTickMsg:
+		if m.done || !m.indeterminate() {
+			return m, nil
+		}
+		m.frame++
+		return m, marqueeTick()
+	case tea.WindowSizeMsg:
+		m.progress.Width = progressWidth(msg.Width)
+		return m, nil
 	default:
 		return m, nil
 	}
@@ -196,28 +539,104 @@ func (m progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m progressModel) View() string {
 	if m.done {
+		final := "✓ " + m.message + " (100%)"
+		if m.indeterminate() {
+			final = "✓ " + m.message
+		}
 		return lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#10B981")).
-			Render("✓ " + m.message + " (100%)")
+			Render(final)
+	}
+
+	if m.indeterminate() {
+		view := m.message + "\n" + marquee(m.progress.Width, m.frame)
+		if m.bytes {
+			view += " " + formatBytes(m.current)
+		} else if m.current > 0 {
+			view += fmt.Sprintf(" %.0f %s", m.current, m.unit)
+		}
+		return view
 	}
 
 	percent := m.current / m.total
-	return fmt.Sprintf("%s\n%s %.0f%%",
+	view := fmt.Sprintf("%s\n%s %.0f%%",
 		m.message,
 		m.progress.ViewAs(percent),
 		percent*100,
 	)
+
+	if m.bytes {
+		view += fmt.Sprintf(" | %s / %s", formatBytes(m.current), formatBytes(m.total))
+	}
+
+	// Rate and ETA need at least one timed update to avoid dividing by zero
+	if m.showETA && m.current > 0 && m.elapsed > 0 {
+		rate := m.current / m.elapsed.Seconds()
+		remaining := time.Duration((m.total - m.current) / rate * float64(time.Second))
+		rateStr := formatRate(rate, m.unit)
+		if m.bytes {
+			rateStr = formatBytes(rate) + "/s"
+		}
+		view += fmt.Sprintf(" | %s | ETA %s", rateStr, formatETA(remaining))
+	}
+
+	return view
+}
+
+// marquee renders an indeterminate bar of the given width with a block that
+// bounces from end to end as frame advances
+func marquee(width, frame int) string {
+	width = max(width, minProgressWidth)
+	block := max(width/5, 3)
+	span := width - block
+
+	pos := frame % (2 * span)
+	if pos > span {
+		pos = 2*span - pos
+	}
+
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#3A3A3A")).Render(strings.Repeat("░", pos)) +
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#7C3AED")).Render(strings.Repeat("█", block)) +
+		lipgloss.NewStyle().Foreground(lipgloss.Color("#3A3A3A")).Render(strings.Repeat("░", span-pos))
+}
+
+// formatRate formats a rate as units per second
+func formatRate(rate float64, unit string) string {
+	if rate < 10 {
+		return fmt.Sprintf("%.1f %s/s", rate, unit)
+	}
+	return fmt.Sprintf("%.0f %s/s", rate, unit)
+}
+
+// formatETA formats a duration as mm:ss, or hh:mm:ss past an hour
+func formatETA(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := int(d / time.Hour)
+	m := int(d/time.Minute) % 60
+	s := int(d/time.Second) % 60
+	if h > 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
 }
 
 type progressMsg struct {
 	current float64
+	at      time.Time
 }
 
+// totalMsg changes the total, switching between indeterminate and
+// determinate mode
+type totalMsg struct{ total float64 }
+
+// progressDoneMsg completes the bar regardless of the current value
+type progressDoneMsg struct{}
+
 // NewProgress creates a new progress bar
 func NewProgress(message string, total int) *Progress {
 	p := progress.New(
 		progress.WithDefaultGradient(),
-		progress.WithWidth(80),
+		progress.WithWidth(maxProgressWidth),
 	)
 
 	return &Progress{
@@ -226,6 +645,8 @@ func NewProgress(message string, total int) *Progress {
 		message: message,
 		prog:    p,
 		output:  os.Stdout,
+		showETA: true,
+		unit:    "it",
 	}
 }
 
@@ -234,15 +655,49 @@ func (p *Progress) SetOutput(w io.Writer) {
 	p.output = w
 }
 
-// Start starts the progress bar
+// SetInput sets the reader keyboard input (such as ctrl+c) is read from.
+// By default the terminal is used.
+func (p *Progress) SetInput(r io.Reader) {
+	p.input = r
+}
+
+// SetShowETA toggles the rate and ETA display
+func (p *Progress) SetShowETA(show bool) {
+	p.showETA = show
+}
+
+// SetUnit sets the unit shown in the rate, such as "MB"
+func (p *Progress) SetUnit(unit string) {
+	p.unit = unit
+}
+
+// Start starts the progress bar. In quiet mode nothing is shown. The bar
+// fits the terminal width and follows it when the terminal is resized.
 func (p *Progress) Start() *Progress {
+	// The rate and ETA are measured from here, not from NewProgress
+	p.start = now()
+	if Quiet() {
+		return p
+	}
+	if columns := terminalWidth(p.output); columns > 0 {
+		p.prog.Width = progressWidth(columns)
+	}
 	model := progressModel{
-		progress: p.prog,
-		current:  0,
-		total:    float64(p.total),
-		message:  p.message,
+		progress:  p.prog,
+		current:   0,
+		total:     float64(p.total),
+		message:   p.message,
+		start:     p.start,
+		showETA:   p.showETA,
+		unit:      p.unit,
+		bytes:     p.bytes,
+		interrupt: p.interrupt,
 	}
-	p.program = tea.NewProgram(model, tea.WithOutput(p.output))
+	opts := []tea.ProgramOption{tea.WithOutput(p.output)}
+	if p.input != nil {
+		opts = append(opts, tea.WithInput(p.input))
+	}
+	p.program = tea.NewProgram(model, opts...)
 	go p.program.Run()
 	return p
 }
@@ -250,7 +705,7 @@ func (p *Progress) Start() *Progress {
 // Increment increments the progress
 func (p *Progress) Increment() {
 	if p.program != nil {
-		p.program.Send(progressMsg{current: float64(p.current + 1)})
+		p.program.Send(progressMsg{current: float64(p.current + 1), at: now()})
 		p.current++
 	}
 }
@@ -258,11 +713,36 @@ func (p *Progress) Increment() {
 // Set sets the progress to a specific value
 func (p *Progress) Set(current int) {
 	if p.program != nil {
-		p.program.Send(progressMsg{current: float64(current)})
+		p.program.Send(progressMsg{current: float64(current), at: now()})
 		p.current = current
 	}
 }
 
+// SetTotal changes the total, such as once the size of a download becomes
+// known. A positive total switches an indeterminate bar to a percentage.
+func (p *Progress) SetTotal(total int) {
+	p.total = total
+	if p.program != nil {
+		p.program.Send(totalMsg{total: float64(total)})
+	}
+}
+
+// Done completes the progress bar, including an indeterminate one
+func (p *Progress) Done() {
+	if p.program != nil {
+		p.program.Send(progressDoneMsg{})
+	}
+}
+
+// finish completes the bar, at the total if known
+func (p *Progress) finish() {
+	if p.total <= 0 {
+		p.Done()
+		return
+	}
+	p.Set(p.total)
+}
+
 // Wait waits for the progress bar to finish
 func (p *Progress) Wait() {
 	if p.program != nil {
@@ -270,22 +750,78 @@ func (p *Progress) Wait() {
 	}
 }
 
-// WithProgress runs a function with a progress bar
+// WithProgress runs a function with a progress bar. Pass a total of -1 when
+// it is unknown to show an indeterminate bar.
 func WithProgress(message string, total int, fn func(update func())) error {
 	p := NewProgress(message, total)
 	p.Start()
 
-	current := 0
-	update := func() {
-		current++
-		p.program.Send(progressMsg{current: float64(current)})
-	}
-
-	fn(update)
+	fn(p.Increment)
 
 	// Ensure we reach 100%
-	p.program.Send(progressMsg{current: float64(total)})
+	p.finish()
 	p.Wait()
 
 	return nil
 }
+
+// WithProgressContext runs fn with a progress bar, passing it a context that
+// is canceled when ctx is done or the user presses ctrl+c so fn can clean up
+func WithProgressContext(ctx context.Context, message string, total int, fn func(ctx context.Context, update func()) error) error {
+	return NewProgress(message, total).runContext(ctx, fn)
+}
+
+// runContext runs fn while the progress bar is shown. The bar is completed
+// when fn succeeds and closed as is when it fails.
+func (p *Progress) runContext(ctx context.Context, fn func(ctx context.Context, update func()) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	p.interrupt = cancel
+	p.Start()
+
+	err := fn(ctx, p.Increment)
+
+	if err != nil {
+		if p.program != nil {
+			p.program.Quit()
+		}
+	} else {
+		p.finish()
+	}
+
+	p.Wait()
+	return err
+}
+
+// WithProgressChan runs a progress bar driven by updates, where each value is
+// the new current count. The bar completes when total is reached or updates
+// is closed, but WithProgressChan reads until updates is closed, so the
+// producer must close it.
+func WithProgressChan(message string, total int, updates <-chan int) {
+	p := NewProgress(message, total)
+	p.Start()
+	p.follow(updates)
+}
+
+// follow sets the progress from each value received on updates until it is
+// closed, completing the bar as soon as the total is reached. Values after
+// that are read and ignored so the producer is not blocked.
+func (p *Progress) follow(updates <-chan int) {
+	finished := false
+	for current := range updates {
+		if finished {
+			continue
+		}
+		p.Set(current)
+		if p.total > 0 && current >= p.total {
+			p.finish()
+			p.Wait()
+			finished = true
+		}
+	}
+
+	if !finished {
+		p.finish()
+		p.Wait()
+	}
+}
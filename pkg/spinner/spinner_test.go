@@ -0,0 +1,637 @@
+package spinner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestNewDefaultStyle(t *testing.T) {
+	s := New("loading")
+
+	if strings.Join(s.spinner.Spinner.Frames, "") != strings.Join(Dot.Frames, "") {
+		t.Errorf("New should default to the Dot style, got frames %v", s.spinner.Spinner.Frames)
+	}
+}
+
+func TestSetStyle(t *testing.T) {
+	styles := map[string]SpinnerStyle{
+		"dot":   Dot,
+		"line":  Line,
+		"globe": Globe,
+		"moon":  Moon,
+	}
+
+	seen := make(map[string]string)
+	for name, style := range styles {
+		s := New("loading")
+		s.SetStyle(style)
+
+		frames := strings.Join(s.spinner.Spinner.Frames, "")
+		if frames != strings.Join(style.Frames, "") {
+			t.Errorf("SetStyle(%s) frames = %v, want %v", name, s.spinner.Spinner.Frames, style.Frames)
+		}
+		if other, ok := seen[frames]; ok {
+			t.Errorf("%s and %s styles have the same frames", name, other)
+		}
+		seen[frames] = name
+	}
+}
+
+func TestSpinnerSetFPS(t *testing.T) {
+	s := New("loading")
+	if got, want := s.model().spinner.Spinner.FPS, Dot.FPS; got != want {
+		t.Errorf("default interval = %v, want the style's %v", got, want)
+	}
+
+	s.SetFPS(4)
+	s.SetStyle(Line)
+	if got, want := s.model().spinner.Spinner.FPS, 250*time.Millisecond; got != want {
+		t.Errorf("interval at 4 FPS = %v, want %v", got, want)
+	}
+}
+
+func TestSetDefaultFPS(t *testing.T) {
+	SetDefaultFPS(2)
+	t.Cleanup(func() { SetDefaultFPS(0) })
+
+	if got, want := New("loading").model().spinner.Spinner.FPS, 500*time.Millisecond; got != want {
+		t.Errorf("interval at default 2 FPS = %v, want %v", got, want)
+	}
+
+	// A spinner's own rate wins over the default
+	s := New("loading")
+	s.SetFPS(20)
+	if got, want := s.model().spinner.Spinner.FPS, 50*time.Millisecond; got != want {
+		t.Errorf("interval at 20 FPS = %v, want %v", got, want)
+	}
+}
+
+func TestSpinnerFromFrames(t *testing.T) {
+	frames := []string{"a", "b", "c"}
+	style := SpinnerFromFrames(frames, 50*time.Millisecond)
+
+	s := New("loading")
+	s.SetStyle(style)
+
+	if strings.Join(s.spinner.Spinner.Frames, ",") != "a,b,c" {
+		t.Errorf("custom frames = %v, want %v", s.spinner.Spinner.Frames, frames)
+	}
+	if s.spinner.Spinner.FPS != 50*time.Millisecond {
+		t.Errorf("custom interval = %v, want %v", s.spinner.Spinner.FPS, 50*time.Millisecond)
+	}
+}
+
+func newTestProgressModel(total int) (progressModel, time.Time) {
+	p := NewProgress("Downloading", total)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return progressModel{
+		progress: p.prog,
+		total:    float64(p.total),
+		message:  p.message,
+		start:    start,
+		showETA:  p.showETA,
+		unit:     p.unit,
+	}, start
+}
+
+func TestProgressViewRateAndETA(t *testing.T) {
+	m, start := newTestProgressModel(100)
+
+	if view := m.View(); strings.Contains(view, "ETA") {
+		t.Errorf("ETA should be hidden before any update, got: %s", view)
+	}
+
+	updated, _ := m.Update(progressMsg{current: 40, at: start.Add(2 * time.Second)})
+	view := updated.View()
+
+	for _, want := range []string{"40%", "20 it/s", "ETA 00:03"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q, got: %s", want, view)
+		}
+	}
+}
+
+func TestProgressIndeterminateView(t *testing.T) {
+	m, _ := newTestProgressModel(-1)
+
+	if m.Init() == nil {
+		t.Fatal("Init() should start the animation in indeterminate mode")
+	}
+
+	first := m.View()
+	updated, cmd := m.Update(marqueeTickMsg{})
+	if cmd == nil {
+		t.Error("a tick should schedule the next frame")
+	}
+	second := updated.View()
+
+	for _, view := range []string{first, second} {
+		if strings.Contains(view, "%") {
+			t.Errorf("indeterminate view should not show a percentage, got: %q", view)
+		}
+		if !strings.Contains(view, "█") {
+			t.Errorf("indeterminate view should show the animated block, got: %q", view)
+		}
+	}
+	if first == second {
+		t.Error("the bar should move between frames")
+	}
+}
+
+func TestMarqueeBounces(t *testing.T) {
+	const width = 20
+	block := strings.Repeat("█", 4)
+
+	if got := ansi.Strip(marquee(width, 0)); !strings.HasPrefix(got, block) {
+		t.Errorf("frame 0 should start at the left edge, got: %q", got)
+	}
+	if got := ansi.Strip(marquee(width, 16)); !strings.HasSuffix(got, block) {
+		t.Errorf("frame 16 should reach the right edge, got: %q", got)
+	}
+	if got, want := ansi.Strip(marquee(width, 20)), ansi.Strip(marquee(width, 12)); got != want {
+		t.Errorf("the block should bounce back, frame 20 = %q, want frame 12 %q", got, want)
+	}
+	if got := ansi.StringWidth(marquee(width, 7)); got != width {
+		t.Errorf("marquee width = %d, want %d", got, width)
+	}
+}
+
+func TestProgressSetTotalSwitchesToDeterminate(t *testing.T) {
+	m, start := newTestProgressModel(0)
+
+	updated, _ := m.Update(progressMsg{current: 25, at: start.Add(time.Second)})
+	if view := updated.View(); strings.Contains(view, "%") || !strings.Contains(view, "25 it") {
+		t.Errorf("indeterminate view should show the count, got: %q", view)
+	}
+
+	updated, _ = updated.Update(totalMsg{total: 100})
+	if view := updated.View(); !strings.Contains(view, "25%") {
+		t.Errorf("view should show a percentage once the total is set, got: %q", view)
+	}
+}
+
+func TestWithProgressIndeterminate(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewProgress("Scanning", -1)
+	p.SetOutput(buf)
+	p.SetInput(strings.NewReader(""))
+
+	err := p.runContext(context.Background(), func(ctx context.Context, update func()) error {
+		update()
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("runContext() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "✓ Scanning") || strings.Contains(buf.String(), "(100%)") {
+		t.Errorf("output should show completion without a percentage, got: %q", buf.String())
+	}
+}
+
+func TestProgressSetUnit(t *testing.T) {
+	m, start := newTestProgressModel(10)
+	m.unit = "MB"
+
+	updated, _ := m.Update(progressMsg{current: 5, at: start.Add(2 * time.Second)})
+
+	if view := updated.View(); !strings.Contains(view, "2.5 MB/s") {
+		t.Errorf("view should show the rate in MB/s, got: %s", view)
+	}
+}
+
+func TestProgressSetShowETA(t *testing.T) {
+	p := NewProgress("Downloading", 10)
+	p.SetShowETA(false)
+	p.SetUnit("MB")
+
+	if p.showETA || p.unit != "MB" {
+		t.Fatalf("SetShowETA/SetUnit not applied: showETA=%v unit=%q", p.showETA, p.unit)
+	}
+
+	m, start := newTestProgressModel(10)
+	m.showETA = false
+	updated, _ := m.Update(progressMsg{current: 5, at: start.Add(time.Second)})
+
+	if view := updated.View(); strings.Contains(view, "ETA") || strings.Contains(view, "/s") {
+		t.Errorf("view should hide rate and ETA when disabled, got: %s", view)
+	}
+}
+
+func TestProgressFollowsWindowSize(t *testing.T) {
+	m, _ := newTestProgressModel(10)
+
+	for _, tt := range []struct {
+		columns int
+		want    int
+	}{
+		{60, 56},
+		{40, 36},
+		{200, maxProgressWidth},
+		{8, minProgressWidth},
+	} {
+		updated, _ := m.Update(tea.WindowSizeMsg{Width: tt.columns, Height: 24})
+		m = updated.(progressModel)
+		if m.progress.Width != tt.want {
+			t.Errorf("width after resize to %d columns = %d, want %d", tt.columns, m.progress.Width, tt.want)
+		}
+	}
+}
+
+func TestProgressStartUsesTerminalWidth(t *testing.T) {
+	original := terminalWidth
+	terminalWidth = func(w io.Writer) int { return 50 }
+	t.Cleanup(func() { terminalWidth = original })
+
+	p := NewProgress("Downloading", 10)
+	p.SetOutput(new(bytes.Buffer))
+	p.SetInput(strings.NewReader(""))
+	p.Start()
+	p.Done()
+	p.Wait()
+
+	if p.prog.Width != 46 {
+		t.Errorf("initial width = %d, want 46", p.prog.Width)
+	}
+}
+
+func TestFormatETA(t *testing.T) {
+	tests := map[time.Duration]string{
+		3 * time.Second:  "00:03",
+		90 * time.Second: "01:30",
+		time.Hour + 2*time.Minute + 3*time.Second: "01:02:03",
+	}
+	for d, want := range tests {
+		if got := formatETA(d); got != want {
+			t.Errorf("formatETA(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestByteProgressView(t *testing.T) {
+	m, start := newTestProgressModel(50 * 1024 * 1024)
+	m.bytes = true
+
+	updated, _ := m.Update(progressMsg{current: 12.4 * 1024 * 1024, at: start.Add(2 * time.Second)})
+	view := updated.View()
+
+	for _, want := range []string{"12.4 MB / 50.0 MB", "6.2 MB/s", "ETA"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q, got: %s", want, view)
+		}
+	}
+}
+
+func TestProgressWriter(t *testing.T) {
+	p := NewByteProgress("Copying", 1024)
+	if !p.bytes {
+		t.Fatal("NewByteProgress should enable byte mode")
+	}
+
+	n, err := io.Copy(p.Writer(), strings.NewReader(strings.Repeat("x", 300)))
+	if err != nil || n != 300 {
+		t.Errorf("io.Copy through Writer() = %d, %v, want 300, nil", n, err)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[float64]string{
+		0:                      "0 B",
+		512:                    "512 B",
+		1024:                   "1.0 KB",
+		1536:                   "1.5 KB",
+		12.4 * 1024 * 1024:     "12.4 MB",
+		3 * 1024 * 1024 * 1024: "3.0 GB",
+	}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%v) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+// withClock replaces now with a clock the test can advance
+func withClock(t *testing.T) *time.Time {
+	t.Helper()
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	t.Cleanup(func() { now = time.Now })
+	return &clock
+}
+
+func TestProgressMeasuresFromStart(t *testing.T) {
+	clock := withClock(t)
+
+	p := NewProgress("Uploading", 10)
+	p.SetOutput(new(bytes.Buffer))
+	p.SetInput(strings.NewReader(""))
+	*clock = clock.Add(time.Minute)
+	p.Start()
+	p.Done()
+	p.Wait()
+
+	if !p.start.Equal(*clock) {
+		t.Errorf("start = %v, want the time Start was called, %v", p.start, *clock)
+	}
+}
+
+func TestSpinnerElapsed(t *testing.T) {
+	clock := withClock(t)
+
+	s := New("Building...")
+	s.SetShowElapsed(true)
+	m := spinnerModel{
+		spinner:     s.spinner,
+		message:     s.message,
+		style:       s.style,
+		start:       now(),
+		showElapsed: s.showElapsed,
+	}
+
+	*clock = clock.Add(12 * time.Second)
+	updated, _ := m.Update(m.spinner.Tick())
+	if view := updated.View(); !strings.Contains(view, "Building... (12s)") {
+		t.Errorf("view should show elapsed time, got: %q", view)
+	}
+
+	*clock = clock.Add(2 * time.Second)
+	updated, _ = updated.Update(doneMsg{})
+	if view := updated.View(); !strings.Contains(view, "✓ Building... (14s)") {
+		t.Errorf("final view should show total duration, got: %q", view)
+	}
+}
+
+func TestSpinnerElapsedHiddenByDefault(t *testing.T) {
+	clock := withClock(t)
+
+	m := spinnerModel{spinner: New("Building...").spinner, message: "Building...", start: now()}
+	*clock = clock.Add(5 * time.Second)
+	updated, _ := m.Update(m.spinner.Tick())
+
+	if view := updated.View(); strings.Contains(view, "(5s)") {
+		t.Errorf("elapsed time should be hidden by default, got: %q", view)
+	}
+}
+
+func TestSpinnerNonTerminal(t *testing.T) {
+	clock := withClock(t)
+	buf := new(bytes.Buffer)
+
+	s := New("Building...")
+	s.SetOutput(buf)
+	s.SetShowElapsed(true)
+	s.Start()
+	*clock = clock.Add(3 * time.Second)
+	s.Stop()
+	s.Stop()
+	s.Wait()
+
+	if got, want := buf.String(), "Building...\n✓ Building... (3s)\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if strings.Contains(buf.String(), "\x1b") {
+		t.Errorf("non-terminal output should not contain escape sequences, got: %q", buf.String())
+	}
+}
+
+func TestSpinnerQuiet(t *testing.T) {
+	SetQuiet(true)
+	t.Cleanup(func() { SetQuiet(false) })
+	buf := new(bytes.Buffer)
+
+	ran := false
+	s := New("Building...")
+	s.SetOutput(buf)
+	s.Start()
+	s.Stop()
+	s.Wait()
+
+	p := NewProgress("Uploading", 3)
+	p.SetOutput(buf)
+	err := p.runContext(context.Background(), func(ctx context.Context, update func()) error {
+		ran = true
+		update()
+		return errors.New("upload failed")
+	})
+
+	if !ran || err == nil {
+		t.Errorf("quiet progress should still run the function, ran = %v, err = %v", ran, err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("quiet mode should print nothing, got: %q", buf.String())
+	}
+}
+
+func TestSpinnerNonTerminalFail(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	s := New("Deploying")
+	s.SetOutput(buf)
+	s.Start()
+	s.Fail(errors.New("connection refused"))
+	s.Wait()
+
+	if got, want := buf.String(), "Deploying\n✗ Deploying: connection refused\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestSpinnerSucceedMessage(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	s := New("Downloading...")
+	s.SetOutput(buf)
+	s.Start()
+	s.Succeed("Downloaded 42 files")
+	s.Wait()
+
+	if got, want := buf.String(), "Downloading...\n✓ Downloaded 42 files\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestSpinnerFailMessage(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	s := New("Downloading...")
+	s.SetOutput(buf)
+	s.Start()
+	s.Fail(errors.New("connection refused"), "Download failed")
+	s.Wait()
+
+	if got, want := buf.String(), "Downloading...\n✗ Download failed: connection refused\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestSpinnerSucceedView(t *testing.T) {
+	m := spinnerModel{spinner: New("Downloading...").spinner, message: "Downloading...", start: now()}
+
+	updated, _ := m.Update(doneMsg{message: "Downloaded 42 files"})
+	view := updated.View()
+	if !strings.Contains(view, "✓ Downloaded 42 files") {
+		t.Errorf("final view should show success message, got: %q", view)
+	}
+	if strings.Contains(view, "Downloading...") {
+		t.Errorf("final view should not show start message, got: %q", view)
+	}
+}
+
+func TestProgressFollow(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		values []int
+	}{
+		{"channel closed", []int{10, 40, 70}},
+		{"total reached", []int{50, 100, 100, 100}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			p := NewProgress("Processing", 100)
+			p.SetOutput(buf)
+			p.SetInput(strings.NewReader(""))
+			p.Start()
+
+			updates := make(chan int)
+			go func() {
+				for _, v := range tt.values {
+					updates <- v
+				}
+				close(updates)
+			}()
+
+			done := make(chan struct{})
+			go func() {
+				p.follow(updates)
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("follow() did not finish")
+			}
+			if p.current != 100 {
+				t.Errorf("current = %d, want 100", p.current)
+			}
+			if !strings.Contains(buf.String(), "✓ Processing (100%)") {
+				t.Errorf("output should show completion, got: %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestProgressFollowReadsUntilClosed(t *testing.T) {
+	p := NewProgress("Processing", 10)
+	p.SetOutput(new(bytes.Buffer))
+	p.SetInput(strings.NewReader(""))
+	p.Start()
+
+	updates := make(chan int)
+	done := make(chan struct{})
+	go func() {
+		p.follow(updates)
+		close(done)
+	}()
+
+	// Values past the total are still received, then closing returns
+	updates <- 10
+	updates <- 10
+	select {
+	case <-done:
+		t.Fatal("follow() returned before updates was closed")
+	default:
+	}
+	close(updates)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("follow() did not finish after updates was closed")
+	}
+}
+
+func TestSpinnerContextCanceled(t *testing.T) {
+	buf := new(bytes.Buffer)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := New("Syncing")
+	s.SetOutput(buf)
+	err := s.runContext(ctx, func(ctx context.Context) error {
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("runContext() error = %v, want context.Canceled", err)
+	}
+	if got, want := buf.String(), "Syncing\n✗ Syncing: context canceled\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestSpinnerCtrlCInterrupts(t *testing.T) {
+	interrupted := false
+	m := spinnerModel{interrupt: func() { interrupted = true }}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+
+	if !interrupted {
+		t.Error("ctrl+c should call the interrupt func")
+	}
+	if cmd == nil {
+		t.Error("ctrl+c should quit the spinner")
+	}
+}
+
+func TestProgressContextInterrupted(t *testing.T) {
+	p := NewProgress("Uploading", 10)
+	p.SetOutput(new(bytes.Buffer))
+	p.SetInput(strings.NewReader("\x03"))
+
+	done := make(chan error)
+	go func() {
+		done <- p.runContext(context.Background(), func(ctx context.Context, update func()) error {
+			update()
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("runContext() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ctrl+c did not cancel the context")
+	}
+}
+
+func TestProgressContextCompletes(t *testing.T) {
+	buf := new(bytes.Buffer)
+	p := NewProgress("Uploading", 3)
+	p.SetOutput(buf)
+	p.SetInput(strings.NewReader(""))
+
+	err := p.runContext(context.Background(), func(ctx context.Context, update func()) error {
+		update()
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("runContext() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "✓ Uploading (100%)") {
+		t.Errorf("output should show completion, got: %q", buf.String())
+	}
+}
@@ -0,0 +1,73 @@
+package spinner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// clearLine returns the cursor to the start of the line and clears it
+const clearLine = "\r\x1b[K"
+
+// StatusLine is a single line of status text that is rewritten in place, for
+// progress that doesn't need an animation. When the output is not a
+// terminal, each update is printed on its own line; in quiet mode nothing is
+// printed.
+type StatusLine struct {
+	mu     sync.Mutex
+	output io.Writer
+	active bool
+}
+
+// NewStatusLine creates a status line writing to stdout
+func NewStatusLine() *StatusLine {
+	return &StatusLine{output: os.Stdout}
+}
+
+// SetOutput sets the output writer
+func (s *StatusLine) SetOutput(w io.Writer) {
+	s.output = w
+}
+
+// Update replaces the status text
+func (s *StatusLine) Update(msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if Quiet() {
+		return
+	}
+	if !isTerminal(s.output) {
+		fmt.Fprintln(s.output, msg)
+		return
+	}
+	fmt.Fprint(s.output, clearLine+msg)
+	s.active = true
+}
+
+// Done replaces the status text with a success message and ends the line
+func (s *StatusLine) Done(msg string) {
+	s.finish(lipgloss.NewStyle().Foreground(lipgloss.Color("#10B981")).Render("✓ " + msg))
+}
+
+// Fail replaces the status text with err and ends the line
+func (s *StatusLine) Fail(err error) {
+	s.finish(lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444")).Render("✗ " + err.Error()))
+}
+
+func (s *StatusLine) finish(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if Quiet() {
+		return
+	}
+	if s.active {
+		fmt.Fprint(s.output, clearLine)
+		s.active = false
+	}
+	fmt.Fprintln(s.output, line)
+}
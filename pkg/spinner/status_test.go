@@ -0,0 +1,62 @@
+package spinner
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// withTerminal makes every writer look like a terminal for the test
+func withTerminal(t *testing.T) {
+	t.Helper()
+	original := isTerminal
+	isTerminal = func(w io.Writer) bool { return true }
+	t.Cleanup(func() { isTerminal = original })
+}
+
+func TestStatusLineTerminal(t *testing.T) {
+	withTerminal(t)
+	buf := new(bytes.Buffer)
+
+	s := NewStatusLine()
+	s.SetOutput(buf)
+	s.Update("Connecting…")
+	s.Update("Authenticating…")
+	s.Done("Connected")
+
+	want := "\r\x1b[KConnecting…\r\x1b[KAuthenticating…\r\x1b[K✓ Connected\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestStatusLinePlain(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	s := NewStatusLine()
+	s.SetOutput(buf)
+	s.Update("Connecting…")
+	s.Fail(errors.New("connection refused"))
+
+	want := "Connecting…\n✗ connection refused\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestStatusLineQuiet(t *testing.T) {
+	withTerminal(t)
+	SetQuiet(true)
+	t.Cleanup(func() { SetQuiet(false) })
+	buf := new(bytes.Buffer)
+
+	s := NewStatusLine()
+	s.SetOutput(buf)
+	s.Update("Connecting…")
+	s.Done("Connected")
+
+	if buf.Len() != 0 {
+		t.Errorf("quiet mode should print nothing, got: %q", buf.String())
+	}
+}
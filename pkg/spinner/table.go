@@ -0,0 +1,184 @@
+package spinner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StatusTable shows a live table of named rows and their status, such as
+// "running", "done", or "failed". The whole table is redrawn on each change.
+// When the output is not a terminal, each change is printed on its own line;
+// in quiet mode nothing is printed.
+//
+// Example:
+//
+//	table := spinner.NewStatusTable()
+//	table.Set("api", "running")
+//	table.Set("worker", "running")
+//	table.Set("api", "done")
+//	table.Set("worker", "failed")
+//	table.Stop()
+type StatusTable struct {
+	mu      sync.Mutex
+	rows    []statusRow
+	output  io.Writer
+	input   io.Reader
+	program *tea.Program
+	done    chan struct{}
+	started bool
+	stopped bool
+}
+
+type statusRow struct {
+	name   string
+	status string
+}
+
+type tableModel struct {
+	rows []statusRow
+}
+
+type tableRowsMsg struct{ rows []statusRow }
+type tableDoneMsg struct{}
+
+func (m tableModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m tableModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		return m, nil
+	case tableRowsMsg:
+		m.rows = msg.rows
+		return m, nil
+	case tableDoneMsg:
+		return m, tea.Quit
+	default:
+		return m, nil
+	}
+}
+
+func (m tableModel) View() string {
+	width := 0
+	for _, row := range m.rows {
+		width = max(width, lipgloss.Width(row.name))
+	}
+
+	var sb strings.Builder
+	for _, row := range m.rows {
+		sb.WriteString(fmt.Sprintf("%-*s  ", width, row.name))
+		sb.WriteString(statusStyle(row.status).Render(row.status))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// statusStyle colors well-known statuses: green for success, red for
+// failure, and purple for work in progress
+func statusStyle(status string) lipgloss.Style {
+	style := lipgloss.NewStyle()
+	switch strings.ToLower(status) {
+	case "done", "ok", "success", "succeeded", "passed", "completed":
+		return style.Foreground(lipgloss.Color("#10B981"))
+	case "failed", "error", "errored", "canceled", "cancelled":
+		return style.Foreground(lipgloss.Color("#EF4444"))
+	case "running", "pending", "waiting", "in progress":
+		return style.Foreground(lipgloss.Color("#7C3AED"))
+	default:
+		return style.Foreground(lipgloss.Color("#F3F4F6"))
+	}
+}
+
+// NewStatusTable creates an empty status table writing to stdout
+func NewStatusTable() *StatusTable {
+	return &StatusTable{output: os.Stdout}
+}
+
+// SetOutput sets the output writer
+func (t *StatusTable) SetOutput(w io.Writer) {
+	t.output = w
+}
+
+// SetInput sets the reader keyboard input (such as ctrl+c) is read from.
+// By default the terminal is used.
+func (t *StatusTable) SetInput(r io.Reader) {
+	t.input = r
+}
+
+// Set sets the status of the named row, adding the row if it is new
+func (t *StatusTable) Set(name, status string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.stopped {
+		return
+	}
+	if !t.started {
+		t.start()
+	}
+
+	found := false
+	for i := range t.rows {
+		if t.rows[i].name == name {
+			t.rows[i].status = status
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.rows = append(t.rows, statusRow{name: name, status: status})
+	}
+
+	if t.program == nil {
+		fmt.Fprintf(t.output, "%s: %s\n", name, status)
+		return
+	}
+	t.program.Send(tableRowsMsg{rows: append([]statusRow(nil), t.rows...)})
+}
+
+// start launches the program that renders the table on a terminal
+func (t *StatusTable) start() {
+	t.started = true
+	if Quiet() {
+		t.output = io.Discard
+	}
+	if !isTerminal(t.output) {
+		return
+	}
+
+	opts := []tea.ProgramOption{tea.WithOutput(t.output)}
+	if t.input != nil {
+		opts = append(opts, tea.WithInput(t.input))
+	}
+	t.program = tea.NewProgram(tableModel{}, opts...)
+	t.done = make(chan struct{})
+	go func() {
+		t.program.Run()
+		close(t.done)
+	}()
+}
+
+// Stop renders the final table and stops updating it
+func (t *StatusTable) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.stopped {
+		return
+	}
+	t.stopped = true
+	if t.program != nil {
+		t.program.Send(tableDoneMsg{})
+		<-t.done
+	}
+}
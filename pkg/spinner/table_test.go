@@ -0,0 +1,100 @@
+package spinner
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// driveStatusTable moves three rows from running to their final status
+func driveStatusTable(table *StatusTable) {
+	table.Set("api", "running")
+	table.Set("worker", "running")
+	table.Set("migrations", "running")
+	table.Set("api", "done")
+	table.Set("migrations", "done")
+	table.Set("worker", "failed")
+	table.Stop()
+}
+
+func TestStatusTable(t *testing.T) {
+	withTerminal(t)
+	buf := new(bytes.Buffer)
+
+	table := NewStatusTable()
+	table.SetOutput(buf)
+	table.SetInput(strings.NewReader(""))
+	driveStatusTable(table)
+
+	want := []statusRow{{"api", "done"}, {"worker", "failed"}, {"migrations", "done"}}
+	if len(table.rows) != len(want) {
+		t.Fatalf("rows = %v, want %v", table.rows, want)
+	}
+	for i, row := range want {
+		if table.rows[i] != row {
+			t.Errorf("row %d = %v, want %v", i, table.rows[i], row)
+		}
+	}
+
+	output := ansi.Strip(buf.String())
+	for _, line := range []string{"api         done", "worker      failed", "migrations  done"} {
+		if !strings.Contains(output, line) {
+			t.Errorf("output should contain %q, got: %q", line, output)
+		}
+	}
+}
+
+func TestStatusTableNonTerminal(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	table := NewStatusTable()
+	table.SetOutput(buf)
+	driveStatusTable(table)
+	table.Set("api", "running")
+
+	want := strings.Join([]string{
+		"api: running",
+		"worker: running",
+		"migrations: running",
+		"api: done",
+		"migrations: done",
+		"worker: failed",
+	}, "\n") + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestStatusTableQuiet(t *testing.T) {
+	withTerminal(t)
+	SetQuiet(true)
+	t.Cleanup(func() { SetQuiet(false) })
+	buf := new(bytes.Buffer)
+
+	table := NewStatusTable()
+	table.SetOutput(buf)
+	table.SetInput(strings.NewReader(""))
+	driveStatusTable(table)
+
+	if buf.Len() != 0 {
+		t.Errorf("quiet mode should print nothing, got: %q", buf.String())
+	}
+}
+
+func TestStatusTableView(t *testing.T) {
+	m := tableModel{rows: []statusRow{{"a", "done"}, {"long-name", "running"}}}
+
+	got := ansi.Strip(m.View())
+	if want := "a          done\nlong-name  running\n"; got != want {
+		t.Errorf("View() = %q, want %q", got, want)
+	}
+}
+
+func TestStatusTableStopWithoutRows(t *testing.T) {
+	table := NewStatusTable()
+	table.SetOutput(new(bytes.Buffer))
+	table.Stop()
+	table.Stop()
+}
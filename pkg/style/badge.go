@@ -0,0 +1,47 @@
+package style
+
+import "github.com/charmbracelet/lipgloss"
+
+// Badge renders text as a compact label with a background fill, padded by
+// one space on each side. When color is disabled it falls back to "[text]".
+func Badge(text string, fg, bg lipgloss.Color) string {
+	if !ColorEnabled() {
+		return "[" + text + "]"
+	}
+	return lipgloss.NewStyle().
+		Bold(true).
+		Foreground(fg).
+		Background(bg).
+		Padding(0, 1).
+		Render(text)
+}
+
+// SuccessBadge renders text as a badge in the theme's success color
+func SuccessBadge(text string) string {
+	return CurrentTheme().SuccessBadge(text)
+}
+
+// ErrorBadge renders text as a badge in the theme's error color
+func ErrorBadge(text string) string {
+	return CurrentTheme().ErrorBadge(text)
+}
+
+// WarnBadge renders text as a badge in the theme's warning color
+func WarnBadge(text string) string {
+	return CurrentTheme().WarnBadge(text)
+}
+
+// SuccessBadge renders text as a badge in the theme's success color
+func (t Theme) SuccessBadge(text string) string {
+	return Badge(text, t.BackgroundColor, t.SuccessColor)
+}
+
+// ErrorBadge renders text as a badge in the theme's error color
+func (t Theme) ErrorBadge(text string) string {
+	return Badge(text, t.BackgroundColor, t.ErrorColor)
+}
+
+// WarnBadge renders text as a badge in the theme's warning color
+func (t Theme) WarnBadge(text string) string {
+	return Badge(text, t.BackgroundColor, t.WarningColor)
+}
@@ -0,0 +1,51 @@
+package style
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestBadge(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileTrueColor)
+
+	got := Badge("PASS", lipgloss.Color("#000000"), lipgloss.Color("#10B981"))
+	if ansi.Strip(got) != " PASS " {
+		t.Errorf("Badge() = %q, want the label padded by one space", got)
+	}
+	if !strings.Contains(got, "48;2;16;185;129") {
+		t.Errorf("Badge() = %q, want a background color", got)
+	}
+}
+
+func TestSemanticBadges(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileTrueColor)
+
+	tests := []struct {
+		name string
+		got  string
+		bg   string
+	}{
+		{"SuccessBadge", SuccessBadge("PASS"), "48;2;16;185;129"},
+		{"ErrorBadge", ErrorBadge("FAIL"), "48;2;239;68;68"},
+		{"WarnBadge", WarnBadge("SKIP"), "48;2;245;158;11"},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(tt.got, tt.bg) {
+			t.Errorf("%s() = %q, want background %s", tt.name, tt.got, tt.bg)
+		}
+	}
+}
+
+func TestBadgeNoColor(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileASCII)
+
+	if got := ErrorBadge("FAIL"); got != "[FAIL]" {
+		t.Errorf("ErrorBadge() = %q, want %q", got, "[FAIL]")
+	}
+}
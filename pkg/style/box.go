@@ -0,0 +1,52 @@
+package style
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// BoxOptions configures box rendering
+type BoxOptions struct {
+	// Width is the total width of the box, including its border. Content is
+	// wrapped to fit. Zero sizes the box to its content.
+	Width int
+
+	// BorderColor is the border color. Empty uses the theme's primary color.
+	BorderColor lipgloss.Color
+
+	// Padding is the space inside the border, in the same form as
+	// lipgloss.Style.Padding. Nil uses 1 line and 2 columns.
+	Padding []int
+
+	// Align aligns content lines horizontally, such as lipgloss.Center
+	Align lipgloss.Position
+}
+
+// BoxWithOptions renders text in a box using opts
+func BoxWithOptions(title, content string, opts BoxOptions) string {
+	return plain(CurrentTheme().BoxWithOptions(title, content, opts))
+}
+
+// BoxWithOptions renders text in a box using opts
+func (t Theme) BoxWithOptions(title, content string, opts BoxOptions) string {
+	s := t.Styles()
+	box := s.Box.Align(opts.Align)
+	if opts.BorderColor != "" {
+		box = box.BorderForeground(opts.BorderColor)
+	}
+	if opts.Padding != nil {
+		box = box.Padding(opts.Padding...)
+	}
+
+	if title != "" {
+		title = s.Header.Render(title) + "\n\n"
+	}
+	if opts.Width > 0 {
+		// Width includes the padding but not the border
+		inner := opts.Width - box.GetHorizontalBorderSize()
+		limit := max(inner-box.GetHorizontalPadding(), 1)
+		content = ansi.Wrap(content, limit, "")
+		box = box.Width(inner)
+	}
+	return box.Render(title + content)
+}
@@ -0,0 +1,64 @@
+package style
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestBoxWithOptionsWidth(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileTrueColor)
+
+	content := "Deploying " + Colorize("api", "#FF0000") + " to every configured region and waiting for health checks.\nDone."
+	got := BoxWithOptions("", content, BoxOptions{Width: 30})
+
+	lines := strings.Split(got, "\n")
+	for _, line := range lines {
+		if w := lipgloss.Width(line); w != 30 {
+			t.Errorf("line %q is %d columns wide, want 30", ansi.Strip(line), w)
+		}
+	}
+	if len(lines) < 7 {
+		t.Errorf("long content should wrap onto several lines, got:\n%s", got)
+	}
+
+	plain := ansi.Strip(got)
+	for _, word := range []string{"Deploying api to", "health", "Done."} {
+		if !strings.Contains(plain, word) {
+			t.Errorf("box should contain %q, got:\n%s", word, plain)
+		}
+	}
+}
+
+func TestBoxWithOptionsBorderColor(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileTrueColor)
+
+	got := BoxWithOptions("", "hello", BoxOptions{BorderColor: "#FF0000"})
+	if !strings.Contains(got, "38;2;255;0;0m╭") {
+		t.Errorf("box border should use the custom color, got: %q", got)
+	}
+}
+
+func TestBoxWithOptionsPaddingAndAlign(t *testing.T) {
+	got := BoxWithOptions("", "ab\nabcdef", BoxOptions{Padding: []int{0, 1}, Align: lipgloss.Center})
+
+	want := strings.Join([]string{
+		"╭────────╮",
+		"│   ab   │",
+		"│ abcdef │",
+		"╰────────╯",
+	}, "\n")
+	if got != want {
+		t.Errorf("BoxWithOptions() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestBoxDefaults(t *testing.T) {
+	if got, want := Box("Title", "content"), BoxWithOptions("Title", "content", BoxOptions{}); got != want {
+		t.Errorf("Box() = %q, want the BoxWithOptions defaults %q", got, want)
+	}
+}
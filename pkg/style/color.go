@@ -0,0 +1,97 @@
+package style
+
+import (
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+// Color profiles accepted by SetColorProfile
+const (
+	ProfileTrueColor = termenv.TrueColor // 24-bit color
+	ProfileANSI256   = termenv.ANSI256   // 8-bit color
+	ProfileANSI      = termenv.ANSI      // 4-bit color
+	ProfileASCII     = termenv.Ascii     // no color
+)
+
+func init() {
+	applyColorEnv()
+}
+
+// applyColorEnv applies the NO_COLOR and FORCE_COLOR environment variables.
+// NO_COLOR (https://no-color.org) disables color and takes precedence over
+// FORCE_COLOR, which enables color even when output is not a terminal.
+func applyColorEnv() {
+	if os.Getenv("NO_COLOR") != "" {
+		SetColorProfile(ProfileASCII)
+		return
+	}
+
+	if force, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		switch strings.ToLower(strings.TrimSpace(force)) {
+		case "0", "false":
+			SetColorProfile(ProfileASCII)
+		case "1":
+			SetColorProfile(ProfileANSI)
+		case "2":
+			SetColorProfile(ProfileANSI256)
+		default:
+			SetColorProfile(ProfileTrueColor)
+		}
+	}
+}
+
+// colorForced is set once the color profile is chosen by SetColorProfile or
+// FORCE_COLOR rather than detected
+var colorForced atomic.Bool
+
+// SetColorProfile overrides the detected color profile used by all render
+// functions. Use ProfileASCII to disable color entirely.
+func SetColorProfile(p termenv.Profile) {
+	lipgloss.SetColorProfile(p)
+	colorForced.Store(true)
+}
+
+// ColorForced reports whether color was turned on with SetColorProfile or
+// FORCE_COLOR, so styled output keeps its colors even when it is not written
+// to a terminal
+func ColorForced() bool {
+	return colorForced.Load() && ColorProfile() != ProfileASCII
+}
+
+// ColorProfile returns the active color profile
+func ColorProfile() termenv.Profile {
+	return lipgloss.ColorProfile()
+}
+
+// ColorEnabled reports whether render functions emit ANSI escape codes
+func ColorEnabled() bool {
+	return Styling() && ColorProfile() != ProfileASCII
+}
+
+// stylingDisabled is set by SetStyling(false)
+var stylingDisabled atomic.Bool
+
+// SetStyling turns styling on or off for all package-level render functions.
+// While it is off they return plain text whatever the color profile, which
+// suits tests and log output.
+func SetStyling(enabled bool) {
+	stylingDisabled.Store(!enabled)
+}
+
+// Styling reports whether styling is enabled
+func Styling() bool {
+	return !stylingDisabled.Load()
+}
+
+// plain returns s without ANSI escape codes when styling is disabled
+func plain(s string) string {
+	if Styling() {
+		return s
+	}
+	return ansi.Strip(s)
+}
@@ -0,0 +1,98 @@
+package style
+
+import (
+	"strings"
+	"testing"
+)
+
+// withColorProfile restores the active color profile when the test finishes
+func withColorProfile(t *testing.T) {
+	t.Helper()
+	original := ColorProfile()
+	t.Cleanup(func() {
+		SetColorProfile(original)
+	})
+}
+
+func TestNoColorEnv(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileTrueColor)
+	t.Setenv("NO_COLOR", "1")
+	t.Setenv("FORCE_COLOR", "1")
+
+	applyColorEnv()
+
+	result := Success("done")
+	if strings.Contains(result, "\x1b[") {
+		t.Errorf("Success() should not contain ANSI codes with NO_COLOR set, got: %q", result)
+	}
+	if !strings.Contains(result, SuccessIcon+" done") {
+		t.Errorf("Success() should keep the icon and message, got: %q", result)
+	}
+	if ColorEnabled() {
+		t.Error("ColorEnabled() should be false with NO_COLOR set")
+	}
+}
+
+func TestForceColorEnv(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileASCII)
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "1")
+
+	applyColorEnv()
+
+	if result := Error("failed"); !strings.Contains(result, "\x1b[") {
+		t.Errorf("Error() should contain ANSI codes with FORCE_COLOR set, got: %q", result)
+	}
+	if ColorProfile() != ProfileANSI {
+		t.Errorf("FORCE_COLOR=1 should select the ANSI profile, got %v", ColorProfile())
+	}
+	if !ColorForced() {
+		t.Error("ColorForced() should be true with FORCE_COLOR set")
+	}
+}
+
+func TestSetColorProfile(t *testing.T) {
+	withColorProfile(t)
+
+	SetColorProfile(ProfileASCII)
+	if result := Warning("careful"); strings.Contains(result, "\x1b[") {
+		t.Errorf("Warning() should be plain with the ASCII profile, got: %q", result)
+	}
+	if ColorForced() {
+		t.Error("ColorForced() should be false with the ASCII profile")
+	}
+
+	SetColorProfile(ProfileTrueColor)
+	if result := Warning("careful"); !strings.Contains(result, "\x1b[") {
+		t.Errorf("Warning() should be styled with the TrueColor profile, got: %q", result)
+	}
+}
+
+func TestSetStyling(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileTrueColor)
+	SetStyling(false)
+	t.Cleanup(func() { SetStyling(true) })
+
+	for name, got := range map[string]string{
+		"Success":  Success("done"),
+		"Bold":     Bold("done"),
+		"Colorize": Colorize("done", "#FF0000"),
+		"Box":      Box("Title", "done"),
+		"Gradient": Gradient("done", "#FF0000", "#0000FF"),
+	} {
+		if strings.Contains(got, "\x1b") || !strings.Contains(got, "done") {
+			t.Errorf("%s() should be plain with styling disabled, got: %q", name, got)
+		}
+	}
+	if ColorEnabled() {
+		t.Error("ColorEnabled() should be false with styling disabled")
+	}
+
+	SetStyling(true)
+	if !strings.Contains(Bold("done"), "\x1b[") {
+		t.Errorf("Bold() should be styled with styling enabled, got: %q", Bold("done"))
+	}
+}
@@ -0,0 +1,48 @@
+package style
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// columnGap is the space between columns
+const columnGap = 2
+
+// Columns lays items out in as many columns as fit in width, filling each
+// column top to bottom like ls. Widths are measured in terminal cells, so
+// wide runes and styled items line up. An item wider than width gets a line
+// of its own.
+func Columns(items []string, width int) string {
+	if len(items) == 0 {
+		return ""
+	}
+
+	cellWidth := 0
+	for _, item := range items {
+		cellWidth = max(cellWidth, ansi.StringWidth(item))
+	}
+
+	cols := max((width+columnGap)/(cellWidth+columnGap), 1)
+	rows := (len(items) + cols - 1) / cols
+	// Spread the items evenly, so the last column is not nearly empty
+	cols = (len(items) + rows - 1) / rows
+
+	lines := make([]string, rows)
+	for r := range lines {
+		var line strings.Builder
+		for c := 0; c < cols; c++ {
+			i := c*rows + r
+			if i >= len(items) {
+				break
+			}
+			if c > 0 {
+				line.WriteString(strings.Repeat(" ", columnGap))
+			}
+			line.WriteString(items[i])
+			line.WriteString(strings.Repeat(" ", cellWidth-ansi.StringWidth(items[i])))
+		}
+		lines[r] = strings.TrimRight(line.String(), " ")
+	}
+	return strings.Join(lines, "\n")
+}
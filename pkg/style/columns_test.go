@@ -0,0 +1,42 @@
+package style
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestColumns(t *testing.T) {
+	items := []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf"}
+
+	// Cells are 7 wide with a gap of 2, so 30 columns fit 3 of them
+	got := Columns(items, 30)
+	want := strings.Join([]string{
+		"alpha    delta    golf",
+		"bravo    echo",
+		"charlie  foxtrot",
+	}, "\n")
+	if got != want {
+		t.Errorf("Columns() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestColumnsWideRunes(t *testing.T) {
+	got := Columns([]string{"日本", "abcd", "ok", "x"}, 10)
+	want := strings.Join([]string{
+		"日本  ok",
+		"abcd  x",
+	}, "\n")
+	if got != want {
+		t.Errorf("Columns() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestColumnsOverWide(t *testing.T) {
+	items := []string{"a-very-long-item-name", "b", "c"}
+	if got, want := Columns(items, 10), "a-very-long-item-name\nb\nc"; got != want {
+		t.Errorf("Columns() = %q, want one item per line %q", got, want)
+	}
+	if got := Columns(nil, 80); got != "" {
+		t.Errorf("Columns(nil) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,93 @@
+package style
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Diff renders a line-based diff from oldText to newText in a box, with
+// added lines prefixed by "+" in green and removed lines by "-" in red
+func Diff(oldText, newText string) string {
+	return plain(CurrentTheme().Diff(oldText, newText))
+}
+
+// Diff renders a line-based diff from oldText to newText in a box, with
+// added lines prefixed by "+" in green and removed lines by "-" in red
+func (t Theme) Diff(oldText, newText string) string {
+	s := t.Styles()
+	added := lipgloss.NewStyle().Foreground(t.SuccessColor)
+	removed := lipgloss.NewStyle().Foreground(t.ErrorColor)
+
+	lines := diffLines(splitLines(oldText), splitLines(newText))
+	rendered := make([]string, len(lines))
+	for i, l := range lines {
+		switch l.op {
+		case '+':
+			rendered[i] = added.Render("+ " + l.text)
+		case '-':
+			rendered[i] = removed.Render("- " + l.text)
+		default:
+			rendered[i] = s.Dim.Render("  " + l.text)
+		}
+	}
+	return s.Box.Render(strings.Join(rendered, "\n"))
+}
+
+// diffLine is a line of a diff; op is '+' for added, '-' for removed, or
+// ' ' for unchanged
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// diffLines returns the diff from a to b based on their longest common
+// subsequence of lines. Removed lines come before added ones in each change.
+func diffLines(a, b []string) []diffLine {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{'+', b[j]})
+	}
+	return lines
+}
+
+// splitLines splits text into lines, ignoring a trailing newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,68 @@
+package style
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     []string
+	}{
+		{"changed line", "a\nb\nc\n", "a\nB\nc\n", []string{"  a", "- b", "+ B", "  c"}},
+		{"added lines", "a\n", "a\nb\nc", []string{"  a", "+ b", "+ c"}},
+		{"removed line", "a\nb\nc", "a\nc", []string{"  a", "- b", "  c"}},
+		{"from empty", "", "a", []string{"+ a"}},
+		{"to empty", "a\nb", "", []string{"- a", "- b"}},
+		{"unchanged", "a\nb", "a\nb\n", []string{"  a", "  b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, l := range diffLines(splitLines(tt.old), splitLines(tt.new)) {
+				got = append(got, string(l.op)+" "+l.text)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("diffLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileTrueColor)
+
+	theme := DefaultTheme()
+	got := theme.Diff("port: 80\nhost: a\n", "port: 8080\nhost: a\n")
+
+	plain := ansi.Strip(got)
+	for _, want := range []string{"- port: 80", "+ port: 8080", "  host: a", "╭"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("Diff() should contain %q, got:\n%s", want, plain)
+		}
+	}
+	if !strings.Contains(got, Colorize("+ port: 8080", theme.SuccessColor)) {
+		t.Errorf("Diff() should render added lines in the success color, got: %q", got)
+	}
+	if !strings.Contains(got, Colorize("- port: 80", theme.ErrorColor)) {
+		t.Errorf("Diff() should render removed lines in the error color, got: %q", got)
+	}
+}
+
+func TestDiffPlain(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileASCII)
+
+	got := Diff("a", "b")
+	if strings.Contains(got, "\x1b") {
+		t.Errorf("Diff() without color should not contain escape codes, got: %q", got)
+	}
+	if !strings.Contains(got, "- a") || !strings.Contains(got, "+ b") {
+		t.Errorf("Diff() should mark changed lines, got:\n%s", got)
+	}
+}
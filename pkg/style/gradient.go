@@ -0,0 +1,76 @@
+package style
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Gradient renders text with a foreground color that fades from one color to
+// another across its runes. Colors must be hex, like "#FF5F87"; otherwise
+// the whole text is rendered in from. It returns text as is when color is
+// disabled.
+func Gradient(text string, from, to lipgloss.Color) string {
+	if !Styling() {
+		return text
+	}
+	return gradient(text, from, to)
+}
+
+// Gradient renders text fading from the theme's primary to its secondary
+// color. Unlike the package-level Gradient it ignores SetStyling, for callers
+// that decide on styling themselves.
+func (t Theme) Gradient(text string) string {
+	return gradient(text, t.PrimaryColor, t.SecondaryColor)
+}
+
+// gradient implements Gradient for the active color profile
+func gradient(text string, from, to lipgloss.Color) string {
+	if ColorProfile() == ProfileASCII || text == "" {
+		return text
+	}
+
+	r1, g1, b1, ok1 := parseHex(from)
+	r2, g2, b2, ok2 := parseHex(to)
+	if !ok1 || !ok2 {
+		return lipgloss.NewStyle().Foreground(from).Render(text)
+	}
+
+	runes := []rune(text)
+	var sb strings.Builder
+	for i, r := range runes {
+		t := 0.0
+		if len(runes) > 1 {
+			t = float64(i) / float64(len(runes)-1)
+		}
+		color := lipgloss.Color(fmt.Sprintf("#%02X%02X%02X", lerp(r1, r2, t), lerp(g1, g2, t), lerp(b1, b2, t)))
+		sb.WriteString(lipgloss.NewStyle().Foreground(color).Render(string(r)))
+	}
+	return sb.String()
+}
+
+// parseHex returns the components of a "#RGB" or "#RRGGBB" color
+func parseHex(c lipgloss.Color) (r, g, b uint8, ok bool) {
+	s, found := strings.CutPrefix(string(c), "#")
+	if !found {
+		return 0, 0, 0, false
+	}
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return uint8(v >> 16), uint8(v >> 8), uint8(v), true
+}
+
+// lerp interpolates between a and b, where t runs from 0 to 1
+func lerp(a, b uint8, t float64) uint8 {
+	return uint8(float64(a) + (float64(b)-float64(a))*t + 0.5)
+}
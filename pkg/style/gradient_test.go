@@ -0,0 +1,85 @@
+package style
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var foregroundCode = regexp.MustCompile(`38;2;(\d+;\d+;\d+)m`)
+
+func TestGradient(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileTrueColor)
+
+	got := Gradient("héllo→", lipgloss.Color("#FF0000"), lipgloss.Color("#0000FF"))
+
+	if stripped := ansi.Strip(got); stripped != "héllo→" {
+		t.Errorf("Gradient() text = %q, want %q", stripped, "héllo→")
+	}
+	codes := foregroundCode.FindAllStringSubmatch(got, -1)
+	if len(codes) != 6 {
+		t.Fatalf("Gradient() should color each of the 6 runes, got %d codes in %q", len(codes), got)
+	}
+	if codes[0][1] != "255;0;0" {
+		t.Errorf("first rune color = %s, want 255;0;0", codes[0][1])
+	}
+	if codes[5][1] != "0;0;255" {
+		t.Errorf("last rune color = %s, want 0;0;255", codes[5][1])
+	}
+	for i := 1; i < len(codes); i++ {
+		if codes[i][1] == codes[i-1][1] {
+			t.Errorf("runes %d and %d share color %s, want a gradient", i-1, i, codes[i][1])
+		}
+	}
+}
+
+func TestGradientShortText(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileTrueColor)
+
+	if got := Gradient("", "#FF0000", "#0000FF"); got != "" {
+		t.Errorf("Gradient(\"\") = %q, want empty", got)
+	}
+	got := Gradient("x", "#F00", "#00F")
+	if codes := foregroundCode.FindAllStringSubmatch(got, -1); len(codes) != 1 || codes[0][1] != "255;0;0" {
+		t.Errorf("Gradient() of one rune should use the start color, got %q", got)
+	}
+}
+
+func TestGradientNonHexColor(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileTrueColor)
+
+	got := Gradient("abc", lipgloss.Color("205"), lipgloss.Color("#0000FF"))
+	if want := Colorize("abc", lipgloss.Color("205")); got != want {
+		t.Errorf("Gradient() with a non-hex color = %q, want %q", got, want)
+	}
+}
+
+func TestGradientPlain(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileASCII)
+
+	got := Gradient("Mamba", "#FF0000", "#0000FF")
+	if got != "Mamba" || strings.Contains(got, "\x1b") {
+		t.Errorf("Gradient() without color = %q, want %q", got, "Mamba")
+	}
+}
+
+func TestThemeGradientIgnoresStyling(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileTrueColor)
+	SetStyling(false)
+	t.Cleanup(func() { SetStyling(true) })
+
+	if got := Gradient("Mamba", "#FF0000", "#0000FF"); got != "Mamba" {
+		t.Errorf("Gradient() with styling disabled = %q, want plain text", got)
+	}
+	if got := DefaultTheme().Gradient("Mamba"); !strings.Contains(got, "\x1b[") {
+		t.Errorf("Theme.Gradient() should stay colored with styling disabled, got: %q", got)
+	}
+}
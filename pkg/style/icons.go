@@ -0,0 +1,93 @@
+package style
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// unicodeEnabled reports whether icons may use Unicode glyphs
+var unicodeEnabled atomic.Bool
+
+func init() {
+	unicodeEnabled.Store(detectUnicode())
+}
+
+// detectUnicode reports whether the terminal likely renders Unicode. Windows
+// consoles other than Windows Terminal and non-UTF-8 locales do not.
+func detectUnicode() bool {
+	if runtime.GOOS == "windows" {
+		return os.Getenv("WT_SESSION") != ""
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return true
+}
+
+// SetUnicode overrides whether icons use Unicode glyphs. When disabled, the
+// render functions fall back to ASCIIIcons for any non-ASCII theme icon.
+func SetUnicode(enabled bool) {
+	unicodeEnabled.Store(enabled)
+}
+
+// Unicode reports whether icons use Unicode glyphs
+func Unicode() bool {
+	return unicodeEnabled.Load()
+}
+
+// ASCIIIcons returns the icons used when Unicode is disabled
+func ASCIIIcons() Icons {
+	return Icons{
+		Success:  "[OK]",
+		Error:    "[ERR]",
+		Warning:  "[!]",
+		Info:     "[i]",
+		Question: "?",
+		Arrow:    "->",
+		Bullet:   "-",
+		Check:    "+",
+		Cross:    "x",
+	}
+}
+
+// icons returns the theme icons, with non-ASCII glyphs replaced by their
+// ASCII fallback when Unicode is disabled
+func (t Theme) icons() Icons {
+	if Unicode() {
+		return t.Icons
+	}
+
+	icons, fallback := t.Icons, ASCIIIcons()
+	for _, pair := range []struct{ icon, ascii *string }{
+		{&icons.Success, &fallback.Success},
+		{&icons.Error, &fallback.Error},
+		{&icons.Warning, &fallback.Warning},
+		{&icons.Info, &fallback.Info},
+		{&icons.Question, &fallback.Question},
+		{&icons.Arrow, &fallback.Arrow},
+		{&icons.Bullet, &fallback.Bullet},
+		{&icons.Check, &fallback.Check},
+		{&icons.Cross, &fallback.Cross},
+	} {
+		if !isASCII(*pair.icon) {
+			*pair.icon = *pair.ascii
+		}
+	}
+	return icons
+}
+
+// isASCII reports whether s contains only ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package style
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// withUnicode sets the Unicode mode for the test and restores it afterwards
+func withUnicode(t *testing.T, enabled bool) {
+	t.Helper()
+	original := Unicode()
+	SetUnicode(enabled)
+	t.Cleanup(func() { SetUnicode(original) })
+}
+
+func TestASCIIIconFallback(t *testing.T) {
+	withUnicode(t, false)
+
+	tests := []struct {
+		name     string
+		got      string
+		want     string
+		unwanted string
+	}{
+		{"Success", Success("done"), "[OK] done", SuccessIcon},
+		{"Error", Error("failed"), "[ERR] failed", ErrorIcon},
+		{"Warning", Warning("careful"), "[!] careful", WarningIcon},
+		{"Info", Info("note"), "[i] note", InfoIcon},
+		{"Bullet", Bullet("item"), "- ", BulletIcon},
+		{"Prompt", Prompt("Name"), "Name -> ", ArrowIcon},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(tt.got, tt.want) {
+			t.Errorf("%s() = %q, want it to contain %q", tt.name, tt.got, tt.want)
+		}
+		if strings.Contains(tt.got, tt.unwanted) {
+			t.Errorf("%s() = %q, should not contain %q", tt.name, tt.got, tt.unwanted)
+		}
+	}
+}
+
+func TestUnicodeIcons(t *testing.T) {
+	withUnicode(t, true)
+
+	if got := Success("done"); !strings.Contains(got, SuccessIcon+" done") {
+		t.Errorf("Success() = %q, want the Unicode icon", got)
+	}
+}
+
+func TestASCIIFallbackKeepsASCIIThemeIcons(t *testing.T) {
+	withUnicode(t, false)
+
+	theme := DefaultTheme()
+	theme.Icons.Success = "OK:"
+	theme.Icons.Bullet = "★"
+
+	if got := theme.Success("done"); !strings.Contains(got, "OK: done") {
+		t.Errorf("Success() = %q, want the custom ASCII icon", got)
+	}
+	if got := theme.Bullet("item"); !strings.HasPrefix(got, "- ") || strings.Contains(got, "★") {
+		t.Errorf("Bullet() = %q, want the ASCII fallback for a Unicode icon", got)
+	}
+}
+
+func TestDetectUnicode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("locale detection does not apply on Windows")
+	}
+
+	tests := []struct {
+		lcAll, lang string
+		want        bool
+	}{
+		{"", "", true},
+		{"", "en_US.UTF-8", true},
+		{"", "de_DE.utf8", true},
+		{"", "C", false},
+		{"", "en_US.ISO-8859-1", false},
+		{"C", "en_US.UTF-8", false},
+		{"en_US.UTF-8", "C", true},
+	}
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_CTYPE", "")
+		t.Setenv("LANG", tt.lang)
+
+		if got := detectUnicode(); got != tt.want {
+			t.Errorf("detectUnicode() with LC_ALL=%q LANG=%q = %v, want %v", tt.lcAll, tt.lang, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package style
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// KeyValue is one line of a KeyValues listing
+type KeyValue struct {
+	Key   string
+	Value string
+}
+
+// KeyValues renders pairs as "key: value" lines with the keys right-aligned,
+// so the colons and values line up
+func KeyValues(pairs []KeyValue) string {
+	return plain(CurrentTheme().KeyValues(pairs))
+}
+
+// KeyValues renders pairs as "key: value" lines with the keys right-aligned
+// in the sub-header style. Continuation lines of multi-line values are
+// indented under the value column.
+func (t Theme) KeyValues(pairs []KeyValue) string {
+	width := 0
+	for _, p := range pairs {
+		width = max(width, ansi.StringWidth(p.Key))
+	}
+
+	key := t.Styles().SubHeader
+	markers := make([]string, len(pairs))
+	values := make([]string, len(pairs))
+	for i, p := range pairs {
+		markers[i] = strings.Repeat(" ", width-ansi.StringWidth(p.Key)) + key.Render(p.Key+":")
+		values[i] = p.Value
+	}
+	return listLines(markers, values)
+}
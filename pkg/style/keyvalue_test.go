@@ -0,0 +1,49 @@
+package style
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeyValues(t *testing.T) {
+	got := KeyValues([]KeyValue{
+		{Key: "Name", Value: "api"},
+		{Key: "Status", Value: "Running"},
+		{Key: "Image", Value: "api:1.4.2"},
+	})
+
+	want := strings.Join([]string{
+		"  Name: api",
+		"Status: Running",
+		" Image: api:1.4.2",
+	}, "\n")
+	if got != want {
+		t.Errorf("KeyValues() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestKeyValuesMultiLine(t *testing.T) {
+	got := KeyValues([]KeyValue{
+		{Key: "Name", Value: "api"},
+		{Key: "Events", Value: "Pulled image\nStarted container"},
+	})
+
+	want := strings.Join([]string{
+		"  Name: api",
+		"Events: Pulled image",
+		"        Started container",
+	}, "\n")
+	if got != want {
+		t.Errorf("KeyValues() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestKeyValuesStyled(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileTrueColor)
+
+	got := KeyValues([]KeyValue{{Key: "Name", Value: "api"}})
+	if !strings.HasPrefix(got, "\x1b[") || !strings.HasSuffix(got, " api") {
+		t.Errorf("KeyValues() should style the key and leave the value plain, got: %q", got)
+	}
+}
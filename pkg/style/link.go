@@ -0,0 +1,24 @@
+package style
+
+import "github.com/charmbracelet/x/ansi"
+
+// Link renders text as a clickable OSC 8 hyperlink to url. When color is
+// disabled it falls back to PlainLink.
+func Link(text, url string) string {
+	if !ColorEnabled() {
+		return PlainLink(text, url)
+	}
+	if text == "" {
+		text = url
+	}
+	return ansi.SetHyperlink(url) + text + ansi.ResetHyperlink()
+}
+
+// PlainLink renders a link as "text (url)", or just url if text is empty or
+// the same as url
+func PlainLink(text, url string) string {
+	if text == "" || text == url {
+		return url
+	}
+	return text + " (" + url + ")"
+}
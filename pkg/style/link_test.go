@@ -0,0 +1,39 @@
+package style
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLink(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileTrueColor)
+
+	got := Link("docs", "https://example.com/docs")
+
+	if want := "\x1b]8;;https://example.com/docs\x07docs\x1b]8;;\x07"; got != want {
+		t.Errorf("Link() = %q, want %q", got, want)
+	}
+}
+
+func TestLinkPlain(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileASCII)
+
+	tests := []struct {
+		text, url, want string
+	}{
+		{"docs", "https://example.com/docs", "docs (https://example.com/docs)"},
+		{"", "https://example.com", "https://example.com"},
+		{"https://example.com", "https://example.com", "https://example.com"},
+	}
+	for _, tt := range tests {
+		got := Link(tt.text, tt.url)
+		if got != tt.want {
+			t.Errorf("Link(%q, %q) = %q, want %q", tt.text, tt.url, got, tt.want)
+		}
+		if strings.Contains(got, "\x1b") {
+			t.Errorf("Link() should not emit escape sequences in plain mode, got: %q", got)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package style
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// CheckItem is an entry of a CheckList
+type CheckItem struct {
+	Text string
+	Done bool
+}
+
+// NumberedList renders items as an ordered list starting at 1
+func NumberedList(items []string) string {
+	return plain(CurrentTheme().NumberedList(items))
+}
+
+// CheckList renders items with a checkmark when done and an empty box
+// otherwise, or [x] and [ ] when Unicode is disabled
+func CheckList(items []CheckItem) string {
+	return plain(CurrentTheme().CheckList(items))
+}
+
+// NumberedList renders items as an ordered list starting at 1, with the
+// numbers right-aligned
+func (t Theme) NumberedList(items []string) string {
+	s := t.Styles()
+	width := len(strconv.Itoa(len(items))) + 1 // digits + "."
+
+	markers := make([]string, len(items))
+	for i := range items {
+		markers[i] = s.Bullet.Render(strings.Repeat(" ", width-len(strconv.Itoa(i+1))-1) + strconv.Itoa(i+1) + ".")
+	}
+	return listLines(markers, items)
+}
+
+// CheckList renders items with a checkmark in the success color when done
+// and a muted empty box otherwise
+func (t Theme) CheckList(items []CheckItem) string {
+	done := lipgloss.NewStyle().Foreground(t.SuccessColor).Bold(true)
+	pending := lipgloss.NewStyle().Foreground(t.MutedColor)
+	doneMark, pendingMark := t.icons().Check, "☐"
+	if !Unicode() {
+		doneMark, pendingMark = "[x]", "[ ]"
+	}
+
+	markers := make([]string, len(items))
+	texts := make([]string, len(items))
+	for i, item := range items {
+		if item.Done {
+			markers[i] = done.Render(doneMark)
+		} else {
+			markers[i] = pending.Render(pendingMark)
+		}
+		texts[i] = item.Text
+	}
+	return listLines(markers, texts)
+}
+
+// listLines joins each marker and item, padding the markers to the same
+// width and indenting the continuation lines of multi-line items to match
+func listLines(markers, items []string) string {
+	width := 0
+	for _, m := range markers {
+		width = max(width, ansi.StringWidth(m))
+	}
+	indent := "\n" + strings.Repeat(" ", width+1)
+
+	lines := make([]string, len(items))
+	for i, item := range items {
+		pad := strings.Repeat(" ", width-ansi.StringWidth(markers[i]))
+		lines[i] = markers[i] + pad + " " + strings.ReplaceAll(item, "\n", indent)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,45 @@
+package style
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNumberedList(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileASCII)
+
+	items := make([]string, 10)
+	for i := range items {
+		items[i] = "step"
+	}
+	items[1] = "build\nand test"
+
+	lines := strings.Split(NumberedList(items), "\n")
+	if lines[0] != " 1. step" {
+		t.Errorf("first line = %q, want numbering to start at 1", lines[0])
+	}
+	if lines[1] != " 2. build" || lines[2] != "    and test" {
+		t.Errorf("wrapped item = %q, %q, want continuation indented under the text", lines[1], lines[2])
+	}
+	if lines[10] != "10. step" {
+		t.Errorf("last line = %q, want %q", lines[10], "10. step")
+	}
+}
+
+func TestCheckList(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileASCII)
+	items := []CheckItem{{Text: "Build", Done: true}, {Text: "Deploy"}}
+
+	withUnicode(t, true)
+	want := CurrentTheme().Icons.Check + " Build\n☐ Deploy"
+	if got := CheckList(items); got != want {
+		t.Errorf("CheckList() = %q, want %q", got, want)
+	}
+
+	withUnicode(t, false)
+	if got, want := CheckList(items), "[x] Build\n[ ] Deploy"; got != want {
+		t.Errorf("CheckList() without Unicode = %q, want %q", got, want)
+	}
+}
@@ -4,26 +4,26 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-// Theme colors
+// Theme colors. These reflect the active theme and are updated by SetTheme.
 var (
 	// Primary colors
-	PrimaryColor   = lipgloss.Color("#7C3AED") // Purple
-	SecondaryColor = lipgloss.Color("#06B6D4") // Cyan
-	AccentColor    = lipgloss.Color("#F59E0B") // Amber
+	PrimaryColor   lipgloss.Color
+	SecondaryColor lipgloss.Color
+	AccentColor    lipgloss.Color
 
 	// Status colors
-	SuccessColor = lipgloss.Color("#10B981") // Green
-	ErrorColor   = lipgloss.Color("#EF4444") // Red
-	WarningColor = lipgloss.Color("#F59E0B") // Amber
-	InfoColor    = lipgloss.Color("#3B82F6") // Blue
+	SuccessColor lipgloss.Color
+	ErrorColor   lipgloss.Color
+	WarningColor lipgloss.Color
+	InfoColor    lipgloss.Color
 
 	// Text colors
-	TextColor       = lipgloss.Color("#F3F4F6") // Light gray
-	MutedColor      = lipgloss.Color("#9CA3AF") // Gray
-	HighlightColor  = lipgloss.Color("#FBBF24") // Yellow
-	DimColor        = lipgloss.Color("#6B7280") // Dark gray
-	SubtleColor     = lipgloss.Color("#4B5563") // Darker gray
-	BrightTextColor = lipgloss.Color("#FFFFFF") // White
+	TextColor       lipgloss.Color
+	MutedColor      lipgloss.Color
+	HighlightColor  lipgloss.Color
+	DimColor        lipgloss.Color
+	SubtleColor     lipgloss.Color
+	BrightTextColor lipgloss.Color
 )
 
 // Base styles
@@ -32,84 +32,85 @@ var (
 	BoldStyle      = lipgloss.NewStyle().Bold(true)
 	ItalicStyle    = lipgloss.NewStyle().Italic(true)
 	UnderlineStyle = lipgloss.NewStyle().Underline(true)
-	DimStyle       = lipgloss.NewStyle().Foreground(DimColor)
-	MutedStyle     = lipgloss.NewStyle().Foreground(MutedColor)
+)
 
-	// Header styles
-	HeaderStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(PrimaryColor).
-			MarginBottom(1)
+// Themed styles. These reflect the active theme and are updated by SetTheme.
+var (
+	DimStyle   lipgloss.Style
+	MutedStyle lipgloss.Style
 
-	SubHeaderStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(SecondaryColor)
+	// Header styles
+	HeaderStyle    lipgloss.Style
+	SubHeaderStyle lipgloss.Style
 
 	// Status styles
-	SuccessStyle = lipgloss.NewStyle().
-			Foreground(SuccessColor).
-			Bold(true)
-
-	ErrorStyle = lipgloss.NewStyle().
-			Foreground(ErrorColor).
-			Bold(true)
-
-	WarningStyle = lipgloss.NewStyle().
-			Foreground(WarningColor).
-			Bold(true)
-
-	InfoStyle = lipgloss.NewStyle().
-			Foreground(InfoColor).
-			Bold(true)
+	SuccessStyle lipgloss.Style
+	ErrorStyle   lipgloss.Style
+	WarningStyle lipgloss.Style
+	InfoStyle    lipgloss.Style
 
 	// Box styles
-	BoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(PrimaryColor).
-			Padding(1, 2)
-
-	HighlightBoxStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(AccentColor).
-				Padding(1, 2).
-				Background(lipgloss.Color("#1F2937"))
+	BoxStyle          lipgloss.Style
+	HighlightBoxStyle lipgloss.Style
 
 	// Command styles
-	CommandStyle = lipgloss.NewStyle().
-			Foreground(AccentColor).
-			Bold(true)
-
-	FlagStyle = lipgloss.NewStyle().
-			Foreground(InfoColor)
-
-	ArgumentStyle = lipgloss.NewStyle().
-			Foreground(HighlightColor).
-			Italic(true)
+	CommandStyle  lipgloss.Style
+	FlagStyle     lipgloss.Style
+	ArgumentStyle lipgloss.Style
 
 	// List styles
-	BulletStyle = lipgloss.NewStyle().
-			Foreground(PrimaryColor).
-			Bold(true)
-
-	ListItemStyle = lipgloss.NewStyle().
-			Foreground(TextColor).
-			PaddingLeft(2)
+	BulletStyle   lipgloss.Style
+	ListItemStyle lipgloss.Style
 
 	// Code/technical styles
-	CodeStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#A78BFA")).
-			Background(lipgloss.Color("#1F2937")).
-			Padding(0, 1)
+	CodeStyle lipgloss.Style
 
 	// Prompt styles
-	PromptStyle = lipgloss.NewStyle().
-			Foreground(PrimaryColor).
-			Bold(true)
-
-	InputStyle = lipgloss.NewStyle().
-			Foreground(AccentColor)
+	PromptStyle lipgloss.Style
+	InputStyle  lipgloss.Style
 )
 
+func init() {
+	applyTheme(CurrentTheme())
+}
+
+// applyTheme updates the exported color and style variables from t
+func applyTheme(t Theme) {
+	PrimaryColor = t.PrimaryColor
+	SecondaryColor = t.SecondaryColor
+	AccentColor = t.AccentColor
+	SuccessColor = t.SuccessColor
+	ErrorColor = t.ErrorColor
+	WarningColor = t.WarningColor
+	InfoColor = t.InfoColor
+	TextColor = t.TextColor
+	MutedColor = t.MutedColor
+	HighlightColor = t.HighlightColor
+	DimColor = t.DimColor
+	SubtleColor = t.SubtleColor
+	BrightTextColor = t.BrightTextColor
+
+	s := t.Styles()
+	DimStyle = s.Dim
+	MutedStyle = s.Muted
+	HeaderStyle = s.Header
+	SubHeaderStyle = s.SubHeader
+	SuccessStyle = s.Success
+	ErrorStyle = s.Error
+	WarningStyle = s.Warning
+	InfoStyle = s.Info
+	BoxStyle = s.Box
+	HighlightBoxStyle = s.HighlightBox
+	CommandStyle = s.Command
+	FlagStyle = s.Flag
+	ArgumentStyle = s.Argument
+	BulletStyle = s.Bullet
+	ListItemStyle = s.ListItem
+	CodeStyle = s.Code
+	PromptStyle = s.Prompt
+	InputStyle = s.Input
+}
+
 // Status icons
 const (
 	SuccessIcon  = "✓"
@@ -124,122 +125,116 @@ const (
 )
 
 // Render functions
+//
+// The themed render functions below use the active theme (see SetTheme).
+// They return plain text while styling is disabled (see SetStyling).
 
 // Success renders a success message
 func Success(msg string) string {
-	return SuccessStyle.Render(SuccessIcon+" ") + SuccessStyle.Render(msg)
+	return plain(CurrentTheme().Success(msg))
 }
 
 // Error renders an error message
 func Error(msg string) string {
-	return ErrorStyle.Render(ErrorIcon+" ") + ErrorStyle.Render(msg)
+	return plain(CurrentTheme().Error(msg))
 }
 
 // Warning renders a warning message
 func Warning(msg string) string {
-	return WarningStyle.Render(WarningIcon+" ") + WarningStyle.Render(msg)
+	return plain(CurrentTheme().Warning(msg))
 }
 
 // Info renders an info message
 func Info(msg string) string {
-	return InfoStyle.Render(InfoIcon+" ") + InfoStyle.Render(msg)
+	return plain(CurrentTheme().Info(msg))
 }
 
 // Header renders a header
 func Header(msg string) string {
-	return HeaderStyle.Render(msg)
+	return plain(CurrentTheme().Header(msg))
 }
 
 // SubHeader renders a sub-header
 func SubHeader(msg string) string {
-	return SubHeaderStyle.Render(msg)
+	return plain(CurrentTheme().SubHeader(msg))
 }
 
 // Command renders a command name
 func Command(cmd string) string {
-	return CommandStyle.Render(cmd)
+	return plain(CurrentTheme().Command(cmd))
 }
 
 // Flag renders a flag
 func Flag(flag string) string {
-	return FlagStyle.Render(flag)
+	return plain(CurrentTheme().Flag(flag))
 }
 
 // Argument renders an argument
 func Argument(arg string) string {
-	return ArgumentStyle.Render(arg)
+	return plain(CurrentTheme().Argument(arg))
 }
 
 // Code renders code or technical text
 func Code(code string) string {
-	return CodeStyle.Render(code)
+	return plain(CurrentTheme().Code(code))
 }
 
 // Bullet renders a bullet point
 func Bullet(msg string) string {
-	return BulletStyle.Render(BulletIcon+" ") + ListItemStyle.Render(msg)
+	return plain(CurrentTheme().Bullet(msg))
 }
 
 // Box renders text in a box
 func Box(title, content string) string {
-	if title != "" {
-		title = HeaderStyle.Render(title) + "\n\n"
-	}
-	return BoxStyle.Render(title + content)
+	return plain(CurrentTheme().Box(title, content))
 }
 
 // HighlightBox renders text in a highlighted box
 func HighlightBox(title, content string) string {
-	if title != "" {
-		title = HeaderStyle.Render(title) + "\n\n"
-	}
-	return HighlightBoxStyle.Render(title + content)
+	return plain(CurrentTheme().HighlightBox(title, content))
 }
 
 // Bold renders bold text
 func Bold(msg string) string {
-	return BoldStyle.Render(msg)
+	return plain(BoldStyle.Render(msg))
 }
 
 // Italic renders italic text
 func Italic(msg string) string {
-	return ItalicStyle.Render(msg)
+	return plain(ItalicStyle.Render(msg))
 }
 
 // Underline renders underlined text
 func Underline(msg string) string {
-	return UnderlineStyle.Render(msg)
+	return plain(UnderlineStyle.Render(msg))
 }
 
 // Dim renders dimmed text
 func Dim(msg string) string {
-	return DimStyle.Render(msg)
+	return plain(CurrentTheme().Dim(msg))
 }
 
 // Muted renders muted text
 func Muted(msg string) string {
-	return MutedStyle.Render(msg)
+	return plain(CurrentTheme().Muted(msg))
 }
 
 // Prompt renders a prompt
 func Prompt(msg string) string {
-	return PromptStyle.Render(msg + " " + ArrowIcon + " ")
+	return plain(CurrentTheme().Prompt(msg))
 }
 
 // Input renders user input
 func Input(msg string) string {
-	return InputStyle.Render(msg)
+	return plain(CurrentTheme().Input(msg))
 }
 
 // Colorize applies a color to text
 func Colorize(msg string, color lipgloss.Color) string {
-	return lipgloss.NewStyle().Foreground(color).Render(msg)
+	return plain(lipgloss.NewStyle().Foreground(color).Render(msg))
 }
 
 // WithBackground applies a background color to text
 func WithBackground(msg string, fg, bg lipgloss.Color) string {
-	return lipgloss.NewStyle().
-		Foreground(fg).
-		Background(bg).
-		Render(msg)
+	return plain(lipgloss.NewStyle().Foreground(fg).Background(bg).Render(msg))
 }
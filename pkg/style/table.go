@@ -0,0 +1,97 @@
+package style
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+// TableOptions configures table rendering
+type TableOptions struct {
+	// Compact renders the table without borders
+	Compact bool
+	// MaxColumnWidth truncates cells wider than this many cells with an
+	// ellipsis; zero means no limit
+	MaxColumnWidth int
+}
+
+// Table renders rows as an aligned table with a styled header row
+func Table(headers []string, rows [][]string) string {
+	return plain(CurrentTheme().Table(headers, rows))
+}
+
+// TableWithOptions renders rows as an aligned table using opts
+func TableWithOptions(headers []string, rows [][]string, opts TableOptions) string {
+	return plain(CurrentTheme().TableWithOptions(headers, rows, opts))
+}
+
+// Table renders rows as an aligned table with a styled header row
+func (t Theme) Table(headers []string, rows [][]string) string {
+	return t.TableWithOptions(headers, rows, TableOptions{})
+}
+
+// TableWithOptions renders rows as an aligned table using opts.
+// Rows with fewer cells than headers are padded with empty cells.
+func (t Theme) TableWithOptions(headers []string, rows [][]string, opts TableOptions) string {
+	columns := len(headers)
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+
+	padded := make([][]string, len(rows))
+	for i, row := range rows {
+		padded[i] = truncateRow(padRow(row, columns), opts.MaxColumnWidth)
+	}
+
+	s := t.Styles()
+	cell := lipgloss.NewStyle().Padding(0, 1)
+	header := s.SubHeader.Padding(0, 1)
+
+	tbl := table.New().
+		Headers(truncateRow(padRow(headers, columns), opts.MaxColumnWidth)...).
+		Rows(padded...).
+		Border(lipgloss.RoundedBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(t.DimColor)).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return header
+			}
+			return cell
+		})
+
+	if opts.Compact {
+		tbl = tbl.
+			BorderTop(false).
+			BorderBottom(false).
+			BorderLeft(false).
+			BorderRight(false).
+			BorderHeader(false).
+			BorderColumn(false)
+	}
+
+	return tbl.Render()
+}
+
+// padRow returns row extended with empty cells to the given number of columns
+func padRow(row []string, columns int) []string {
+	if len(row) >= columns {
+		return row
+	}
+	padded := make([]string, columns)
+	copy(padded, row)
+	return padded
+}
+
+// truncateRow returns row with every cell truncated to max cells, or row
+// itself when max is zero
+func truncateRow(row []string, max int) []string {
+	if max <= 0 {
+		return row
+	}
+	truncated := make([]string, len(row))
+	for i, cell := range row {
+		truncated[i] = Truncate(cell, max)
+	}
+	return truncated
+}
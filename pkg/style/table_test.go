@@ -0,0 +1,88 @@
+package style
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestTable(t *testing.T) {
+	headers := []string{"Name", "Status"}
+	rows := [][]string{
+		{"api", "running"},
+		{"worker-pool", "stopped"},
+		{"日本語", "ok"},
+	}
+
+	result := Table(headers, rows)
+
+	for _, want := range []string{"Name", "Status", "api", "worker-pool", "日本語", "stopped"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Table() should contain %q, got:\n%s", want, result)
+		}
+	}
+
+	lines := strings.Split(result, "\n")
+	width := lipgloss.Width(lines[0])
+	for _, line := range lines {
+		if lipgloss.Width(line) != width {
+			t.Errorf("Table() lines should be aligned to width %d, got %d for %q", width, lipgloss.Width(line), line)
+		}
+	}
+
+	// Cells in the same column start at the same offset
+	apiLine, workerLine := lineContaining(lines, "api"), lineContaining(lines, "worker-pool")
+	if strings.Index(apiLine, "running") != strings.Index(workerLine, "stopped") {
+		t.Errorf("Table() columns should be aligned:\n%s\n%s", apiLine, workerLine)
+	}
+}
+
+func TestTableRaggedRows(t *testing.T) {
+	result := Table([]string{"A", "B", "C"}, [][]string{{"1"}, {"1", "2", "3"}})
+
+	lines := strings.Split(result, "\n")
+	width := lipgloss.Width(lines[0])
+	for _, line := range lines {
+		if lipgloss.Width(line) != width {
+			t.Errorf("Table() should pad ragged rows, got:\n%s", result)
+			break
+		}
+	}
+}
+
+func TestTableCompact(t *testing.T) {
+	result := TableWithOptions([]string{"Name"}, [][]string{{"api"}}, TableOptions{Compact: true})
+
+	if strings.ContainsAny(result, "╭│─") {
+		t.Errorf("Compact table should not contain borders, got:\n%s", result)
+	}
+	if !strings.Contains(result, "api") {
+		t.Errorf("Compact table should contain cells, got:\n%s", result)
+	}
+}
+
+func TestTableMaxColumnWidth(t *testing.T) {
+	withUnicode(t, true)
+	result := TableWithOptions(
+		[]string{"Name", "Image"},
+		[][]string{{"api", "registry.example.com/api:1.4.2"}},
+		TableOptions{MaxColumnWidth: 12},
+	)
+
+	if !strings.Contains(result, "registry.ex…") {
+		t.Errorf("Table should truncate long cells, got:\n%s", result)
+	}
+	if strings.Contains(result, "api:1.4.2") {
+		t.Errorf("Table should not contain the cut text, got:\n%s", result)
+	}
+}
+
+func lineContaining(lines []string, s string) string {
+	for _, line := range lines {
+		if strings.Contains(line, s) {
+			return line
+		}
+	}
+	return ""
+}
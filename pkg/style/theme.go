@@ -0,0 +1,377 @@
+package style
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Icons holds the glyphs used by the status and list render functions
+type Icons struct {
+	Success  string
+	Error    string
+	Warning  string
+	Info     string
+	Question string
+	Arrow    string
+	Bullet   string
+	Check    string
+	Cross    string
+}
+
+// Theme holds the colors and icons used by the render functions.
+//
+// Example:
+//
+//	theme := style.DefaultTheme()
+//	theme.PrimaryColor = lipgloss.Color("#FF5F87")
+//	style.SetTheme(theme)
+type Theme struct {
+	// Primary colors
+	PrimaryColor   lipgloss.Color
+	SecondaryColor lipgloss.Color
+	AccentColor    lipgloss.Color
+
+	// Status colors
+	SuccessColor lipgloss.Color
+	ErrorColor   lipgloss.Color
+	WarningColor lipgloss.Color
+	InfoColor    lipgloss.Color
+
+	// Text colors
+	TextColor       lipgloss.Color
+	MutedColor      lipgloss.Color
+	HighlightColor  lipgloss.Color
+	DimColor        lipgloss.Color
+	SubtleColor     lipgloss.Color
+	BrightTextColor lipgloss.Color
+
+	// Code and panel colors
+	CodeColor       lipgloss.Color
+	BackgroundColor lipgloss.Color
+
+	// Icons used by status messages, bullets, and prompts
+	Icons Icons
+}
+
+// Styles holds the lipgloss styles derived from a Theme
+type Styles struct {
+	Dim          lipgloss.Style
+	Muted        lipgloss.Style
+	Header       lipgloss.Style
+	SubHeader    lipgloss.Style
+	Success      lipgloss.Style
+	Error        lipgloss.Style
+	Warning      lipgloss.Style
+	Info         lipgloss.Style
+	Box          lipgloss.Style
+	HighlightBox lipgloss.Style
+	Command      lipgloss.Style
+	Flag         lipgloss.Style
+	Argument     lipgloss.Style
+	Bullet       lipgloss.Style
+	ListItem     lipgloss.Style
+	Code         lipgloss.Style
+	Prompt       lipgloss.Style
+	Input        lipgloss.Style
+}
+
+// DefaultTheme returns Mamba's default purple and cyan theme
+func DefaultTheme() Theme {
+	return Theme{
+		PrimaryColor:   lipgloss.Color("#7C3AED"), // Purple
+		SecondaryColor: lipgloss.Color("#06B6D4"), // Cyan
+		AccentColor:    lipgloss.Color("#F59E0B"), // Amber
+
+		SuccessColor: lipgloss.Color("#10B981"), // Green
+		ErrorColor:   lipgloss.Color("#EF4444"), // Red
+		WarningColor: lipgloss.Color("#F59E0B"), // Amber
+		InfoColor:    lipgloss.Color("#3B82F6"), // Blue
+
+		TextColor:       lipgloss.Color("#F3F4F6"), // Light gray
+		MutedColor:      lipgloss.Color("#9CA3AF"), // Gray
+		HighlightColor:  lipgloss.Color("#FBBF24"), // Yellow
+		DimColor:        lipgloss.Color("#6B7280"), // Dark gray
+		SubtleColor:     lipgloss.Color("#4B5563"), // Darker gray
+		BrightTextColor: lipgloss.Color("#FFFFFF"), // White
+
+		CodeColor:       lipgloss.Color("#A78BFA"), // Light purple
+		BackgroundColor: lipgloss.Color("#1F2937"), // Charcoal
+
+		Icons: Icons{
+			Success:  SuccessIcon,
+			Error:    ErrorIcon,
+			Warning:  WarningIcon,
+			Info:     InfoIcon,
+			Question: QuestionIcon,
+			Arrow:    ArrowIcon,
+			Bullet:   BulletIcon,
+			Check:    CheckIcon,
+			Cross:    CrossIcon,
+		},
+	}
+}
+
+// HighContrastTheme returns a theme of bright, saturated colors on plain
+// text for low-vision users and washed-out displays
+func HighContrastTheme() Theme {
+	t := DefaultTheme()
+	t.PrimaryColor = lipgloss.Color("#FF00FF")   // Magenta
+	t.SecondaryColor = lipgloss.Color("#00FFFF") // Cyan
+	t.AccentColor = lipgloss.Color("#FFFF00")    // Yellow
+
+	t.SuccessColor = lipgloss.Color("#00FF00") // Green
+	t.ErrorColor = lipgloss.Color("#FF0000")   // Red
+	t.WarningColor = lipgloss.Color("#FFFF00") // Yellow
+	t.InfoColor = lipgloss.Color("#00FFFF")    // Cyan
+
+	t.TextColor = lipgloss.Color("#FFFFFF")      // White
+	t.MutedColor = lipgloss.Color("#E0E0E0")     // Near white
+	t.HighlightColor = lipgloss.Color("#FFFF00") // Yellow
+	t.DimColor = lipgloss.Color("#C0C0C0")       // Silver
+	t.SubtleColor = lipgloss.Color("#A0A0A0")    // Light gray
+
+	t.CodeColor = lipgloss.Color("#FFFFFF")       // White
+	t.BackgroundColor = lipgloss.Color("#000000") // Black
+	return t
+}
+
+// DeuteranopiaTheme returns a theme built from the Okabe-Ito palette, whose
+// status colors stay distinguishable with red-green color blindness
+func DeuteranopiaTheme() Theme {
+	t := DefaultTheme()
+	t.PrimaryColor = lipgloss.Color("#0072B2")   // Blue
+	t.SecondaryColor = lipgloss.Color("#56B4E9") // Sky blue
+	t.AccentColor = lipgloss.Color("#E69F00")    // Orange
+
+	t.SuccessColor = lipgloss.Color("#56B4E9") // Sky blue
+	t.ErrorColor = lipgloss.Color("#D55E00")   // Vermillion
+	t.WarningColor = lipgloss.Color("#F0E442") // Yellow
+	t.InfoColor = lipgloss.Color("#CC79A7")    // Reddish purple
+
+	t.HighlightColor = lipgloss.Color("#F0E442") // Yellow
+	t.CodeColor = lipgloss.Color("#56B4E9")      // Sky blue
+	return t
+}
+
+// themes are the built-in themes selectable by name with MAMBA_THEME
+var themes = map[string]func() Theme{
+	"default":       DefaultTheme,
+	"high-contrast": HighContrastTheme,
+	"deuteranopia":  DeuteranopiaTheme,
+}
+
+// ThemeByName returns the built-in theme called name: "default",
+// "high-contrast", or "deuteranopia"
+func ThemeByName(name string) (Theme, bool) {
+	theme, ok := themes[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Theme{}, false
+	}
+	return theme(), true
+}
+
+var (
+	themeMu      sync.RWMutex
+	currentTheme = DefaultTheme()
+)
+
+func init() {
+	applyThemeEnv()
+}
+
+// applyThemeEnv selects the built-in theme named by the MAMBA_THEME
+// environment variable. Unknown names are ignored.
+func applyThemeEnv() {
+	if theme, ok := ThemeByName(os.Getenv("MAMBA_THEME")); ok {
+		SetTheme(theme)
+	}
+}
+
+// SetTheme sets the theme used by the package-level render functions and
+// updates the exported color and style variables to match
+func SetTheme(t Theme) {
+	themeMu.Lock()
+	currentTheme = t
+	themeMu.Unlock()
+
+	applyTheme(t)
+}
+
+// CurrentTheme returns the active theme
+func CurrentTheme() Theme {
+	themeMu.RLock()
+	defer themeMu.RUnlock()
+	return currentTheme
+}
+
+// Styles returns the lipgloss styles derived from the theme
+func (t Theme) Styles() Styles {
+	return Styles{
+		Dim:   lipgloss.NewStyle().Foreground(t.DimColor),
+		Muted: lipgloss.NewStyle().Foreground(t.MutedColor),
+
+		Header: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(t.PrimaryColor).
+			MarginBottom(1),
+
+		SubHeader: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(t.SecondaryColor),
+
+		Success: lipgloss.NewStyle().
+			Foreground(t.SuccessColor).
+			Bold(true),
+
+		Error: lipgloss.NewStyle().
+			Foreground(t.ErrorColor).
+			Bold(true),
+
+		Warning: lipgloss.NewStyle().
+			Foreground(t.WarningColor).
+			Bold(true),
+
+		Info: lipgloss.NewStyle().
+			Foreground(t.InfoColor).
+			Bold(true),
+
+		Box: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(t.PrimaryColor).
+			Padding(1, 2),
+
+		HighlightBox: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(t.AccentColor).
+			Padding(1, 2).
+			Background(t.BackgroundColor),
+
+		Command: lipgloss.NewStyle().
+			Foreground(t.AccentColor).
+			Bold(true),
+
+		Flag: lipgloss.NewStyle().
+			Foreground(t.InfoColor),
+
+		Argument: lipgloss.NewStyle().
+			Foreground(t.HighlightColor).
+			Italic(true),
+
+		Bullet: lipgloss.NewStyle().
+			Foreground(t.PrimaryColor).
+			Bold(true),
+
+		ListItem: lipgloss.NewStyle().
+			Foreground(t.TextColor).
+			PaddingLeft(2),
+
+		Code: lipgloss.NewStyle().
+			Foreground(t.CodeColor).
+			Background(t.BackgroundColor).
+			Padding(0, 1),
+
+		Prompt: lipgloss.NewStyle().
+			Foreground(t.PrimaryColor).
+			Bold(true),
+
+		Input: lipgloss.NewStyle().
+			Foreground(t.AccentColor),
+	}
+}
+
+// Success renders a success message
+func (t Theme) Success(msg string) string {
+	s := t.Styles().Success
+	return s.Render(t.icons().Success+" ") + s.Render(msg)
+}
+
+// Error renders an error message
+func (t Theme) Error(msg string) string {
+	s := t.Styles().Error
+	return s.Render(t.icons().Error+" ") + s.Render(msg)
+}
+
+// Warning renders a warning message
+func (t Theme) Warning(msg string) string {
+	s := t.Styles().Warning
+	return s.Render(t.icons().Warning+" ") + s.Render(msg)
+}
+
+// Info renders an info message
+func (t Theme) Info(msg string) string {
+	s := t.Styles().Info
+	return s.Render(t.icons().Info+" ") + s.Render(msg)
+}
+
+// Header renders a header
+func (t Theme) Header(msg string) string {
+	return t.Styles().Header.Render(msg)
+}
+
+// SubHeader renders a sub-header
+func (t Theme) SubHeader(msg string) string {
+	return t.Styles().SubHeader.Render(msg)
+}
+
+// Command renders a command name
+func (t Theme) Command(cmd string) string {
+	return t.Styles().Command.Render(cmd)
+}
+
+// Flag renders a flag
+func (t Theme) Flag(flag string) string {
+	return t.Styles().Flag.Render(flag)
+}
+
+// Argument renders an argument
+func (t Theme) Argument(arg string) string {
+	return t.Styles().Argument.Render(arg)
+}
+
+// Code renders code or technical text
+func (t Theme) Code(code string) string {
+	return t.Styles().Code.Render(code)
+}
+
+// Bullet renders a bullet point
+func (t Theme) Bullet(msg string) string {
+	s := t.Styles()
+	return s.Bullet.Render(t.icons().Bullet+" ") + s.ListItem.Render(msg)
+}
+
+// Box renders text in a box
+func (t Theme) Box(title, content string) string {
+	return t.BoxWithOptions(title, content, BoxOptions{})
+}
+
+// HighlightBox renders text in a highlighted box
+func (t Theme) HighlightBox(title, content string) string {
+	s := t.Styles()
+	if title != "" {
+		title = s.Header.Render(title) + "\n\n"
+	}
+	return s.HighlightBox.Render(title + content)
+}
+
+// Dim renders dimmed text
+func (t Theme) Dim(msg string) string {
+	return t.Styles().Dim.Render(msg)
+}
+
+// Muted renders muted text
+func (t Theme) Muted(msg string) string {
+	return t.Styles().Muted.Render(msg)
+}
+
+// Prompt renders a prompt
+func (t Theme) Prompt(msg string) string {
+	return t.Styles().Prompt.Render(msg + " " + t.icons().Arrow + " ")
+}
+
+// Input renders user input
+func (t Theme) Input(msg string) string {
+	return t.Styles().Input.Render(msg)
+}
@@ -0,0 +1,113 @@
+package style
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestDefaultTheme(t *testing.T) {
+	theme := DefaultTheme()
+	if theme.PrimaryColor != lipgloss.Color("#7C3AED") {
+		t.Errorf("DefaultTheme() primary color = %v", theme.PrimaryColor)
+	}
+	if theme.Icons.Success != SuccessIcon {
+		t.Errorf("DefaultTheme() success icon = %q, want %q", theme.Icons.Success, SuccessIcon)
+	}
+}
+
+func TestBuiltinThemesChangeStatusColors(t *testing.T) {
+	def := DefaultTheme()
+	for name, theme := range map[string]Theme{
+		"high-contrast": HighContrastTheme(),
+		"deuteranopia":  DeuteranopiaTheme(),
+	} {
+		for _, c := range []struct {
+			status    string
+			got, base lipgloss.Color
+		}{
+			{"success", theme.SuccessColor, def.SuccessColor},
+			{"error", theme.ErrorColor, def.ErrorColor},
+			{"warning", theme.WarningColor, def.WarningColor},
+			{"info", theme.InfoColor, def.InfoColor},
+		} {
+			if c.got == c.base {
+				t.Errorf("%s %s color = %v, should differ from the default", name, c.status, c.got)
+			}
+		}
+	}
+}
+
+func TestThemeEnv(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileTrueColor)
+	original := CurrentTheme()
+	defer SetTheme(original)
+
+	t.Setenv("MAMBA_THEME", "Deuteranopia")
+	applyThemeEnv()
+
+	if got := CurrentTheme().ErrorColor; got != DeuteranopiaTheme().ErrorColor {
+		t.Errorf("error color = %v, want the deuteranopia theme's", got)
+	}
+	if ErrorColor != DeuteranopiaTheme().ErrorColor {
+		t.Errorf("ErrorColor = %v, should be updated by the theme", ErrorColor)
+	}
+	if got := Error("failed"); !strings.Contains(got, "38;2;213;94;0") {
+		t.Errorf("Error() should use the deuteranopia vermillion, got: %q", got)
+	}
+
+	t.Setenv("MAMBA_THEME", "unknown")
+	applyThemeEnv()
+	if got := CurrentTheme().ErrorColor; got != DeuteranopiaTheme().ErrorColor {
+		t.Errorf("unknown theme name should be ignored, error color = %v", got)
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileTrueColor)
+	original := CurrentTheme()
+	defer SetTheme(original)
+
+	before := Success("done")
+	if !strings.Contains(before, "38;2;16;185;129") {
+		t.Errorf("Success() should use the default green, got: %q", before)
+	}
+
+	theme := DefaultTheme()
+	theme.SuccessColor = lipgloss.Color("#FF0000")
+	theme.Icons.Success = "+"
+	SetTheme(theme)
+
+	after := Success("done")
+	if !strings.Contains(after, "38;2;255;0;0") {
+		t.Errorf("Success() should use the theme color, got: %q", after)
+	}
+	if !strings.Contains(after, "+") {
+		t.Errorf("Success() should use the theme icon, got: %q", after)
+	}
+	if SuccessColor != lipgloss.Color("#FF0000") {
+		t.Errorf("SetTheme() should update SuccessColor, got %v", SuccessColor)
+	}
+	if CurrentTheme().SuccessColor != theme.SuccessColor {
+		t.Error("CurrentTheme() should return the theme passed to SetTheme")
+	}
+}
+
+func TestThemeRender(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileTrueColor)
+
+	theme := DefaultTheme()
+	theme.PrimaryColor = lipgloss.Color("#0000FF")
+
+	result := theme.Header("Title")
+	if !strings.Contains(result, "38;2;0;0;255") {
+		t.Errorf("Theme.Header() should use the theme primary color, got: %q", result)
+	}
+	if strings.Contains(Header("Title"), "38;2;0;0;255") {
+		t.Error("Rendering with a theme value should not change the active theme")
+	}
+}
@@ -0,0 +1,49 @@
+package style
+
+import "strings"
+
+// TreeNode is a labeled node of a hierarchy rendered by Tree
+type TreeNode struct {
+	Label    string
+	Children []TreeNode
+}
+
+// Tree renders root and its descendants, one label per line, joined by
+// ├──/└── connectors, or |--/`-- when Unicode is disabled. Labels are
+// written as given, so they may be styled by the caller.
+func Tree(root TreeNode) string {
+	return plain(CurrentTheme().Tree(root))
+}
+
+// Tree renders root and its descendants, one label per line, joined by
+// connectors in the theme's dim color
+func (t Theme) Tree(root TreeNode) string {
+	glyphs := treeGlyphs{branch: "├── ", last: "└── ", pipe: "│   "}
+	if !Unicode() {
+		glyphs = treeGlyphs{branch: "|-- ", last: "`-- ", pipe: "|   "}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(root.Label + "\n")
+	writeTreeChildren(&sb, root.Children, "", glyphs, t.Styles().Dim.Render)
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// treeGlyphs holds the connectors drawn before a child and below a non-last
+// child
+type treeGlyphs struct {
+	branch, last, pipe string
+}
+
+// writeTreeChildren writes each child prefixed by the connectors of its
+// ancestors
+func writeTreeChildren(sb *strings.Builder, children []TreeNode, prefix string, glyphs treeGlyphs, dim func(...string) string) {
+	for i, child := range children {
+		connector, indent := glyphs.branch, glyphs.pipe
+		if i == len(children)-1 {
+			connector, indent = glyphs.last, "    "
+		}
+		sb.WriteString(dim(prefix+connector) + child.Label + "\n")
+		writeTreeChildren(sb, child.Children, prefix+indent, glyphs, dim)
+	}
+}
@@ -0,0 +1,68 @@
+package style
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestTree() TreeNode {
+	return TreeNode{
+		Label: "app",
+		Children: []TreeNode{
+			{Label: "cmd", Children: []TreeNode{
+				{Label: "main.go"},
+				{Label: "root.go"},
+			}},
+			{Label: "pkg", Children: []TreeNode{
+				{Label: "style", Children: []TreeNode{{Label: "tree.go"}}},
+			}},
+			{Label: "go.mod"},
+		},
+	}
+}
+
+func TestTree(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileASCII)
+	withUnicode(t, true)
+
+	want := strings.Join([]string{
+		"app",
+		"├── cmd",
+		"│   ├── main.go",
+		"│   └── root.go",
+		"├── pkg",
+		"│   └── style",
+		"│       └── tree.go",
+		"└── go.mod",
+	}, "\n")
+	if got := Tree(newTestTree()); got != want {
+		t.Errorf("Tree() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTreeASCII(t *testing.T) {
+	withColorProfile(t)
+	SetColorProfile(ProfileASCII)
+	withUnicode(t, false)
+
+	want := strings.Join([]string{
+		"app",
+		"|-- cmd",
+		"|   |-- main.go",
+		"|   `-- root.go",
+		"|-- pkg",
+		"|   `-- style",
+		"|       `-- tree.go",
+		"`-- go.mod",
+	}, "\n")
+	if got := Tree(newTestTree()); got != want {
+		t.Errorf("Tree() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTreeLeaf(t *testing.T) {
+	if got := Tree(TreeNode{Label: "alone"}); got != "alone" {
+		t.Errorf("Tree() = %q, want %q", got, "alone")
+	}
+}
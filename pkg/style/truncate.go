@@ -0,0 +1,42 @@
+package style
+
+import "github.com/charmbracelet/x/ansi"
+
+// ellipsis returns the marker for cut text, "..." when Unicode is disabled
+func ellipsis() string {
+	if Unicode() {
+		return "…"
+	}
+	return "..."
+}
+
+// Truncate cuts s to at most max terminal cells and marks the cut with an
+// ellipsis. Wide runes count as two cells, and ANSI escape sequences are kept
+// whole. s is returned unchanged when it fits.
+func Truncate(s string, max int) string {
+	if ansi.StringWidth(s) <= max {
+		return s
+	}
+	tail := ellipsis()
+	if max <= ansi.StringWidth(tail) {
+		return ansi.Truncate(s, max, "")
+	}
+	return ansi.Truncate(s, max, tail)
+}
+
+// TruncateMiddle is like Truncate but keeps both ends of s and puts the
+// ellipsis in the middle, which suits paths and identifiers
+func TruncateMiddle(s string, max int) string {
+	width := ansi.StringWidth(s)
+	if width <= max {
+		return s
+	}
+	tail := ellipsis()
+	keep := max - ansi.StringWidth(tail)
+	if keep <= 0 {
+		return ansi.Truncate(s, max, "")
+	}
+	left := (keep + 1) / 2
+	right := keep - left
+	return ansi.Truncate(s, left, "") + tail + ansi.TruncateLeft(s, width-right, "")
+}
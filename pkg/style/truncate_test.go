@@ -0,0 +1,80 @@
+package style
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestTruncate(t *testing.T) {
+	withUnicode(t, true)
+
+	tests := []struct {
+		name string
+		s    string
+		max  int
+		want string
+	}{
+		{"fits", "deploy", 6, "deploy"},
+		{"ascii", "deployment", 7, "deploy…"},
+		{"cjk", "日本語テキスト", 7, "日本語…"},
+		{"cjk no half rune", "日本語テキスト", 6, "日本…"},
+		{"tiny", "deployment", 1, "d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.s, tt.max)
+			if got != tt.want {
+				t.Errorf("Truncate(%q, %d) = %q, want %q", tt.s, tt.max, got, tt.want)
+			}
+			if w := ansi.StringWidth(got); w > tt.max {
+				t.Errorf("Truncate(%q, %d) is %d cells wide", tt.s, tt.max, w)
+			}
+		})
+	}
+}
+
+func TestTruncateANSI(t *testing.T) {
+	withUnicode(t, true)
+
+	s := "\x1b[31mproduction\x1b[0m"
+	got := Truncate(s, 5)
+	if stripped := ansi.Strip(got); stripped != "prod…" {
+		t.Errorf("Truncate() text = %q, want %q", stripped, "prod…")
+	}
+	if got[:5] != "\x1b[31m" {
+		t.Errorf("Truncate() = %q, want the color sequence kept whole", got)
+	}
+}
+
+func TestTruncateASCII(t *testing.T) {
+	withUnicode(t, false)
+
+	if got, want := Truncate("deployment", 7), "depl..."; got != want {
+		t.Errorf("Truncate() = %q, want %q", got, want)
+	}
+}
+
+func TestTruncateMiddle(t *testing.T) {
+	withUnicode(t, true)
+
+	tests := []struct {
+		s    string
+		max  int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"/usr/local/share/mamba", 11, "/usr/…mamba"},
+		{"日本語テキスト", 9, "日本…スト"},
+	}
+	for _, tt := range tests {
+		if got := TruncateMiddle(tt.s, tt.max); got != tt.want {
+			t.Errorf("TruncateMiddle(%q, %d) = %q, want %q", tt.s, tt.max, got, tt.want)
+		}
+	}
+
+	got := TruncateMiddle("\x1b[1m/usr/local/share/mamba\x1b[0m", 11)
+	if stripped := ansi.Strip(got); stripped != "/usr/…mamba" {
+		t.Errorf("TruncateMiddle() text = %q, want %q", stripped, "/usr/…mamba")
+	}
+}
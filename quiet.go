@@ -0,0 +1,22 @@
+package mamba
+
+// initDefaultQuietFlag adds a persistent --quiet/-q flag to a root command
+// with EnableQuiet set. The -q shorthand is only used if it is free.
+func (c *Command) initDefaultQuietFlag() {
+	if !c.EnableQuiet || c.PersistentFlags().Lookup("quiet") != nil {
+		return
+	}
+
+	usage := "only print errors and data output"
+	if c.PersistentFlags().ShorthandLookup("q") == nil && c.LocalFlags().ShorthandLookup("q") == nil {
+		c.PersistentFlags().BoolP("quiet", "q", false, usage)
+	} else {
+		c.PersistentFlags().Bool("quiet", false, usage)
+	}
+}
+
+// Quiet reports whether --quiet was passed to the command or an ancestor, in
+// which case messages, spinners, and progress bars are suppressed
+func (c *Command) Quiet() bool {
+	return c.inheritedFlagSet("quiet")
+}
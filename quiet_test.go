@@ -0,0 +1,93 @@
+package mamba
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/base-go/mamba/pkg/spinner"
+)
+
+func TestCommand_Quiet(t *testing.T) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	var quiet, spinnerQuiet bool
+	rootCmd := &Command{Use: "app", EnableQuiet: true}
+	syncCmd := &Command{
+		Use: "sync",
+		Run: func(cmd *Command, args []string) {
+			quiet = cmd.Quiet()
+			spinnerQuiet = spinner.Quiet()
+			cmd.PrintHeader("Syncing")
+			cmd.PrintInfo("Fetched 3 remotes")
+			cmd.PrintSuccess("Synced")
+			cmd.PrintError("1 remote unreachable")
+		},
+	}
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.SetOutput(stdout)
+	rootCmd.SetErr(stderr)
+
+	if err := rootCmd.execute([]string{"sync", "-q"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if !quiet || !spinnerQuiet {
+		t.Errorf("Quiet() = %v, spinner.Quiet() = %v, want both true for a subcommand", quiet, spinnerQuiet)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("quiet mode should suppress messages, got: %q", stdout.String())
+	}
+	if stderr.Len() == 0 {
+		t.Error("PrintError should still write in quiet mode")
+	}
+	if spinner.Quiet() {
+		t.Error("spinner quiet mode should be reset after the command")
+	}
+}
+
+func TestCommand_QuietNotSet(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd := &Command{
+		Use:         "app",
+		EnableQuiet: true,
+		Run: func(cmd *Command, args []string) {
+			cmd.PrintInfo("Fetched 3 remotes")
+		},
+	}
+	rootCmd.SetOutput(buf)
+
+	if err := rootCmd.execute([]string{}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if rootCmd.Quiet() || buf.Len() == 0 {
+		t.Errorf("PrintInfo should write without --quiet, got: %q", buf.String())
+	}
+}
+
+func TestCommand_QuietOptIn(t *testing.T) {
+	rootCmd := &Command{Use: "app", Run: func(cmd *Command, args []string) {}}
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	if err := rootCmd.execute([]string{"--quiet"}); err == nil {
+		t.Error("--quiet should be an unknown flag without EnableQuiet")
+	}
+}
+
+func TestCommand_QuietKeepsData(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd := &Command{
+		Use:         "app",
+		EnableQuiet: true,
+		Run: func(cmd *Command, args []string) {
+			cmd.PrintTable([]string{"NAME"}, [][]string{{"api"}})
+		},
+	}
+	rootCmd.SetOutput(buf)
+
+	if err := rootCmd.execute([]string{"--quiet"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "api") {
+		t.Errorf("PrintTable should still write in quiet mode, got: %q", buf.String())
+	}
+}
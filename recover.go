@@ -0,0 +1,45 @@
+package mamba
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// recoverPanicsEnabled reports whether RecoverPanics is set on the command
+// or any of its ancestors
+func (c *Command) recoverPanicsEnabled() bool {
+	for p := c; p != nil; p = p.parent {
+		if p.RecoverPanics {
+			return true
+		}
+	}
+	return false
+}
+
+// recoverPanic turns a panic into an error stored in err and prints it in an
+// error box, with the stack trace when --debug is set. It must be deferred.
+func (c *Command) recoverPanic(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	*err = fmt.Errorf("panic: %v", r)
+
+	if c.SilenceErrors {
+		return
+	}
+
+	t := c.Theme()
+	content := t.Error("Unexpected error") + "\n\n" + fmt.Sprint(r)
+	if c.debugFlagSet() {
+		content += "\n\n" + t.Dim(string(debug.Stack()))
+	}
+	box := t.Styles().Box.BorderForeground(t.ErrorColor)
+	fmt.Fprintln(c.ErrOrStderr(), c.render(c.ErrOrStderr(), box.Render(content)))
+}
+
+// debugFlagSet reports whether a --debug flag is defined and set
+func (c *Command) debugFlagSet() bool {
+	flag := c.Flags().Lookup("debug")
+	return flag != nil && flag.Value.String() == "true"
+}
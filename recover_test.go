@@ -0,0 +1,58 @@
+package mamba
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCommand_RecoverPanics(t *testing.T) {
+	stderr := new(bytes.Buffer)
+	rootCmd := &Command{Use: "app", RecoverPanics: true}
+	rootCmd.AddCommand(&Command{
+		Use: "deploy",
+		RunE: func(cmd *Command, args []string) error {
+			var m map[string]int
+			m["replicas"] = 3
+			return nil
+		},
+	})
+	rootCmd.SetOutput(new(bytes.Buffer))
+	rootCmd.SetErr(stderr)
+
+	err := rootCmd.execute([]string{"deploy"})
+
+	if err == nil || !strings.Contains(err.Error(), "assignment to entry in nil map") {
+		t.Fatalf("execute() should return the panic as an error, got: %v", err)
+	}
+	output := stderr.String()
+	if !strings.Contains(output, "Unexpected error") || !strings.Contains(output, "╭") {
+		t.Errorf("panic should be printed in an error box, got: %s", output)
+	}
+	if strings.Contains(output, "goroutine") {
+		t.Errorf("stack should be hidden without --debug, got: %s", output)
+	}
+}
+
+func TestCommand_RecoverPanicsDebugStack(t *testing.T) {
+	stderr := new(bytes.Buffer)
+	rootCmd := &Command{Use: "app", RecoverPanics: true}
+	rootCmd.PersistentFlags().Bool("debug", false, "Debug output")
+	rootCmd.AddCommand(&Command{
+		Use: "deploy",
+		RunE: func(cmd *Command, args []string) error {
+			var m map[string]int
+			m["replicas"] = 3
+			return nil
+		},
+	})
+	rootCmd.SetOutput(new(bytes.Buffer))
+	rootCmd.SetErr(stderr)
+
+	if err := rootCmd.execute([]string{"deploy", "--debug"}); err == nil {
+		t.Fatal("execute() should return the panic as an error")
+	}
+	if !strings.Contains(stderr.String(), "goroutine") {
+		t.Errorf("--debug should print the stack, got: %s", stderr.String())
+	}
+}
@@ -0,0 +1,119 @@
+package mamba
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// interruptSignals cancel the execution context
+var interruptSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// interruptGracePeriod is how long a command may keep running after an
+// interrupt before its cleanup runs and the process exits
+var interruptGracePeriod = 3 * time.Second
+
+// interruptExitCode is the exit code after an interrupt, as shells use for
+// SIGINT
+const interruptExitCode = 130
+
+// errInterrupted is the cause of a context canceled by a signal
+var errInterrupted = errors.New("interrupted")
+
+// notifyContext returns a copy of ctx that is canceled on SIGINT or SIGTERM.
+// If the command is still running after interruptGracePeriod, or a second
+// signal arrives, expire is called; it should clean up and exit.
+func notifyContext(ctx context.Context, expire func()) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, interruptSignals...)
+	done := make(chan struct{})
+	grace := interruptGracePeriod
+
+	go func() {
+		select {
+		case <-signals:
+			cancel(errInterrupted)
+		case <-done:
+			return
+		}
+
+		select {
+		case <-signals:
+		case <-time.After(grace):
+		case <-done:
+			return
+		}
+		expire()
+	}()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+			cancel(context.Canceled)
+		})
+	}
+}
+
+// expireInterrupted cleans up the command still running after an interrupt
+// and exits the process
+func (c *Command) expireInterrupted() {
+	c.cleanupMu.Lock()
+	finish := c.onInterrupt
+	c.cleanupMu.Unlock()
+	if finish != nil {
+		finish()
+	}
+	osExit(interruptExitCode)
+}
+
+// setOnInterrupt sets the function expireInterrupted runs before exiting
+func (c *Command) setOnInterrupt(fn func()) {
+	c.cleanupMu.Lock()
+	defer c.cleanupMu.Unlock()
+	c.onInterrupt = fn
+}
+
+// interrupted reports whether the command's context was canceled by a signal
+func (c *Command) interrupted() bool {
+	return errors.Is(context.Cause(c.Context()), errInterrupted)
+}
+
+// SetCleanup registers fn to run once when the command finishes, whether it
+// succeeds, fails, or is interrupted. Cleanups set on ancestors run too,
+// after the command's own. When an interrupted command does not return
+// within a short grace period, its cleanup runs before the process exits.
+func (c *Command) SetCleanup(fn func()) {
+	c.cleanupMu.Lock()
+	defer c.cleanupMu.Unlock()
+	c.cleanup = fn
+}
+
+// finisher returns a function that runs the finalizers and cleanups for cmd
+// once. When interrupted is set it first runs the persistent post-run hooks,
+// which are otherwise skipped when the command fails.
+func (c *Command) finisher(args []string) func(interrupted bool) {
+	var once sync.Once
+	return func(interrupted bool) {
+		once.Do(func() {
+			if interrupted {
+				_ = c.executePersistentPostRun(args)
+			}
+			runFinalizers()
+			for p := c; p != nil; p = p.parent {
+				p.cleanupMu.Lock()
+				cleanup := p.cleanup
+				p.cleanupMu.Unlock()
+				if cleanup != nil {
+					cleanup()
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package mamba
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCommand_ExecuteContextCanceledOnSignal(t *testing.T) {
+	cmd := &Command{
+		Use: "app",
+		RunContextE: func(ctx context.Context, cmd *Command, args []string) error {
+			if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(5 * time.Second):
+				return errors.New("context was not canceled")
+			}
+		},
+	}
+	cmd.SetOutput(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{})
+
+	if err := cmd.ExecuteContext(context.Background()); !errors.Is(err, context.Canceled) {
+		t.Errorf("ExecuteContext() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestCommand_ExecuteContextParentCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var got error
+	cmd := &Command{
+		Use: "app",
+		Run: func(cmd *Command, args []string) {
+			got = cmd.Context().Err()
+		},
+	}
+	cmd.SetArgs([]string{})
+
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+	if !errors.Is(got, context.Canceled) {
+		t.Errorf("Context().Err() = %v, want context.Canceled", got)
+	}
+}
+
+func TestCommand_CleanupOnInterrupt(t *testing.T) {
+	var postRun, cleaned, finalized int
+	OnFinalize(func() { finalized++ })
+	t.Cleanup(func() { finalizers = nil })
+
+	rootCmd := &Command{
+		Use:               "app",
+		PersistentPostRun: func(cmd *Command, args []string) { postRun++ },
+	}
+	rootCmd.AddCommand(&Command{
+		Use: "sync",
+		RunContextE: func(ctx context.Context, cmd *Command, args []string) error {
+			cmd.SetCleanup(func() { cleaned++ })
+			if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+				return err
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+	rootCmd.SetOutput(new(bytes.Buffer))
+	rootCmd.SetErr(new(bytes.Buffer))
+	rootCmd.SetArgs([]string{"sync"})
+
+	if err := rootCmd.ExecuteContext(context.Background()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("ExecuteContext() error = %v, want context.Canceled", err)
+	}
+	if postRun != 1 || cleaned != 1 || finalized != 1 {
+		t.Errorf("after an interrupt PersistentPostRun ran %d, cleanup %d, OnFinalize %d times; want 1 each", postRun, cleaned, finalized)
+	}
+}
+
+func TestCommand_CleanupAfterGracePeriod(t *testing.T) {
+	exited := make(chan int, 1)
+	originalExit := osExit
+	osExit = func(code int) { exited <- code }
+	t.Cleanup(func() { osExit = originalExit })
+	original := interruptGracePeriod
+	interruptGracePeriod = 10 * time.Millisecond
+	t.Cleanup(func() { interruptGracePeriod = original })
+
+	cleaned := make(chan struct{})
+	calls := 0
+	rootCmd := &Command{
+		Use: "app",
+		Run: func(cmd *Command, args []string) {
+			cmd.SetCleanup(func() {
+				calls++
+				close(cleaned)
+			})
+			syscall.Kill(syscall.Getpid(), syscall.SIGINT)
+			// Ignore the context, as a stuck command would
+			select {
+			case <-cleaned:
+			case <-time.After(5 * time.Second):
+			}
+		},
+	}
+	rootCmd.SetOutput(new(bytes.Buffer))
+	rootCmd.SetArgs([]string{})
+
+	if err := rootCmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("ExecuteContext() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("cleanup ran %d times, want once", calls)
+	}
+	if code := <-exited; code != interruptExitCode {
+		t.Errorf("exit code = %d, want %d", code, interruptExitCode)
+	}
+}
+
+func TestCommand_SetCleanup(t *testing.T) {
+	var order []string
+	rootCmd := &Command{Use: "app"}
+	rootCmd.SetCleanup(func() { order = append(order, "app") })
+	rootCmd.AddCommand(&Command{
+		Use: "build",
+		RunE: func(cmd *Command, args []string) error {
+			cmd.SetCleanup(func() { order = append(order, "build") })
+			return errors.New("build failed")
+		},
+	})
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	if err := rootCmd.execute([]string{"build"}); err == nil {
+		t.Fatal("execute() error = nil, want the build error")
+	}
+	if got := strings.Join(order, ","); got != "build,app" {
+		t.Errorf("cleanups ran as %q, want the command's then its ancestors'", got)
+	}
+}
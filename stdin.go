@@ -0,0 +1,23 @@
+package mamba
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxStdinArgsSize caps how much piped input ReadArgsFromStdin reads
+const maxStdinArgsSize = 1 << 20 // 1 MiB
+
+// readArgs reads whitespace-separated arguments from r, failing if the input
+// is larger than maxStdinArgsSize
+func readArgs(r io.Reader) ([]string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxStdinArgsSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading arguments from stdin: %w", err)
+	}
+	if len(data) > maxStdinArgsSize {
+		return nil, fmt.Errorf("arguments from stdin exceed %d bytes", maxStdinArgsSize)
+	}
+	return strings.Fields(string(data)), nil
+}
@@ -0,0 +1,95 @@
+package mamba
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCommand_ReadArgsFromStdin(t *testing.T) {
+	var got []string
+	cmd := &Command{
+		Use:               "process [files...]",
+		ReadArgsFromStdin: true,
+		Args:              MinimumNArgs(1),
+		Run: func(cmd *Command, args []string) {
+			got = args
+		},
+	}
+	cmd.SetOutput(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetIn(strings.NewReader("a.txt b.txt\n  c.txt\n\nd.txt\n"))
+
+	if err := cmd.execute([]string{}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+
+	want := []string{"a.txt", "b.txt", "c.txt", "d.txt"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("args = %q, want %q", got, want)
+	}
+}
+
+func TestCommand_ReadArgsFromStdinIgnoredWithArgs(t *testing.T) {
+	var got []string
+	cmd := &Command{
+		Use:               "process [files...]",
+		ReadArgsFromStdin: true,
+		Args:              MinimumNArgs(1),
+		Run: func(cmd *Command, args []string) {
+			got = args
+		},
+	}
+	cmd.SetOutput(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetIn(strings.NewReader("piped.txt\n"))
+
+	if err := cmd.execute([]string{"given.txt"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if strings.Join(got, ",") != "given.txt" {
+		t.Errorf("args = %q, want only the given argument", got)
+	}
+}
+
+func TestCommand_ReadArgsFromStdinTerminal(t *testing.T) {
+	original := isInputTerminal
+	isInputTerminal = func(r io.Reader) bool { return true }
+	defer func() { isInputTerminal = original }()
+
+	var got []string
+	cmd := &Command{
+		Use:               "process [files...]",
+		ReadArgsFromStdin: true,
+		Args:              MinimumNArgs(1),
+		Run: func(cmd *Command, args []string) {
+			got = args
+		},
+	}
+	cmd.SetOutput(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetIn(strings.NewReader("piped.txt\n"))
+
+	// Without piped input the Args validator sees no arguments
+	if err := cmd.execute([]string{}); err == nil {
+		t.Errorf("execute() should fail without arguments, got args %q", got)
+	}
+}
+
+func TestCommand_ReadArgsFromStdinTooLarge(t *testing.T) {
+	cmd := &Command{
+		Use:               "process [files...]",
+		ReadArgsFromStdin: true,
+		Args:              MinimumNArgs(1),
+		Run:               func(cmd *Command, args []string) {},
+	}
+	cmd.SetOutput(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetIn(strings.NewReader(strings.Repeat("x ", maxStdinArgsSize)))
+
+	err := cmd.execute([]string{})
+	if err == nil || !strings.Contains(err.Error(), "exceed") {
+		t.Errorf("execute() error = %v, want a size limit error", err)
+	}
+}
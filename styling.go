@@ -0,0 +1,16 @@
+package mamba
+
+import "github.com/base-go/mamba/pkg/style"
+
+// DisableStyling turns off styling process-wide: the Print helpers, help,
+// and the package-level style functions print plain text, whether or not
+// output is a terminal. A command that sets EnableColors explicitly still
+// follows its own setting.
+func DisableStyling() {
+	style.SetStyling(false)
+}
+
+// EnableStyling restores styling turned off by DisableStyling
+func EnableStyling() {
+	style.SetStyling(true)
+}
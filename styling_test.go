@@ -0,0 +1,83 @@
+package mamba
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/base-go/mamba/pkg/style"
+)
+
+func TestCommand_DisableStyling(t *testing.T) {
+	originalProfile := style.ColorProfile()
+	style.SetColorProfile(style.ProfileTrueColor)
+	defer style.SetColorProfile(originalProfile)
+
+	buf := new(bytes.Buffer)
+	withTerminalWriters(t, buf)
+	DisableStyling()
+	t.Cleanup(EnableStyling)
+
+	cmd := &Command{Use: "test", Short: "Test command", Run: func(cmd *Command, args []string) {}}
+	cmd.SetOutput(buf)
+	cmd.PrintSuccess("Deployed")
+	cmd.PrintTable([]string{"Name"}, [][]string{{"api"}})
+	cmd.Help()
+
+	if strings.Contains(buf.String(), "\x1b") || !strings.Contains(buf.String(), "Deployed") {
+		t.Errorf("output should be plain with styling disabled, got: %q", buf.String())
+	}
+	if got := style.Success("ok"); strings.Contains(got, "\x1b") {
+		t.Errorf("style.Success() should be plain with styling disabled, got: %q", got)
+	}
+
+	// An explicit EnableColors on the command wins
+	buf.Reset()
+	enabled := true
+	cmd.EnableColors = &enabled
+	cmd.PrintSuccess("Deployed")
+	if !strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("EnableColors should override DisableStyling, got: %q", buf.String())
+	}
+
+	// Turning styling back on restores auto-detection
+	buf.Reset()
+	cmd.EnableColors = nil
+	EnableStyling()
+	cmd.PrintSuccess("Deployed")
+	if !strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("output to a terminal should be styled after EnableStyling, got: %q", buf.String())
+	}
+}
+
+func TestCommand_DisableStylingEnableColorsOverride(t *testing.T) {
+	originalProfile := style.ColorProfile()
+	style.SetColorProfile(style.ProfileTrueColor)
+	defer style.SetColorProfile(originalProfile)
+	DisableStyling()
+	t.Cleanup(EnableStyling)
+
+	buf := new(bytes.Buffer)
+	enabled := true
+	cmd := &Command{Use: "test", EnableColors: &enabled}
+	cmd.SetOutput(buf)
+
+	if err := cmd.PrintJSON(map[string]int{"replicas": 3}); err != nil {
+		t.Fatalf("PrintJSON() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("PrintJSON should be highlighted with EnableColors set, got: %q", buf.String())
+	}
+
+	buf.Reset()
+	cmd.PrintBanner("Mamba")
+	if !strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("PrintBanner should be colored with EnableColors set, got: %q", buf.String())
+	}
+
+	buf.Reset()
+	cmd.PrintLink("docs", "https://example.com")
+	if !strings.Contains(buf.String(), "\x1b]8;;https://example.com") {
+		t.Errorf("PrintLink should be a hyperlink with EnableColors set, got: %q", buf.String())
+	}
+}
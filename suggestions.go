@@ -0,0 +1,103 @@
+package mamba
+
+import (
+	"slices"
+	"strings"
+)
+
+// defaultSuggestionsMinimumDistance is used when SuggestionsMinimumDistance is unset
+const defaultSuggestionsMinimumDistance = 2
+
+// unknownCommandError returns the error for an unmatched subcommand name,
+// with suggestions for similarly named subcommands
+func (c *Command) unknownCommandError(arg string) error {
+	err := &UnknownCommandError{Name: arg, Command: c.CommandPath()}
+	if !c.suggestionsDisabled() {
+		err.Suggestions = c.SuggestionsFor(arg)
+	}
+	return err
+}
+
+// SuggestionsFor returns the visible subcommand names that list typedName in
+// their SuggestFor, followed by those within SuggestionsMinimumDistance edits
+// of typedName or starting with it
+func (c *Command) SuggestionsFor(typedName string) []string {
+	distance := c.suggestionsMinimumDistance()
+
+	var suggestions, similar []string
+	for _, cmd := range c.commands {
+		if cmd.Hidden {
+			continue
+		}
+		name := cmd.Name()
+		switch {
+		case slices.Contains(cmd.SuggestFor, typedName):
+			suggestions = append(suggestions, name)
+		case levenshtein(strings.ToLower(typedName), strings.ToLower(name)) <= distance ||
+			strings.HasPrefix(strings.ToLower(name), strings.ToLower(typedName)):
+			similar = append(similar, name)
+		}
+	}
+	return append(suggestions, similar...)
+}
+
+// suggestedCommand returns the only subcommand that lists name in its
+// SuggestFor, or nil when none or several do
+func (c *Command) suggestedCommand(name string) *Command {
+	var found *Command
+	for _, cmd := range c.commands {
+		if slices.Contains(cmd.SuggestFor, name) {
+			if found != nil {
+				return nil
+			}
+			found = cmd
+		}
+	}
+	return found
+}
+
+// suggestionsDisabled reports whether suggestions are disabled on the command
+// or any of its ancestors
+func (c *Command) suggestionsDisabled() bool {
+	for p := c; p != nil; p = p.parent {
+		if p.DisableSuggestions {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestionsMinimumDistance returns the nearest SuggestionsMinimumDistance
+// set on the command or its ancestors
+func (c *Command) suggestionsMinimumDistance() int {
+	for p := c; p != nil; p = p.parent {
+		if p.SuggestionsMinimumDistance > 0 {
+			return p.SuggestionsMinimumDistance
+		}
+	}
+	return defaultSuggestionsMinimumDistance
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(t)]
+}
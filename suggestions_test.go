@@ -0,0 +1,163 @@
+package mamba
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func newSuggestionTestTree() *Command {
+	rootCmd := &Command{Use: "myapp"}
+	rootCmd.AddCommand(
+		&Command{Use: "stats", Run: func(cmd *Command, args []string) {}},
+		&Command{Use: "status", Run: func(cmd *Command, args []string) {}},
+		&Command{Use: "deploy", Run: func(cmd *Command, args []string) {}},
+	)
+	return rootCmd
+}
+
+func TestCommand_FindUnknownCommandSuggestions(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    []string
+		wantNot []string
+	}{
+		{"close match", "stat", []string{"stats", "status"}, []string{"deploy"}},
+		{"typo", "delpoy", []string{"deploy"}, []string{"stats"}},
+		{"no match", "zzzzzz", nil, []string{"Did you mean this?"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := newSuggestionTestTree().Find([]string{tt.arg})
+			if err == nil {
+				t.Fatalf("Find(%q) should return an error", tt.arg)
+			}
+
+			msg := err.Error()
+			if !strings.HasPrefix(msg, `unknown command "`+tt.arg+`" for "myapp"`) {
+				t.Errorf("unexpected error message: %q", msg)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(msg, "Did you mean this?") || !strings.Contains(msg, "\t"+want) {
+					t.Errorf("error should suggest %q, got: %q", want, msg)
+				}
+			}
+			for _, notWant := range tt.wantNot {
+				if strings.Contains(msg, notWant) {
+					t.Errorf("error should not contain %q, got: %q", notWant, msg)
+				}
+			}
+		})
+	}
+}
+
+func TestCommand_DisableSuggestions(t *testing.T) {
+	rootCmd := newSuggestionTestTree()
+	rootCmd.DisableSuggestions = true
+
+	_, _, err := rootCmd.Find([]string{"stat"})
+	if err == nil {
+		t.Fatal("Find should still return an error with suggestions disabled")
+	}
+	if strings.Contains(err.Error(), "Did you mean this?") {
+		t.Errorf("suggestions should be disabled, got: %q", err.Error())
+	}
+}
+
+func TestCommand_SuggestionsMinimumDistance(t *testing.T) {
+	rootCmd := newSuggestionTestTree()
+
+	if got := rootCmd.SuggestionsFor("dpl"); len(got) != 0 {
+		t.Errorf("SuggestionsFor(dpl) with default distance = %v, want none", got)
+	}
+
+	rootCmd.SuggestionsMinimumDistance = 3
+	if got := rootCmd.SuggestionsFor("dpl"); len(got) != 1 || got[0] != "deploy" {
+		t.Errorf("SuggestionsFor(dpl) with distance 3 = %v, want [deploy]", got)
+	}
+}
+
+func TestCommand_ExecuteUnknownCommand(t *testing.T) {
+	stderr := new(bytes.Buffer)
+	rootCmd := newSuggestionTestTree()
+	rootCmd.SetOutput(new(bytes.Buffer))
+	rootCmd.SetErr(stderr)
+
+	if err := rootCmd.execute([]string{"stat"}); err == nil {
+		t.Fatal("execute() with an unknown command should return an error")
+	}
+	if !strings.Contains(stderr.String(), "Did you mean this?") {
+		t.Errorf("execute() should print suggestions, got: %q", stderr.String())
+	}
+
+	// Commands with an Args validator accept unmatched words as arguments
+	rootCmd.Args = ArbitraryArgs
+	if _, _, err := rootCmd.Find([]string{"stat"}); err != nil {
+		t.Errorf("Find() with Args set should not error, got: %v", err)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"stat", "stats", 1},
+		{"kitten", "sitting", 3},
+		{"héllo", "hello", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCommand_SuggestForRoutes(t *testing.T) {
+	var ran bool
+	rootCmd := &Command{Use: "myapp"}
+	deleteCmd := &Command{
+		Use:        "delete",
+		SuggestFor: []string{"remove", "rm"},
+		Run:        func(cmd *Command, args []string) { ran = true },
+	}
+	rootCmd.AddCommand(deleteCmd)
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	cmd, args, err := rootCmd.Find([]string{"remove", "origin"})
+	if err != nil || cmd != deleteCmd || len(args) != 1 || args[0] != "origin" {
+		t.Errorf("Find(remove origin) = %v, %v, %v; want delete with [origin]", cmd.Name(), args, err)
+	}
+
+	if err := rootCmd.execute([]string{"rm"}); err != nil || !ran {
+		t.Errorf("execute(rm) error = %v, ran = %v; want delete to run", err, ran)
+	}
+
+	if strings.Contains(rootCmd.UsageString(), "remove") {
+		t.Error("SuggestFor words should not be listed in help")
+	}
+}
+
+func TestCommand_SuggestForAmbiguous(t *testing.T) {
+	rootCmd := &Command{Use: "myapp"}
+	rootCmd.AddCommand(
+		&Command{Use: "delete", SuggestFor: []string{"remove"}, Run: func(cmd *Command, args []string) {}},
+		&Command{Use: "prune", SuggestFor: []string{"remove"}, Run: func(cmd *Command, args []string) {}},
+	)
+
+	_, _, err := rootCmd.Find([]string{"remove"})
+	if err == nil {
+		t.Fatal("Find(remove) should fail when several commands list it")
+	}
+	want := "unknown command \"remove\" for \"myapp\"\n\nDid you mean this?\n\tdelete\n\tprune"
+	if got := err.Error(); got != want {
+		t.Errorf("error = %q, want %q", got, want)
+	}
+
+	if got := rootCmd.SuggestionsFor("remove"); len(got) == 0 || got[0] != "delete" {
+		t.Errorf("SuggestionsFor(remove) = %v, want SuggestFor matches first", got)
+	}
+}
@@ -0,0 +1,50 @@
+package mamba
+
+import "github.com/base-go/mamba/pkg/style"
+
+// TreeOptions controls how TreeStringWithOptions renders the command tree
+type TreeOptions struct {
+	// IncludeHidden also lists hidden commands
+	IncludeHidden bool
+}
+
+// TreeString renders the command and its visible subcommands as a tree
+func (c *Command) TreeString() string {
+	return c.TreeStringWithOptions(TreeOptions{})
+}
+
+// TreeStringWithOptions renders the command tree using opts
+func (c *Command) TreeStringWithOptions(opts TreeOptions) string {
+	return c.Theme().Tree(c.treeNode(opts))
+}
+
+// PrintTree prints the command tree
+func (c *Command) PrintTree() {
+	c.printDecorative(c.TreeString())
+}
+
+// PrintTreeWithOptions prints the command tree using opts
+func (c *Command) PrintTreeWithOptions(opts TreeOptions) {
+	c.printDecorative(c.TreeStringWithOptions(opts))
+}
+
+// treeNode builds the tree of c and its subcommands
+func (c *Command) treeNode(opts TreeOptions) style.TreeNode {
+	node := style.TreeNode{Label: c.treeLine()}
+	for _, cmd := range c.commands {
+		if !cmd.Hidden || opts.IncludeHidden {
+			node.Children = append(node.Children, cmd.treeNode(opts))
+		}
+	}
+	return node
+}
+
+// treeLine renders the command's name and short description
+func (c *Command) treeLine() string {
+	t := c.Theme()
+	line := t.Command(c.Name())
+	if c.Short != "" {
+		line += "  " + t.Muted(c.Short)
+	}
+	return line
+}
@@ -0,0 +1,94 @@
+package mamba
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/base-go/mamba/pkg/style"
+)
+
+// withUnicode sets whether glyphs use Unicode for the test
+func withUnicode(t *testing.T, enabled bool) {
+	t.Helper()
+	original := style.Unicode()
+	style.SetUnicode(enabled)
+	t.Cleanup(func() { style.SetUnicode(original) })
+}
+
+func newTreeTestCommand() *Command {
+	rootCmd := &Command{Use: "app", Short: "My application"}
+	deployCmd := &Command{Use: "deploy", Short: "Deploy the app"}
+	deployCmd.AddCommand(
+		&Command{Use: "prod", Short: "Deploy to production"},
+		&Command{Use: "staging", Short: "Deploy to staging"},
+	)
+	rootCmd.AddCommand(
+		deployCmd,
+		&Command{Use: "debug", Short: "Internal tools", Hidden: true},
+		&Command{Use: "status", Short: "Show status"},
+	)
+	return rootCmd
+}
+
+func TestCommand_TreeString(t *testing.T) {
+	withUnicode(t, true)
+	got := newTreeTestCommand().TreeString()
+
+	want := strings.Join([]string{
+		"app  My application",
+		"├── deploy  Deploy the app",
+		"│   ├── prod  Deploy to production",
+		"│   └── staging  Deploy to staging",
+		"└── status  Show status",
+	}, "\n")
+	if got != want {
+		t.Errorf("TreeString() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCommand_TreeStringIncludeHidden(t *testing.T) {
+	withUnicode(t, true)
+	got := newTreeTestCommand().TreeStringWithOptions(TreeOptions{IncludeHidden: true})
+
+	want := strings.Join([]string{
+		"app  My application",
+		"├── deploy  Deploy the app",
+		"│   ├── prod  Deploy to production",
+		"│   └── staging  Deploy to staging",
+		"├── debug  Internal tools",
+		"└── status  Show status",
+	}, "\n")
+	if got != want {
+		t.Errorf("TreeStringWithOptions() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCommand_TreeStringASCII(t *testing.T) {
+	withUnicode(t, false)
+	got := newTreeTestCommand().TreeString()
+
+	want := strings.Join([]string{
+		"app  My application",
+		"|-- deploy  Deploy the app",
+		"|   |-- prod  Deploy to production",
+		"|   `-- staging  Deploy to staging",
+		"`-- status  Show status",
+	}, "\n")
+	if got != want {
+		t.Errorf("TreeString() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCommand_PrintTree(t *testing.T) {
+	withUnicode(t, true)
+	buf := new(bytes.Buffer)
+	cmd := newTreeTestCommand()
+	cmd.SetOutput(buf)
+
+	cmd.PrintTree()
+
+	if !strings.Contains(buf.String(), "└── status") {
+		t.Errorf("PrintTree should print the tree, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,42 @@
+package mamba
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// initDefaultVerboseFlag adds a persistent --verbose/-v count flag to a root
+// command with EnableVerbosity set. The -v shorthand is only used if it is
+// free.
+func (c *Command) initDefaultVerboseFlag() {
+	if !c.EnableVerbosity || c.PersistentFlags().Lookup("verbose") != nil {
+		return
+	}
+
+	usage := "increase verbosity (-v, -vv, -vvv)"
+	if c.PersistentFlags().ShorthandLookup("v") == nil && c.LocalFlags().ShorthandLookup("v") == nil {
+		c.PersistentFlags().CountP("verbose", "v", usage)
+	} else {
+		c.PersistentFlags().Count("verbose", usage)
+	}
+}
+
+// Verbosity returns how many times --verbose was passed to the command or an
+// ancestor, so -vv is 2. It is 0 when the flag is not a count flag.
+func (c *Command) Verbosity() int {
+	flag := c.Flag("verbose")
+	if flag == nil || flag.Value.Type() != "count" {
+		return 0
+	}
+	n, _ := strconv.Atoi(flag.Value.String())
+	return n
+}
+
+// PrintVerbose prints a dimmed diagnostic message to stderr when the
+// verbosity is at least level. Quiet mode suppresses it.
+func (c *Command) PrintVerbose(level int, msg string) {
+	if c.Verbosity() < level || c.Quiet() {
+		return
+	}
+	fmt.Fprintln(c.ErrOrStderr(), c.render(c.ErrOrStderr(), c.Theme().Dim(msg)))
+}
@@ -0,0 +1,69 @@
+package mamba
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCommand_Verbosity(t *testing.T) {
+	stderr := new(bytes.Buffer)
+	rootCmd := &Command{Use: "app", Version: "1.0.0", EnableVerbosity: true}
+	buildCmd := &Command{
+		Use: "build",
+		Run: func(cmd *Command, args []string) {
+			cmd.PrintVerbose(1, "resolving modules")
+			cmd.PrintVerbose(2, "compiling 12 packages")
+			cmd.PrintVerbose(3, "cache key 9f2c")
+		},
+	}
+	rootCmd.AddCommand(buildCmd)
+	rootCmd.SetOutput(new(bytes.Buffer))
+	rootCmd.SetErr(stderr)
+
+	if err := rootCmd.execute([]string{"build", "-vv"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if got := buildCmd.Verbosity(); got != 2 {
+		t.Errorf("Verbosity() = %d, want 2", got)
+	}
+	out := stderr.String()
+	if !strings.Contains(out, "resolving modules") || !strings.Contains(out, "compiling 12 packages") {
+		t.Errorf("level 1 and 2 messages should print at -vv, got: %q", out)
+	}
+	if strings.Contains(out, "cache key") {
+		t.Errorf("level 3 messages should not print at -vv, got: %q", out)
+	}
+}
+
+func TestCommand_VerbosityDefault(t *testing.T) {
+	stderr := new(bytes.Buffer)
+	rootCmd := &Command{Use: "app", Version: "1.0.0", EnableVerbosity: true}
+	buildCmd := &Command{
+		Use: "build",
+		Run: func(cmd *Command, args []string) {
+			cmd.PrintVerbose(1, "resolving modules")
+			cmd.PrintVerbose(2, "compiling 12 packages")
+			cmd.PrintVerbose(3, "cache key 9f2c")
+		},
+	}
+	rootCmd.AddCommand(buildCmd)
+	rootCmd.SetOutput(new(bytes.Buffer))
+	rootCmd.SetErr(stderr)
+
+	if err := rootCmd.execute([]string{"build", "--verbose"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if got := buildCmd.Verbosity(); got != 1 {
+		t.Errorf("Verbosity() = %d, want 1", got)
+	}
+	if strings.Contains(stderr.String(), "compiling") {
+		t.Errorf("level 2 messages should not print at --verbose, got: %q", stderr.String())
+	}
+
+	// -v is taken by verbosity, so the version flag keeps only its long form
+	rootCmd.InitDefaultVersionFlag()
+	if rootCmd.Flags().Lookup("version").Shorthand != "" {
+		t.Error("--version should not claim -v when verbosity is enabled")
+	}
+}
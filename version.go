@@ -0,0 +1,89 @@
+package mamba
+
+import (
+	"fmt"
+	"text/template"
+)
+
+// defaultVersionTemplate is used when no template is set with SetVersionTemplate
+const defaultVersionTemplate = "{{.Name}} version {{.Version}}\n"
+
+// SetVersionTemplate sets the template used by --version and the version
+// subcommand. The template is rendered with text/template against the command.
+func (c *Command) SetVersionTemplate(s string) {
+	c.versionTemplate = s
+}
+
+// VersionTemplate returns the version template, inherited from the nearest
+// ancestor that sets one
+func (c *Command) VersionTemplate() string {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.versionTemplate != "" {
+			return cmd.versionTemplate
+		}
+	}
+	return defaultVersionTemplate
+}
+
+// InitDefaultVersionFlag adds a --version flag to commands that set Version,
+// unless they already have one. The -v shorthand is only used if no other
+// flag has claimed it. Execute calls it automatically.
+func (c *Command) InitDefaultVersionFlag() {
+	if c.Version == "" {
+		return
+	}
+	c.mergePersistentFlags()
+	if c.Flags().Lookup("version") != nil {
+		return
+	}
+
+	usage := "version for " + c.Name()
+	if c.Flags().ShorthandLookup("v") == nil {
+		c.Flags().BoolP("version", "v", false, usage)
+	} else {
+		c.Flags().Bool("version", false, usage)
+	}
+}
+
+// versionFlagSet checks if the version flag was set
+func (c *Command) versionFlagSet() bool {
+	if c.Version == "" {
+		return false
+	}
+	flag := c.Flags().Lookup("version")
+	if flag == nil {
+		return false
+	}
+	return flag.Value.String() == "true"
+}
+
+// printVersion renders the version template to the output
+func (c *Command) printVersion() error {
+	tmpl, err := template.New("version").Parse(c.VersionTemplate())
+	if err != nil {
+		return fmt.Errorf("invalid version template: %w", err)
+	}
+	return tmpl.Execute(c.OutOrStdout(), c)
+}
+
+// initDefaultVersionCmd adds a version subcommand to a root command that sets
+// Version, unless it already has one
+func (c *Command) initDefaultVersionCmd() {
+	if c.Version == "" || !c.HasSubCommands() {
+		return
+	}
+	for _, cmd := range c.commands {
+		if cmd.Name() == "version" {
+			return
+		}
+	}
+
+	c.AddCommand(&Command{
+		Use:   "version",
+		Short: "Print the version number of " + c.Name(),
+		Args:  NoArgs,
+		RunE: func(cmd *Command, args []string) error {
+			return cmd.Root().printVersion()
+		},
+	})
+}
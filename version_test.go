@@ -0,0 +1,121 @@
+package mamba
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestCommand_VersionFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		args     []string
+		want     string
+	}{
+		{"default template", "", []string{"--version"}, "myapp version 1.2.3\n"},
+		{"shorthand", "", []string{"-v"}, "myapp version 1.2.3\n"},
+		{"custom template", "{{.Name}} v{{.Version}} ({{.Short}})\n", []string{"--version"}, "myapp v1.2.3 (My app)\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			ran := false
+			cmd := &Command{
+				Use:     "myapp",
+				Short:   "My app",
+				Version: "1.2.3",
+				Run:     func(cmd *Command, args []string) { ran = true },
+			}
+			if tt.template != "" {
+				cmd.SetVersionTemplate(tt.template)
+			}
+			cmd.SetOutput(buf)
+
+			if err := cmd.execute(tt.args); err != nil {
+				t.Fatalf("execute() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("version output = %q, want %q", buf.String(), tt.want)
+			}
+			if ran {
+				t.Error("Run should not be called when --version is passed")
+			}
+		})
+	}
+}
+
+func TestCommand_VersionFlagShorthandTaken(t *testing.T) {
+	cmd := &Command{Use: "myapp", Version: "1.2.3", Run: func(cmd *Command, args []string) {}}
+	cmd.PersistentFlags().BoolP("verbose", "v", false, "Verbose output")
+	cmd.SetOutput(new(bytes.Buffer))
+
+	if err := cmd.execute([]string{"-v"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if f := cmd.Flags().Lookup("version"); f == nil || f.Shorthand != "" {
+		t.Error("--version should be added without a shorthand when -v is taken")
+	}
+}
+
+func TestCommand_InitDefaultVersionFlag(t *testing.T) {
+	cmd := &Command{Use: "myapp", Version: "1.2.3"}
+
+	cmd.InitDefaultVersionFlag()
+	cmd.InitDefaultVersionFlag()
+
+	count := 0
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Name == "version" {
+			count++
+		}
+	})
+	if count != 1 {
+		t.Errorf("version flags = %d, want 1", count)
+	}
+}
+
+func TestCommand_NoVersionFlagWithoutVersion(t *testing.T) {
+	cmd := &Command{Use: "myapp", Run: func(cmd *Command, args []string) {}}
+	cmd.SetOutput(new(bytes.Buffer))
+
+	if err := cmd.execute([]string{"--version"}); err == nil {
+		t.Error("--version should be an unknown flag when Version is not set")
+	}
+}
+
+func TestCommand_VersionCommand(t *testing.T) {
+	buf := new(bytes.Buffer)
+	rootCmd := &Command{Use: "myapp", Version: "1.2.3"}
+	rootCmd.AddCommand(&Command{Use: "serve", Run: func(cmd *Command, args []string) {}})
+	rootCmd.SetVersionTemplate("{{.Version}}\n")
+	rootCmd.SetOutput(buf)
+
+	if err := rootCmd.execute([]string{"version"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if buf.String() != "1.2.3\n" {
+		t.Errorf("version command output = %q, want %q", buf.String(), "1.2.3\n")
+	}
+
+	// An existing version command is left alone
+	called := false
+	rootCmd = &Command{Use: "myapp", Version: "1.2.3"}
+	rootCmd.AddCommand(&Command{Use: "version", Run: func(cmd *Command, args []string) { called = true }})
+	rootCmd.SetOutput(new(bytes.Buffer))
+
+	if err := rootCmd.execute([]string{"version"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	versions := 0
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == "version" {
+			versions++
+		}
+	}
+	if !called || versions != 1 {
+		t.Error("a user-defined version command should not be replaced")
+	}
+}